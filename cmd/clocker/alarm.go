@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/clock"
+)
+
+const (
+	// AlarmFrequency bounds the wait for the next alarm, so that alarms
+	// still ring soon after the computer wakes up.
+	AlarmFrequency = time.Minute
+	// AlarmTimeFormat is how alarms of the day are entered.
+	AlarmTimeFormat = "15:04"
+)
+
+var alarmWake = make(chan struct{}, 1)
+
+// Alarm reminds of something at a given time, possibly pausing the
+// tracker it's attached to.
+type Alarm struct {
+	At      time.Time `yaml:"at"`
+	Message string    `yaml:"message,omitempty"`
+	Pause   bool      `yaml:"pause,omitempty"`
+}
+
+// parseAlarmTime reads a time of the day, the next one to come, or a full
+// date and time. An empty text clears the alarm.
+func parseAlarmTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if clock, err := time.ParseInLocation(AlarmTimeFormat, s, time.Local); err == nil {
+		at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+	at, err := time.ParseInLocation(SessionTimeFormat, s, time.Local)
+	if err != nil {
+		return at, fmt.Errorf("invalid alarm %q, expected HH:MM or YYYY-MM-DD HH:MM", s)
+	}
+	return at, nil
+}
+
+// formatAlarmTime only gives the day of alarms not due within a day.
+func formatAlarmTime(at, now time.Time) string {
+	if at.Sub(now) < 24*time.Hour {
+		return at.Format(AlarmTimeFormat)
+	}
+	return at.Format(SessionTimeFormat)
+}
+
+// wakeAlarms makes the alarms get checked again, after one got changed.
+func wakeAlarms() {
+	select {
+	case alarmWake <- struct{}{}:
+	default:
+	}
+}
+
+// nextAlarm returns when the first alarm rings.
+func nextAlarm() (time.Time, bool) {
+	next, found := time.Time{}, false
+	for _, t := range trackers {
+		if t.Alarm != nil && (!found || t.Alarm.At.Before(next)) {
+			next, found = t.Alarm.At, true
+		}
+	}
+	return next, found
+}
+
+// ringAlarms notifies of the alarms due, pausing the trackers when asked
+// to, and tells whether any rang.
+func ringAlarms(now time.Time) bool {
+	rang := false
+	for _, t := range trackers {
+		a := t.Alarm
+		if a == nil || a.At.After(now) {
+			continue
+		}
+		t.Alarm = nil
+		rang = true
+		log.Println("Alarm of", t.Label, "at", a.At.Format(AlarmTimeFormat))
+		body := a.Message
+		if body == "" {
+			body = fmt.Sprintf("It's %s.", a.At.Format(AlarmTimeFormat))
+		}
+		if a.Pause && t.Running() {
+			// the session ends when the alarm was due, even if it rings late
+			end := a.At
+			if s := t.Sessions[len(t.Sessions)-1]; end.Before(s.Start) {
+				end = now
+			}
+			t.stop(end)
+			body += "\nThe tracker has been paused."
+		}
+		notifyUser(AlertAlarm, t.Label, body)
+	}
+	return rang
+}
+
+// runAlarms rings alarms on time until ctx is done.
+func runAlarms(ctx context.Context, w fyne.Window, clk clock.Clock) {
+	for {
+		if ringAlarms(clk.Now()) {
+			refreshTray(w)
+			refreshTaskbar()
+			saveConfig()
+		}
+		wait := AlarmFrequency
+		if next, ok := nextAlarm(); ok {
+			wait = min(wait, next.Sub(clk.Now()))
+		}
+		timer := clk.NewTimer(wait)
+		select {
+		case <-timer.C():
+		case <-alarmWake:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestParseAlarmTime(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		text string
+		want time.Time
+	}{
+		{"16:45", time.Date(2026, 3, 2, 16, 45, 0, 0, time.Local)},
+		// already past today
+		{"09:00", time.Date(2026, 3, 3, 9, 0, 0, 0, time.Local)},
+		{"2026-03-10 08:30", time.Date(2026, 3, 10, 8, 30, 0, 0, time.Local)},
+		{"", time.Time{}},
+	}
+	for _, tt := range tests {
+		at, err := parseAlarmTime(tt.text, now)
+		if err != nil || !at.Equal(tt.want) {
+			t.Errorf("parseAlarmTime(%q) = %s, %v, expected %s", tt.text, at, err, tt.want)
+		}
+	}
+	if _, err := parseAlarmTime("teatime", now); err == nil {
+		t.Error("parsed an invalid alarm")
+	}
+}
+
+func TestRingAlarms(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex")
+	acme, globex := trackers[0], trackers[1]
+	tapIcon(t, w, "Start Acme")
+	tapIcon(t, w, "Start Globex")
+	now := time.Now()
+	acme.Alarm = &Alarm{At: now.Add(time.Hour), Message: "Write the summary", Pause: true}
+	globex.Alarm = &Alarm{At: now.Add(2 * time.Hour)}
+
+	if ringAlarms(now) {
+		t.Fatal("rang an alarm before its time")
+	}
+	if next, ok := nextAlarm(); !ok || !next.Equal(acme.Alarm.At) {
+		t.Errorf("next alarm at %s, expected the one of Acme", next)
+	}
+
+	due := acme.Alarm.At
+	if !ringAlarms(due.Add(time.Minute)) {
+		t.Fatal("alarm didn't ring")
+	}
+	if acme.Alarm != nil || globex.Alarm == nil {
+		t.Errorf("alarms left acme=%v globex=%v, expected only the one of Globex", acme.Alarm, globex.Alarm)
+	}
+	if acme.Running() || !acme.Sessions[0].End.Equal(due) {
+		t.Errorf("acme session %+v, expected paused when the alarm was due", acme.Sessions[0])
+	}
+	if !globex.Running() {
+		t.Error("paused a tracker without an alarm due")
+	}
+}
+
+func TestEditTrackerAlarm(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+
+	tapIcon(t, w, "Edit Acme")
+	for _, o := range test.LaidOutObjects(topDialog(t, w)) {
+		if e, ok := o.(*entry); ok && e.PlaceHolder == "16:45" {
+			test.Type(e, "2099-01-01 16:45")
+		}
+	}
+	tapButton(t, w, "Update")
+
+	a := trackers[0].Alarm
+	if a == nil || !a.At.Equal(time.Date(2099, 1, 1, 16, 45, 0, 0, time.Local)) {
+		t.Fatalf("alarm %+v, expected 2099-01-01 16:45", a)
+	}
+}
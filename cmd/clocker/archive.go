@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/engine"
+)
+
+// visibleTrackers are the ones not archived, listed in the main window and
+// the tray.
+func visibleTrackers() []*Tracker {
+	visible := []*Tracker{}
+	for _, t := range trackers {
+		if t.State != engine.Archived {
+			visible = append(visible, t)
+		}
+	}
+	return visible
+}
+
+func archiveTracker(w fyne.Window, t *Tracker) {
+	if t.Running() {
+		t.Stop()
+	}
+	err := t.Fire(engine.Archive)
+	if err != nil {
+		showError(err, w)
+		return
+	}
+	audit("archive", t, "")
+	fireHooks(t, engine.Archive)
+	update(w)
+}
+
+func restoreTracker(w fyne.Window, t *Tracker) {
+	err := t.Fire(engine.Restore)
+	if err != nil {
+		showError(err, w)
+		return
+	}
+	audit("restore", t, "")
+	fireHooks(t, engine.Restore)
+	update(w)
+}
+
+// makeArchivedList folds the archived trackers matching the filter at the
+// bottom of the list, nil when there are none.
+func makeArchivedList(w fyne.Window) fyne.CanvasObject {
+	archived := []*Tracker{}
+	for _, t := range trackers {
+		if t.State == engine.Archived {
+			archived = append(archived, t)
+		}
+	}
+	rows := []fyne.CanvasObject{}
+	for _, t := range filterTrackers(archived) {
+		label := widget.NewLabel(fmt.Sprintf("%s  %s", t.Label, shortDur(t.Current().Truncate(ClockFrequency))))
+		label.Alignment = leadingAlignment()
+		restore := newIconButton("Restore "+t.Label, theme.ContentUndoIcon(), func() {
+			restoreTracker(w, t)
+		})
+		rows = append(rows, newRow(nil, nil, restore, label))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	title := fmt.Sprintf("Archived (%d)", len(rows))
+	accordion := widget.NewAccordion(widget.NewAccordionItem(title, container.NewVBox(rows...)))
+	if filtering() {
+		// matches are shown, not folded away
+		accordion.Open(0)
+	}
+	return accordion
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/lang"
+
+	"github.com/gxben/clocker/pkg/assets"
+	"github.com/gxben/clocker/pkg/export"
+)
+
+// AssetsDir holds user provided icons, sounds, translations and report
+// templates, replacing the bundled ones.
+const AssetsDir = ".clocker.d"
+
+var resources = assets.New(assetsDir())
+
+func init() {
+	export.Assets = resources
+}
+
+func assetsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, AssetsDir)
+}
+
+// resource loads an asset as a Fyne resource, nil if it can't be read.
+func resource(name string) fyne.Resource {
+	data, err := fs.ReadFile(resources, name)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+	return fyne.NewStaticResource(path.Base(name), data)
+}
+
+// loadTranslations registers the message catalogs, named after their
+// language (e.g. fr.json).
+func loadTranslations() {
+	files, err := fs.ReadDir(resources, "translations")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for _, f := range files {
+		if path.Ext(f.Name()) != ".json" {
+			continue
+		}
+		r := resource(path.Join("translations", f.Name()))
+		if r == nil {
+			continue
+		}
+		err = lang.AddTranslations(r)
+		if err != nil {
+			log.Println(f.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	AuditFile = ".clocker.audit"
+)
+
+// audit appends an entry to the audit log. The file is only ever appended
+// to, so that approved time keeps an immutable trail of changes.
+func audit(action string, t *Tracker, format string, args ...any) {
+	detail := fmt.Sprintf(format, args...)
+	logChange(action, t, detail)
+
+	home, _ := os.UserHomeDir()
+	auditFile := filepath.Clean(fmt.Sprintf("%s/%s", home, AuditFile))
+
+	f, err := os.OpenFile(auditFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer f.Close()
+
+	// the ID keeps the trail of a tracker together across renames
+	id, label := "-", ""
+	if t != nil {
+		id, label = t.ID, t.Label
+	}
+	entry := fmt.Sprintf("%s\t%s\t%s\t%q\t%s\n", time.Now().Format(time.RFC3339), action, id, label, detail)
+	_, err = f.WriteString(entry)
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"os"
+
+	"github.com/gxben/clocker/pkg/autostart"
+)
+
+// MinimizedFlag is passed to clocker when started at login, so that it
+// stays in the tray.
+const MinimizedFlag = "minimized"
+
+// setStartAtLogin installs or removes the platform autostart entry for
+// the running executable.
+func setStartAtLogin(enabled, minimized bool) error {
+	if !enabled {
+		return autostart.Disable()
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{}
+	if minimized {
+		args = append(args, "--"+MinimizedFlag)
+	}
+	return autostart.Enable(exe, args...)
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+)
+
+var totalStr = binding.NewString()
+
+type RateChange struct {
+	From time.Time `yaml:"from"`
+	Rate float64   `yaml:"rate"`
+}
+
+// RateAt returns the hourly rate effective at the given time. Rate is the
+// initial one, used before the first recorded change.
+func (t *Tracker) RateAt(at time.Time) float64 {
+	rate := t.Rate
+	for _, r := range t.Rates {
+		if r.From.After(at) {
+			break
+		}
+		rate = r.Rate
+	}
+	return rate
+}
+
+func (t *Tracker) CurrentRate() float64 {
+	return t.RateAt(time.Now())
+}
+
+// SetRate changes the hourly rate from the given date on, keeping past
+// sessions valued at the rate they were tracked with.
+func (t *Tracker) SetRate(rate float64, from time.Time) {
+	if rate == t.RateAt(from) {
+		return
+	}
+	if len(t.Sessions) == 0 && len(t.Rates) == 0 {
+		t.Rate = rate
+		return
+	}
+
+	rates := []RateChange{}
+	for _, r := range t.Rates {
+		if !r.From.Equal(from) {
+			rates = append(rates, r)
+		}
+	}
+	rates = append(rates, RateChange{From: from, Rate: rate})
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].From.Before(rates[j].From)
+	})
+	t.Rates = rates
+}
+
+// TimeByRate splits the counter time left to invoice per applicable
+// hourly rate. Time tracked before sessions were recorded is valued at the
+// initial rate.
+func (t *Tracker) TimeByRate() map[float64]time.Duration {
+	times := map[float64]time.Duration{}
+	counted := time.Duration(0)
+	for _, s := range t.CounterSessions() {
+		d := s.Duration()
+		if s.Invoice == "" {
+			times[t.RateAt(s.Start)] += d
+		}
+		counted += d
+	}
+	if rest := t.Current() - counted; rest > 0 {
+		times[t.Rate] += rest
+	}
+	return times
+}
+
+// unbilled returns the closed sessions no invoice billed yet, per hourly
+// rate, leaving out the ones without any.
+func (t *Tracker) unbilled() map[float64][]*Session {
+	sessions := map[float64][]*Session{}
+	for idx := range t.Sessions {
+		s := &t.Sessions[idx]
+		if s.Open() || s.Invoice != "" {
+			continue
+		}
+		if rate := t.RateAt(s.Start); rate != 0 {
+			sessions[rate] = append(sessions[rate], s)
+		}
+	}
+	return sessions
+}
+
+func (t *Tracker) TimeAmount() float64 {
+	amount := 0.0
+	for rate, d := range t.TimeByRate() {
+		amount += d.Hours() * rate
+	}
+	return amount
+}
+
+func (t *Tracker) ExpensesAmount() float64 {
+	amount := 0.0
+	for _, e := range t.Expenses {
+		if e.Invoice == "" {
+			amount += e.Amount
+		}
+	}
+	return amount
+}
+
+func (t *Tracker) Amount() float64 {
+	return t.TimeAmount() + t.ExpensesAmount()
+}
+
+func (t *Tracker) BillingCurrency() string {
+	if t.Currency != "" {
+		return t.Currency
+	}
+	return settings.Currency
+}
+
+// Subtotals returns the amount (time and expenses) billed by all trackers,
+// per currency.
+func Subtotals() map[string]float64 {
+	subtotals := map[string]float64{}
+	for _, t := range trackers {
+		amount := t.Amount()
+		if amount == 0 {
+			continue
+		}
+		subtotals[t.BillingCurrency()] += amount
+	}
+	return subtotals
+}
+
+// ConvertedTotal sums up all subtotals in the main currency, using the
+// user-defined exchange rates. It reports false when there is no main
+// currency or a rate to it is missing.
+func ConvertedTotal(subtotals map[string]float64) (float64, bool) {
+	if settings.Currency == "" {
+		return 0, false
+	}
+
+	total := 0.0
+	for currency, amount := range subtotals {
+		if currency == settings.Currency {
+			total += amount
+			continue
+		}
+		rate, ok := settings.ExchangeRates[currency]
+		if !ok {
+			return 0, false
+		}
+		total += amount * rate
+	}
+	return total, true
+}
+
+func formatAmount(amount float64, currency string) string {
+	return strings.TrimSpace(fmt.Sprintf("%.2f %s", amount, currency))
+}
+
+func formatRate(rate float64) string {
+	if rate == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+func parseRate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil || rate < 0 {
+		return 0, fmt.Errorf("invalid rate %q", s)
+	}
+	return rate, nil
+}
+
+func formatExchangeRates(rates map[string]float64) string {
+	lines := []string{}
+	for currency, rate := range rates {
+		lines = append(lines, fmt.Sprintf("%s = %s", currency, formatRate(rate)))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// parseExchangeRates reads one "CUR = rate" definition per line, where rate
+// is the value of one unit of CUR expressed in the main currency.
+func parseExchangeRates(s string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		currency, value, found := strings.Cut(line, "=")
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if !found || currency == "" {
+			return nil, fmt.Errorf("invalid exchange rate %q", line)
+		}
+		rate, err := parseRate(value)
+		if err != nil || rate == 0 {
+			return nil, fmt.Errorf("invalid exchange rate %q", line)
+		}
+		rates[currency] = rate
+	}
+	return rates, nil
+}
+
+func totalsText() string {
+	subtotals := Subtotals()
+	if len(subtotals) == 0 {
+		return ""
+	}
+
+	currencies := []string{}
+	for currency := range subtotals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	amounts := []string{}
+	for _, currency := range currencies {
+		amounts = append(amounts, formatAmount(subtotals[currency], currency))
+	}
+	text := strings.Join(amounts, " + ")
+
+	if len(subtotals) > 1 {
+		if total, ok := ConvertedTotal(subtotals); ok {
+			text = fmt.Sprintf("%s ≈ %s", text, formatAmount(total, settings.Currency))
+		}
+	}
+	return text
+}
+
+func refreshTotals() {
+	_ = totalStr.Set(totalsText())
+	for _, t := range trackers {
+		t.refreshBudget()
+	}
+}
+
+func makeTotals() fyne.CanvasObject {
+	totals := widget.NewLabelWithData(totalStr)
+	totals.Alignment = fyne.TextAlignCenter
+	return totals
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestRateAt(t *testing.T) {
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	april := march.AddDate(0, 1, 0)
+	acme := &Tracker{Rate: 80, Rates: []RateChange{{From: march, Rate: 90}, {From: april, Rate: 100}}}
+	for _, c := range []struct {
+		at   time.Time
+		rate float64
+	}{
+		{march.Add(-time.Second), 80},
+		{march, 90},
+		{march.AddDate(0, 0, 15), 90},
+		{april, 100},
+		{april.AddDate(1, 0, 0), 100},
+	} {
+		if rate := acme.RateAt(c.at); rate != c.rate {
+			t.Errorf("rate at %s = %v, expected %v", c.at, rate, c.rate)
+		}
+	}
+}
+
+func TestSetRate(t *testing.T) {
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	april := march.AddDate(0, 1, 0)
+	session := []Session{{ID: "a", Start: march.AddDate(0, -1, 0), End: march.AddDate(0, -1, 0).Add(time.Hour)}}
+	for _, c := range []struct {
+		name     string
+		tracker  Tracker
+		rate     float64
+		from     time.Time
+		initial  float64
+		expected []RateChange
+	}{
+		{"without sessions", Tracker{Rate: 80}, 90, march, 90, nil},
+		{"unchanged", Tracker{Rate: 80, Sessions: session}, 80, march, 80, nil},
+		{"after sessions", Tracker{Rate: 80, Sessions: session}, 90, march, 80, []RateChange{{march, 90}}},
+		{"before the last change", Tracker{Rate: 80, Sessions: session, Rates: []RateChange{{april, 100}}}, 90, march, 80,
+			[]RateChange{{march, 90}, {april, 100}}},
+		{"replacing a change", Tracker{Rate: 80, Sessions: session, Rates: []RateChange{{march, 90}}}, 95, march, 80,
+			[]RateChange{{march, 95}}},
+	} {
+		tracker := c.tracker
+		tracker.SetRate(c.rate, c.from)
+		if tracker.Rate != c.initial || len(tracker.Rates) != len(c.expected) {
+			t.Errorf("%s: rate %v, changes %+v", c.name, tracker.Rate, tracker.Rates)
+			continue
+		}
+		for idx, r := range c.expected {
+			if !tracker.Rates[idx].From.Equal(r.From) || tracker.Rates[idx].Rate != r.Rate {
+				t.Errorf("%s: changes %+v, expected %+v", c.name, tracker.Rates, c.expected)
+			}
+		}
+	}
+}
+
+func TestTimeByRate(t *testing.T) {
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	acme := &Tracker{Rate: 80, Rates: []RateChange{{From: march, Rate: 100}}}
+	acme.Sessions = []Session{
+		{ID: "a", Start: march.Add(-48 * time.Hour), End: march.Add(-46 * time.Hour)},
+		// spanning the change, valued at the rate it started with
+		{ID: "b", Start: march.Add(-time.Hour), End: march.Add(2 * time.Hour)},
+		{ID: "c", Start: march.Add(24 * time.Hour), End: march.Add(25 * time.Hour)},
+		// billed already
+		{ID: "d", Start: march.Add(48 * time.Hour), End: march.Add(52 * time.Hour), Invoice: "INV-0001"},
+	}
+	// plus half an hour added by hand
+	acme.Elapsed = 10*time.Hour + 30*time.Minute
+
+	expected := map[float64]time.Duration{80: 5*time.Hour + 30*time.Minute, 100: time.Hour}
+	if times := acme.TimeByRate(); !maps.Equal(times, expected) {
+		t.Errorf("time by rate = %v, expected %v", times, expected)
+	}
+	if amount := acme.TimeAmount(); amount != 540 {
+		t.Errorf("amount = %v, expected 540", amount)
+	}
+}
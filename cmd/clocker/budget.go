@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Burn returns the share of the tracker budget already spent.
+func (t *Tracker) Burn() float64 {
+	if t.Budget == 0 {
+		return 0
+	}
+	return t.Amount() / t.Budget
+}
+
+// refreshBudget updates the burn-down indicator and notifies the user the
+// first time a budget alert threshold gets crossed.
+func (t *Tracker) refreshBudget() {
+	if t.Budget == 0 {
+		return
+	}
+
+	burn := t.Burn()
+	_ = t.BudgetBurn.Set(burn)
+
+	crossed := 0
+	for _, threshold := range settings.BudgetAlerts {
+		if burn*100 >= float64(threshold) && threshold > crossed {
+			crossed = threshold
+		}
+	}
+	if crossed > t.Alerted {
+		text := lang.LocalizeKey("budget.alert", "{{.Label}} has used {{.Percent}}% of its {{.Budget}} budget.", map[string]any{
+			"Label":   t.Label,
+			"Percent": crossed,
+			"Budget":  formatAmount(t.Budget, t.BillingCurrency()),
+		})
+		notifyUser(AlertBudget, lang.L("Budget alert"), text)
+	}
+	t.Alerted = crossed
+}
+
+func makeBudgetBar(t *Tracker) fyne.CanvasObject {
+	if t.Budget == 0 {
+		return nil
+	}
+
+	bar := widget.NewProgressBarWithData(t.BudgetBurn)
+	bar.TextFormatter = func() string {
+		left := t.Budget - t.Amount()
+		if left < 0 {
+			return fmt.Sprintf("%s over budget", formatAmount(-left, t.BillingCurrency()))
+		}
+		return fmt.Sprintf("%s left", formatAmount(left, t.BillingCurrency()))
+	}
+	return bar
+}
+
+func formatBudgetAlerts(alerts []int) string {
+	values := []string{}
+	for _, a := range alerts {
+		values = append(values, strconv.Itoa(a))
+	}
+	return strings.Join(values, ", ")
+}
+
+func parseBudgetAlerts(s string) ([]int, error) {
+	alerts := []int{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "%"))
+		if v == "" {
+			continue
+		}
+		a, err := strconv.Atoi(v)
+		if err != nil || a <= 0 {
+			return nil, fmt.Errorf("invalid budget alert %q", v)
+		}
+		alerts = append(alerts, a)
+	}
+	sort.Ints(alerts)
+	return alerts, nil
+}
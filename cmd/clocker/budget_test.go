@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/notify"
+)
+
+func TestRefreshBudget(t *testing.T) {
+	newTestWindow(t, "Acme")
+	alerts := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		alerts <- string(body)
+	}))
+	defer server.Close()
+	settings.BudgetAlerts = []int{50, 80, 100}
+	settings.Notifications.Webhook.URL = server.URL
+	settings.Notifications.setRoute(AlertBudget, []string{notify.ChannelWebhook})
+
+	acme := trackers[0]
+	acme.Rate, acme.Budget = 100, 1000
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	spend := func(hours int) {
+		t.Helper()
+		acme.Sessions = []Session{{ID: "a", Start: start, End: start.Add(time.Duration(hours) * time.Hour)}}
+		acme.refreshBudget()
+	}
+	expect := func(percent string) {
+		t.Helper()
+		select {
+		case body := <-alerts:
+			if !strings.Contains(body, percent) {
+				t.Errorf("alert %s, expected %s", body, percent)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no alert at %s", percent)
+		}
+	}
+	none := func(what string) {
+		t.Helper()
+		select {
+		case body := <-alerts:
+			t.Errorf("%s alerted %s", what, body)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	spend(4)
+	none("40% of the budget")
+	if burn, _ := acme.BudgetBurn.Get(); burn != 0.4 {
+		t.Errorf("burn = %v", burn)
+	}
+	spend(5)
+	expect("50%")
+	// once per threshold
+	spend(6)
+	none("the 50% threshold again")
+	// thresholds crossed at once alert for the highest one
+	spend(11)
+	expect("100%")
+	if acme.Alerted != 100 {
+		t.Errorf("alerted at %d%%", acme.Alerted)
+	}
+	spend(12)
+	none("over budget")
+
+	// the budget raised, crossing again alerts again
+	acme.Budget = 2000
+	spend(12)
+	none("60% of the raised budget")
+	spend(17)
+	expect("80%")
+}
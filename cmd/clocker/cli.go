@@ -0,0 +1,237 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gxben/clocker/pkg/config"
+	"github.com/gxben/clocker/pkg/export"
+	"github.com/gxben/clocker/pkg/importer"
+)
+
+// commands run from the command line without showing the window, e.g.
+// clocker export --format tempo-json.
+var commands = map[string]func(args []string, stdout io.Writer) error{
+	"config":  configCommand,
+	"doctor":  doctorCommand,
+	"export":  exportCommand,
+	"import":  importCommand,
+	"migrate": migrateCommand,
+}
+
+// runCommand runs the command named by the first argument, if any.
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return false
+	}
+	err := cmd(args[1:], os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return true
+}
+
+func formatNames() string {
+	names := []string{}
+	for _, f := range export.Formats() {
+		names = append(names, f.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func exportCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	name := flags.String("format", "", "one of "+formatNames()+", detected from the output file otherwise")
+	output := flags.String("o", "", "output `file`, standard output by default")
+	week := flags.String("week", "", "any `day` of the week to export, the current one by default")
+	first := flags.String("from", "", "first `day` to export, instead of a week")
+	last := flags.String("to", "", "last `day` to export, included")
+	year := flags.Int("year", 0, "`year` to export, e.g. for a year in review")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	format, ok := export.Lookup(DefaultExportFormat)
+	switch {
+	case *name != "":
+		format, ok = export.Lookup(*name)
+		if !ok {
+			return fmt.Errorf("unknown format %q, expected one of %s", *name, formatNames())
+		}
+	case *output != "":
+		format, ok = export.Detect(*output)
+		if !ok {
+			return fmt.Errorf("can't tell the format of %s, use --format", *output)
+		}
+	}
+
+	day := time.Now()
+	if *week != "" {
+		day, err = parseDate(*week)
+		if err != nil {
+			return err
+		}
+	}
+	from := startOfWeek(day)
+	to := export.AddDays(from, 7)
+	if *first != "" {
+		from, err = parseDate(*first)
+		if err != nil {
+			return err
+		}
+		to = export.AddDays(from, 7)
+	}
+	if *last != "" {
+		to, err = parseDate(*last)
+		if err != nil {
+			return err
+		}
+		to = export.AddDays(to, 1)
+	}
+	if *year != 0 {
+		from = time.Date(*year, 1, 1, 0, 0, 0, 0, time.Local)
+		to = from.AddDate(1, 0, 0)
+	}
+	if !to.After(from) {
+		return errors.New("nothing to export, the last day is before the first one")
+	}
+
+	readConfig()
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return format.Export(w, exportRequest(from, to))
+}
+
+func importCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	name := flags.String("format", "", "format of the file, detected from its name otherwise")
+	dryRun := flags.Bool("dry-run", false, "only preview what would be imported")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: clocker import [flags] file")
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected a single file to import")
+	}
+	file := flags.Arg(0)
+
+	format, ok := importer.Detect(file)
+	if *name != "" {
+		format, ok = importer.Lookup(*name)
+	}
+	if !ok {
+		return fmt.Errorf("can't tell the format of %s, use --format", file)
+	}
+
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	readConfig()
+	plan, err := readImport(format, f)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, previewImport(plan))
+	if *dryRun || plan.Empty() {
+		return nil
+	}
+	applyImport(plan, format.Name)
+	saveConfig()
+	if groups := duplicateLabels(trackers); len(groups) > 0 {
+		fmt.Fprintf(stdout, "%d labels are shared by several trackers, run clocker doctor --fix to merge them\n", len(groups))
+	}
+	return nil
+}
+
+// configCommand lists the settings, shows one or changes it in the
+// configuration file.
+func configCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: clocker config [key [value]]")
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 2 {
+		flags.Usage()
+		return errors.New("expected a key and its value at most")
+	}
+
+	readConfig()
+	switch flags.NArg() {
+	case 0:
+		tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		for _, s := range settingsSchema {
+			value, _ := config.Get(&settings, s.Key)
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Key, value, config.EnvName(EnvPrefix, s.Key), s.Usage)
+		}
+		return tw.Flush()
+	case 1:
+		if _, ok := settingsSchema.Lookup(flags.Arg(0)); !ok {
+			return fmt.Errorf("unknown setting %q", flags.Arg(0))
+		}
+		value, err := config.Get(&settings, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, value)
+		return nil
+	}
+
+	key := flags.Arg(0)
+	if _, ok := settingsSchema.Lookup(key); !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	s := savedSettings()
+	err = config.Set(&s, key, flags.Arg(1))
+	if err == nil {
+		err = s.Validate()
+	}
+	if err != nil {
+		return err
+	}
+	// overrides are left out of the saved settings
+	settings, overridden = s, nil
+	saveConfig()
+	return nil
+}
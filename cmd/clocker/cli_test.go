@@ -0,0 +1,276 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	// daylight saving time rules, missing from some systems
+	_ "time/tzdata"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gxben/clocker/pkg/export"
+)
+
+// saveTestTrackers saves a tracker with two sessions on the week of
+// 2026-03-02 and one on the week after.
+func saveTestTrackers(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	store = nil
+	trackers = []*Tracker{}
+	settings = Settings{}
+	tr := &Tracker{Label: "Acme", Issue: "ACME-1"}
+	for _, day := range []int{2, 4, 10} {
+		start := time.Date(2026, 3, day, 9, 0, 0, 0, time.Local)
+		tr.Sessions = append(tr.Sessions, Session{Start: start, End: start.Add(2 * time.Hour)})
+	}
+	addTracker(tr)
+	saveConfig()
+	trackers = []*Tracker{}
+}
+
+func TestExportCommand(t *testing.T) {
+	saveTestTrackers(t)
+
+	var out bytes.Buffer
+	err := exportCommand([]string{"--format", "daily", "--week", "2026-03-04"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "2026-03-02") || !strings.Contains(out.String(), "2026-03-04") {
+		t.Errorf("daily export misses the week sessions:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "2026-03-10") {
+		t.Errorf("daily export holds the next week:\n%s", out.String())
+	}
+}
+
+func TestExportCommandDetect(t *testing.T) {
+	saveTestTrackers(t)
+
+	file := filepath.Join(t.TempDir(), "worklogs.tempo.json")
+	err := exportCommand([]string{"-o", file, "--from", "2026-03-01", "--to", "2026-03-31"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worklogs := []map[string]any{}
+	err = json.Unmarshal(contents, &worklogs)
+	if err != nil {
+		t.Fatalf("%s is not Tempo JSON: %v", file, err)
+	}
+	if len(worklogs) != 3 {
+		t.Errorf("exported %d worklogs, expected 3", len(worklogs))
+	}
+}
+
+func TestExportCommandMarkdown(t *testing.T) {
+	saveTestTrackers(t)
+
+	file := filepath.Join(t.TempDir(), "worklog.md")
+	err := exportCommand([]string{"-o", file, "--week", "2026-03-04"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(contents), "# Time report 2026-03-02 to 2026-03-08\n") || !strings.Contains(string(contents), "## Sessions") {
+		t.Errorf("%s is not a Markdown report:\n%s", file, contents)
+	}
+}
+
+func TestExportCommandYear(t *testing.T) {
+	saveTestTrackers(t)
+
+	var out bytes.Buffer
+	err := exportCommand([]string{"--format", "review-html", "--year", "2026"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "<title>Year in review 2026</title>") || !strings.Contains(out.String(), "<b>3</b>days worked") {
+		t.Errorf("year not reviewed:\n%s", out.String())
+	}
+}
+
+func TestExportFileName(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	settings = Settings{}
+	f, _ := export.Lookup("markdown")
+	week := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	if name := exportFileName(week, week.AddDate(0, 0, 7), f); name != "timesheet-2026-W10.md" {
+		t.Errorf("week exported as %s", name)
+	}
+	if name := exportFileName(week, week.AddDate(0, 1, 0), f); name != "timesheet-2026-03-02_2026-04-01.md" {
+		t.Errorf("month exported as %s", name)
+	}
+	review, _ := export.Lookup("review-html")
+	from, to := reviewYear(time.Date(2027, 1, 12, 9, 0, 0, 0, time.Local))
+	if name := exportFileName(from, to, review); name != "timesheet-2026.review.html" {
+		t.Errorf("past year reviewed in January as %s", name)
+	}
+}
+
+// setLocal runs the test in the time zone, as if it were the local one.
+func setLocal(t *testing.T, name string) {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = local })
+}
+
+func TestDaylightSaving(t *testing.T) {
+	// clocks go forward at midnight, sunday starts at 01:00
+	setLocal(t, "America/Santiago")
+	settings = Settings{WeekStart: "sunday"}
+	t.Cleanup(func() { settings = Settings{} })
+	sunday, err := parseDate("2026-09-06")
+	if err != nil || sunday.Format(time.DateTime) != "2026-09-06 01:00:00" {
+		t.Fatalf("parsed %s, %v", sunday, err)
+	}
+	if week := startOfWeek(sunday.Add(14 * time.Hour)); !week.Equal(sunday) {
+		t.Errorf("week starts on %s", week)
+	}
+	if week := startOfWeek(time.Date(2026, 9, 12, 10, 0, 0, 0, time.Local)); !week.Equal(sunday) {
+		t.Errorf("week of saturday starts on %s", week)
+	}
+
+	// the session lasts an hour more than the wall clock tells
+	setLocal(t, "Europe/Paris")
+	start := time.Date(2026, 10, 25, 1, 30, 0, 0, time.Local)
+	s := Session{ID: "a", Start: start, End: start.Add(3 * time.Hour)}
+	if s.End.Format(time.TimeOnly) != "03:30:00" {
+		t.Fatalf("session ends at %s", s.End)
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read Session
+	if err = yaml.Unmarshal(data, &read); err != nil || read.Duration() != 3*time.Hour {
+		t.Errorf("read session lasting %s, %v:\n%s", read.Duration(), err, data)
+	}
+}
+
+func TestExportCommandErrors(t *testing.T) {
+	saveTestTrackers(t)
+
+	tests := [][]string{
+		{"--format", "pdf"},
+		{"-o", "timesheet.pdf"},
+		{"--week", "yesterday"},
+		{"--from", "2026-03-10", "--to", "2026-03-01"},
+	}
+	for _, args := range tests {
+		var out bytes.Buffer
+		err := exportCommand(args, &out)
+		if err == nil {
+			t.Errorf("export %v succeeded", args)
+		}
+	}
+}
+
+func TestImportCommand(t *testing.T) {
+	saveTestTrackers(t)
+	file := filepath.Join(t.TempDir(), "week.csv")
+	csv := `tracker,start,end
+acme,2026-03-02 10:00,2026-03-02 12:00
+Acme,2026-03-03 09:00,2026-03-03 10:00
+Globex,2026-03-03 09:00,2026-03-03 10:00
+`
+	err := os.WriteFile(file, []byte(csv), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = importCommand([]string{"--dry-run", file}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preview := "1 new tracker, 2 sessions, 1 conflict"
+	if !strings.HasPrefix(out.String(), preview) {
+		t.Errorf("preview %q, expected %q", out.String(), preview)
+	}
+	trackers = []*Tracker{}
+	readConfig()
+	if len(trackers) != 1 || len(trackers[0].Sessions) != 3 {
+		t.Fatal("dry run changed the configuration")
+	}
+
+	out.Reset()
+	err = importCommand([]string{file}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trackers = []*Tracker{}
+	readConfig()
+	if len(trackers) != 2 || len(trackers[0].Sessions) != 4 || len(trackers[1].Sessions) != 1 {
+		t.Fatalf("imported into %d trackers", len(trackers))
+	}
+	if trackers[0].Elapsed != time.Hour {
+		t.Errorf("elapsed = %s, expected the imported hour", trackers[0].Elapsed)
+	}
+
+	// importing again brings nothing new
+	out.Reset()
+	err = importCommand([]string{file}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "0 new trackers, 0 sessions, 1 conflict, 2 duplicates") {
+		t.Errorf("second import preview %q", out.String())
+	}
+}
+
+func TestConfigCommand(t *testing.T) {
+	resetOverrides(t)
+	saveTestTrackers(t)
+
+	err := configCommand([]string{"sync.interval_minutes", "15"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	err = configCommand([]string{"sync.interval_minutes"}, &out)
+	if err != nil || out.String() != "15\n" {
+		t.Errorf("read back %q, %v", out.String(), err)
+	}
+	if len(trackers) != 1 {
+		t.Errorf("changing a setting left %d trackers", len(trackers))
+	}
+
+	out.Reset()
+	err = configCommand(nil, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "CLOCKER_SYNC_INTERVAL_MINUTES") || !strings.Contains(out.String(), "budget_alerts") {
+		t.Errorf("settings list misses keys:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{{"colour"}, {"idle_minutes", "-1"}, {"idle_minutes", "soon"}} {
+		if err := configCommand(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("config %v succeeded", args)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/clock"
+)
+
+const (
+	// HiddenClockFrequency refreshes the tray and budget alerts while the
+	// window is hidden.
+	HiddenClockFrequency = time.Minute
+)
+
+var (
+	windowShown = true
+	clockWake   = make(chan struct{}, 1)
+)
+
+// Current is the counter time, including the running session. Elapsed
+// only accounts for closed sessions, so that time stays exact whatever
+// the refresh rate.
+func (t *Tracker) Current() time.Duration {
+	current := t.Elapsed
+	if n := len(t.Sessions); n > 0 {
+		s := &t.Sessions[n-1]
+		if s.Open() && t.inCounter(s) {
+			current += s.Duration()
+		}
+	}
+	return current
+}
+
+// refreshElapsed shows the counter time, to the second, along with the
+// projected weekly total.
+func (t *Tracker) refreshElapsed() {
+	now := time.Now()
+	text := shortDur(t.Current().Truncate(ClockFrequency))
+	if left, ok := t.remaining(now); ok {
+		text += fmt.Sprintf(" (%s left)", shortDur(left.Truncate(ClockFrequency)))
+	}
+	_ = t.ElapsedStr.Set(text + t.paceText(now))
+	t.checkGoal(now)
+}
+
+func anyActive() bool {
+	for _, t := range trackers {
+		if t.Running() {
+			return true
+		}
+	}
+	return false
+}
+
+// hidden tells whether nothing but the tray shows the trackers.
+func hidden() bool {
+	return !windowShown && trayEnabled()
+}
+
+func showWindow(w fyne.Window) {
+	windowShown = true
+	w.Show()
+	w.RequestFocus()
+	wakeClock()
+}
+
+func hideWindow(w fyne.Window) {
+	windowShown = false
+	w.Hide()
+	wakeClock()
+}
+
+// wakeClock makes the clock refresh right away and pick up any change of
+// running trackers or window visibility.
+func wakeClock() {
+	select {
+	case clockWake <- struct{}{}:
+	default:
+	}
+}
+
+// runClock refreshes the running trackers every second while the window
+// is shown, every minute when in the tray only, and sleeps when nothing
+// runs, until ctx is done. Countdowns wake it up when over.
+func runClock(ctx context.Context, w fyne.Window, clk clock.Clock) {
+	for {
+		if !anyActive() {
+			select {
+			case <-clockWake:
+			case <-ctx.Done():
+				return
+			}
+		} else {
+			interval := ClockFrequency
+			if hidden() {
+				interval = HiddenClockFrequency
+			}
+			if left, ok := nextDeadline(clk.Now()); ok {
+				interval = min(interval, left)
+			}
+			timer := clk.NewTimer(interval)
+			select {
+			case <-timer.C():
+			case <-clockWake:
+				timer.Stop()
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+		if expireTimers(clk.Now()) {
+			refreshTray(w)
+		}
+		refreshClock()
+	}
+}
+
+func refreshClock() {
+	if !hidden() {
+		// projections of the others go down as time goes
+		for _, t := range visibleTrackers() {
+			t.refreshElapsed()
+		}
+	}
+	refreshTotals()
+	refreshTrayTitle()
+	refreshTaskbar()
+	if anyActive() {
+		heartbeat()
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/export"
+)
+
+const (
+	ComparePreviousWeek = "Previous week"
+	CompareLastYear     = "Same week last year"
+
+	CompareTrackers = "Tracker"
+	CompareClients  = "Client"
+)
+
+// baseWeek is the start of the week the one starting on week gets compared
+// with: the previous one, or the one with the same number a year before.
+func baseWeek(week time.Time, base string) time.Time {
+	if base == CompareLastYear {
+		year, number := week.ISOWeek()
+		// the 4th of january always is in the first week
+		first := startOfWeek(time.Date(year-1, 1, 4, 0, 0, 0, 0, week.Location()))
+		return export.AddDays(first, 7*(number-1))
+	}
+	return export.AddDays(week, -7)
+}
+
+// compareWeeks sums up the week of the day, and the base week, per tracker
+// or client.
+func compareWeeks(day time.Time, base, per string) (time.Time, time.Time, []export.Change) {
+	week := startOfWeek(day)
+	from := baseWeek(week, base)
+	group := func(e export.Entry) string {
+		return e.Tracker
+	}
+	if per == CompareClients {
+		group = func(e export.Entry) string {
+			if e.Receiver == "" {
+				return "No client"
+			}
+			return e.Receiver
+		}
+	}
+	return week, from, export.Compare(exportEntries(), week, export.AddDays(week, 7), from, export.AddDays(from, 7), group)
+}
+
+// signedDur shows a delta with its sign, e.g. +1h30m or -45m.
+func signedDur(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d > 0:
+		return "+" + shortDur(d)
+	case d < 0:
+		return "-" + shortDur(-d)
+	default:
+		return "±0"
+	}
+}
+
+// formatChange shows the time spent, then the one of the base week along
+// with the delta.
+func formatChange(c export.Change) string {
+	text := fmt.Sprintf("%s  (%s)  %s", shortDur(c.Current.Round(time.Minute)), shortDur(c.Base.Round(time.Minute)), signedDur(c.Delta()))
+	if percent, ok := c.Percent(); ok && c.Delta().Round(time.Minute) != 0 {
+		text += fmt.Sprintf(" %+.0f%%", percent)
+	}
+	return text + "  " + c.Trend()
+}
+
+// compareDialog shows the time spent on a week next to the one of the
+// week before, or of the same week a year before.
+func compareDialog(w fyne.Window) {
+	day := newEntry()
+	day.SetText(time.Now().Format(export.DateFormat))
+	base := widget.NewSelect([]string{ComparePreviousWeek, CompareLastYear}, nil)
+	base.SetSelected(ComparePreviousWeek)
+	per := widget.NewSelect([]string{CompareTrackers, CompareClients}, nil)
+	per.SetSelected(CompareTrackers)
+	rows := container.NewVBox()
+
+	render := func() {
+		d, err := parseDate(day.Text)
+		if err != nil {
+			return
+		}
+		rows.RemoveAll()
+		week, from, changes := compareWeeks(d, base.Selected, per.Selected)
+		rows.Add(widget.NewLabelWithStyle(fmt.Sprintf("Week %s against %s", weekOf(week), weekOf(from)),
+			leadingAlignment(), fyne.TextStyle{Bold: true}))
+		if len(changes) == 0 {
+			rows.Add(widget.NewLabel("Nothing tracked on both weeks."))
+			return
+		}
+		total := export.Change{Name: "Total"}
+		for _, c := range changes {
+			rows.Add(statsLine(c.Name, formatChange(c)))
+			total.Current += c.Current
+			total.Base += c.Base
+		}
+		rows.Add(statsLine(total.Name, formatChange(total)))
+	}
+	day.OnChanged = func(string) { render() }
+	base.OnChanged = func(string) { render() }
+	per.OnChanged = func(string) { render() }
+	render()
+
+	form := widget.NewForm(
+		&widget.FormItem{Text: "Week of", Widget: day, HintText: "Any day of the week"},
+		widget.NewFormItem("Compared with", base),
+		widget.NewFormItem("Per", per),
+	)
+	d := dialog.NewCustom("Compare weeks", "Close", container.NewBorder(form, nil, nil, nil, container.NewVScroll(rows)), w)
+	d.Resize(fyne.NewSize(460, 480))
+	showDialog(d)
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareWeeks(t *testing.T) {
+	newTestWindow(t, "Acme", "Globex")
+	acme, globex := trackers[0], trackers[1]
+	acme.Receiver, globex.Receiver = "Big Corp", "Big Corp"
+	// a thursday, and the same ISO week a year before
+	day := time.Date(2026, 3, 12, 9, 0, 0, 0, time.Local)
+	lastYear := time.Date(2025, 3, 13, 9, 0, 0, 0, time.Local)
+	acme.Sessions = []Session{
+		{ID: "a", Start: lastYear, End: lastYear.Add(time.Hour)},
+		{ID: "b", Start: day.AddDate(0, 0, -7), End: day.AddDate(0, 0, -7).Add(2 * time.Hour)},
+		{ID: "c", Start: day, End: day.Add(3 * time.Hour)},
+	}
+	globex.Sessions = []Session{{ID: "d", Start: day, End: day.Add(time.Hour)}}
+
+	week, from, changes := compareWeeks(day, ComparePreviousWeek, CompareTrackers)
+	if weekOf(week) != "2026-W11" || weekOf(from) != "2026-W10" || len(changes) != 2 || changes[0].Base != 2*time.Hour {
+		t.Errorf("compared %s with %s: %+v", weekOf(week), weekOf(from), changes)
+	}
+	_, from, changes = compareWeeks(day, CompareLastYear, CompareClients)
+	if weekOf(from) != "2025-W11" || len(changes) != 1 || changes[0].Name != "Big Corp" || changes[0].Current != 4*time.Hour || changes[0].Base != time.Hour {
+		t.Errorf("compared with %s: %+v", weekOf(from), changes)
+	}
+	if text := formatChange(changes[0]); text != "4h  (1h)  +3h +300%  ↑" {
+		t.Errorf("change shown as %q", text)
+	}
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/clock"
+	"github.com/gxben/clocker/pkg/config"
+	"github.com/gxben/clocker/pkg/engine"
+	"github.com/gxben/clocker/pkg/export"
+	"github.com/gxben/clocker/pkg/hooks"
+	"github.com/gxben/clocker/pkg/invoice"
+	"github.com/gxben/clocker/pkg/notify"
+	"github.com/gxben/clocker/pkg/syncer"
+)
+
+const (
+	SetFlag   = "set"
+	EnvPrefix = "CLOCKER_"
+)
+
+// settingsSchema lists the settings that can be overridden from the
+// environment, e.g. CLOCKER_IDLE_MINUTES=5, or the command line, e.g.
+// --set idle_minutes=5.
+var settingsSchema = config.Schema{
+	{Key: "currency", Usage: "billing currency code"},
+	{Key: "budget_alerts", Default: "80,100", Usage: "budget percentages to be alerted at"},
+	{Key: "idle_minutes", Usage: "minutes without activity before being asked about the idle time, 0 to disable"},
+	{Key: "menu_bar", Usage: "keep clocker in the menu bar only"},
+	{Key: "start_minimized", Usage: "start hidden in the system tray at login"},
+	{Key: "ui_scale", Usage: "interface scale percentage"},
+	{Key: "text_scale", Usage: "text size percentage"},
+	{Key: "high_contrast", Usage: "use the high contrast theme"},
+	{Key: "power_prompt", Usage: "ask to stop trackers on critical battery"},
+	{Key: "timer_presets", Default: "25,50,90", Usage: "countdowns offered next to the play buttons, in minutes"},
+	{Key: "week_start", Default: "monday", Usage: "first day of the week"},
+	{Key: "weekly_rollover", Usage: "restart counters every week, keeping past weeks in the history"},
+	{Key: "storage", Default: StorageYAML, Usage: "storage backend"},
+	{Key: "event_log", Usage: "JSON Lines file tracker events get appended to, empty to disable"},
+	{Key: "control_file", Usage: "JSON Lines file start and stop commands get read from, empty to disable"},
+	{Key: "invoice.logo", Usage: "logo shown on invoices"},
+	{Key: "invoice.number_format", Default: invoice.DefaultNumberFormat, Usage: "invoice numbering"},
+	{Key: "invoice.next_number", Default: "1", Usage: "number of the next invoice"},
+	{Key: "sap.personnel_number", Usage: "SAP CATS personnel number"},
+	{Key: "sap.activity_type", Usage: "SAP CATS activity type"},
+	{Key: "sap.date_format", Usage: "Go layout of SAP CATS work dates"},
+	{Key: "tempo.account_id", Usage: "Atlassian account ID of Tempo worklogs"},
+	{Key: "standup.template", Usage: "Go template of standup summaries"},
+	{Key: "notifications.webhook.url", Usage: "webhook alerts are posted to"},
+	{Key: "notifications.email.server", Usage: "SMTP server alerts are sent through"},
+	{Key: "notifications.email.username", Usage: "SMTP user"},
+	{Key: "notifications.email.from", Usage: "sender of email alerts"},
+	{Key: "notifications.email.to", Usage: "recipients of email alerts"},
+	{Key: "sync.provider", Usage: "one of " + strings.Join(syncProviders, ", ") + ", empty to disable"},
+	{Key: "sync.url", Usage: "WebDAV file or HTTP API document"},
+	{Key: "sync.username", Usage: "WebDAV user"},
+	{Key: "sync.dir", Usage: "git clone dedicated to clocker"},
+	{Key: "sync.branch", Default: syncer.DefaultBranch, Usage: "git branch"},
+	{Key: "sync.interval_minutes", Usage: "minutes between syncs, 0 to only sync on save"},
+}
+
+var (
+	settingOverrides config.Overrides
+	// overridden settings keep their file value in fileSettings, which
+	// gets saved instead
+	overridden   []string
+	fileSettings Settings
+)
+
+func settingsLoader(present []string) *config.Loader {
+	return &config.Loader{
+		Schema:  settingsSchema,
+		Prefix:  EnvPrefix,
+		Flags:   settingOverrides,
+		Present: present,
+	}
+}
+
+// settingKeys lists the keys set in a configuration file, none for legacy
+// ones.
+func settingKeys(contents []byte) []string {
+	keys, err := config.Keys(contents)
+	if err != nil {
+		return nil
+	}
+	return keys
+}
+
+// loadSettings completes the settings read from the file with defaults
+// for the missing ones, environment and flags.
+func loadSettings(s Settings, present []string) (Settings, error) {
+	file := s
+	keys, err := settingsLoader(present).Apply(&s)
+	overridden, fileSettings = keys, file
+	return s, err
+}
+
+// savedSettings are the current ones, minus overrides.
+func savedSettings() Settings {
+	s := settings
+	err := config.Copy(&s, &fileSettings, overridden...)
+	if err != nil {
+		log.Println(err)
+	}
+	return s
+}
+
+func (s *Settings) Validate() error {
+	errs := []error{}
+	if s.Currency != "" && len(s.Currency) != 3 {
+		errs = append(errs, fmt.Errorf("invalid currency %q", s.Currency))
+	}
+	for currency, rate := range s.ExchangeRates {
+		if rate <= 0 {
+			errs = append(errs, fmt.Errorf("invalid %s exchange rate %v", currency, rate))
+		}
+	}
+	for _, a := range s.BudgetAlerts {
+		if a <= 0 {
+			errs = append(errs, fmt.Errorf("invalid budget alert %d", a))
+		}
+	}
+	for _, p := range s.TimerPresets {
+		if p <= 0 {
+			errs = append(errs, fmt.Errorf("invalid timer preset %d", p))
+		}
+	}
+	if s.IdleMinutes < 0 {
+		errs = append(errs, fmt.Errorf("invalid idle delay %d", s.IdleMinutes))
+	}
+	for _, scale := range []int{s.UIScale, s.TextScale} {
+		if scale != 0 && (scale < ScaleChoices[0] || scale > ScaleChoices[len(ScaleChoices)-1]) {
+			errs = append(errs, fmt.Errorf("invalid scale %d%%", scale))
+		}
+	}
+	if _, ok := parseWeekday(s.WeekStart); s.WeekStart != "" && !ok {
+		errs = append(errs, fmt.Errorf("invalid week start %q", s.WeekStart))
+	}
+	if _, ok := storages[s.Storage]; s.Storage != "" && !ok {
+		errs = append(errs, fmt.Errorf("unsupported storage %q", s.Storage))
+	}
+	if s.Invoice.NextNumber < 0 {
+		errs = append(errs, fmt.Errorf("invalid invoice number %d", s.Invoice.NextNumber))
+	}
+	if err := s.Invoice.CheckNumberFormat(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := export.StandupTemplate(s.Standup.Template); err != nil {
+		errs = append(errs, err)
+	}
+	for kind, route := range s.Notifications.Routes {
+		if !slices.Contains(alertKinds, kind) {
+			errs = append(errs, fmt.Errorf("unknown alert %q", kind))
+		}
+		for _, channel := range route {
+			if !slices.Contains(notify.Channels, channel) {
+				errs = append(errs, fmt.Errorf("unknown notification channel %q", channel))
+			}
+		}
+	}
+	if s.Sync.Provider != "" && !slices.Contains(syncProviders, s.Sync.Provider) {
+		errs = append(errs, fmt.Errorf("unsupported sync provider %q", s.Sync.Provider))
+	}
+	if s.Sync.IntervalMinutes < 0 {
+		errs = append(errs, fmt.Errorf("invalid sync interval %d", s.Sync.IntervalMinutes))
+	}
+	for _, h := range s.Hooks {
+		if !validHookEvent(h.Event) {
+			errs = append(errs, fmt.Errorf("hook %s: unknown event %q", &h, h.Event))
+		}
+		if strings.TrimSpace(h.Command) == "" {
+			errs = append(errs, fmt.Errorf("hook %s: no command", &h))
+		}
+		if h.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("hook %s: invalid timeout %s", &h, h.Timeout))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validHookEvent(event string) bool {
+	if event == hooks.AnyEvent {
+		return true
+	}
+	for _, e := range engine.Events {
+		if strings.EqualFold(event, e.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// applySettings takes changed settings into account.
+func applySettings(w fyne.Window, old Settings) {
+	if settings.IdleMinutes != old.IdleMinutes {
+		startIdleDetection(w)
+	}
+	if scaleFactor(settings.UIScale) != scaleFactor(old.UIScale) || scaleFactor(settings.TextScale) != scaleFactor(old.TextScale) ||
+		settings.HighContrast != old.HighContrast {
+		applyTheme()
+	}
+	if settings.Sync != old.Sync {
+		startSync(w)
+	}
+	if settingFile(settings.ControlFile) != settingFile(old.ControlFile) {
+		startControl(w)
+	}
+	if settings.Rollover && (!old.Rollover || settings.WeekStart != old.WeekStart) {
+		stateLock.Lock()
+		rolloverTrackers(time.Now())
+		stateLock.Unlock()
+	}
+	update(w)
+}
+
+// watchedFiles are the ones the settings get read from.
+func watchedFiles() []string {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	files := []string{configFile()}
+	if s, ok := store.(*fileStorage); ok && s.file != configFile() {
+		files = append(files, s.file)
+	}
+	return files
+}
+
+// watchConfig reloads the settings whenever the configuration file gets
+// edited outside of clocker, e.g. by a dotfile manager.
+func watchConfig(ctx context.Context, w fyne.Window) {
+	config.Watch(ctx, clock.Real, config.WatchInterval, func(file string) {
+		reloadSettings(w, file)
+	}, watchedFiles)
+}
+
+// reloadSettings applies the settings changed in the file, trackers being
+// left as they are in memory. Settings failing validation are ignored.
+func reloadSettings(w fyne.Window, file string) {
+	old, ok := loadChangedSettings(file)
+	if ok && w != nil {
+		applySettings(w, old)
+	}
+}
+
+// loadChangedSettings replaces the settings with the ones of the file, if
+// valid and changed, returning the previous ones.
+func loadChangedSettings(file string) (Settings, bool) {
+	ctx, cancel := context.WithTimeout(appCtx, StoreTimeout)
+	defer cancel()
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	loaded, _, err := store.Load(ctx)
+	if err != nil {
+		log.Println(err)
+		return settings, false
+	}
+	s := loaded.Settings
+	values := s
+	keys, err := settingsLoader(loaded.keys).Apply(&s)
+	if err != nil {
+		log.Println("Ignoring invalid settings from", file, ":", err)
+		return settings, false
+	}
+	// our own saves come back unchanged
+	if reflect.DeepEqual(s, settings) {
+		return settings, false
+	}
+	log.Println("Reloading settings from", file)
+	old := settings
+	settings, fileSettings, overridden = s, values, keys
+	return old, true
+}
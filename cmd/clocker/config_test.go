@@ -0,0 +1,389 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/config"
+	"github.com/gxben/clocker/pkg/hooks"
+	"github.com/gxben/clocker/pkg/invoice"
+	"github.com/gxben/clocker/pkg/uuid"
+)
+
+const (
+	benchTrackers = 12
+	benchSessions = 1000 // per tracker
+)
+
+// fillTrackers replaces the trackers with a few years worth of sessions.
+func fillTrackers(tb testing.TB, count, sessions int) {
+	tb.Helper()
+	trackers = []*Tracker{}
+	settings = Settings{Currency: "EUR"}
+	start := time.Date(2022, 1, 3, 9, 0, 0, 0, time.Local)
+	for i := 0; i < count; i++ {
+		t := &Tracker{
+			Label:    fmt.Sprintf("Project %d", i),
+			Since:    start,
+			Rate:     80,
+			Receiver: fmt.Sprintf("WBS-%04d", i),
+			Issue:    fmt.Sprintf("PROJ-%d", i),
+		}
+		for j := 0; j < sessions; j++ {
+			from := start.Add(time.Duration(j)*24*time.Hour + time.Duration(i)*15*time.Minute)
+			s := Session{Start: from, End: from.Add(90 * time.Minute), Locked: j%5 != 0}
+			t.Sessions = append(t.Sessions, s)
+			t.Elapsed += s.Duration()
+		}
+		addTracker(t)
+	}
+}
+
+func writeConfig(tb testing.TB) {
+	tb.Helper()
+	tb.Setenv("HOME", tb.TempDir())
+	store = nil
+	saveConfig()
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	fillTrackers(t, 3, 20)
+	writeConfig(t)
+	labels, ids := []string{}, []string{}
+	for _, t := range trackers {
+		labels = append(labels, t.Label)
+		ids = append(ids, t.ID)
+	}
+	session := trackers[0].Sessions[0].ID
+
+	trackers = []*Tracker{}
+	readConfig()
+
+	if len(trackers) != len(labels) {
+		t.Fatalf("read %d trackers, expected %d", len(trackers), len(labels))
+	}
+	for idx, tr := range trackers {
+		if tr.Label != labels[idx] || len(tr.Sessions) != 20 {
+			t.Errorf("tracker %d = %q with %d sessions, expected %q with 20", idx, tr.Label, len(tr.Sessions), labels[idx])
+		}
+		if tr.ID != ids[idx] {
+			t.Errorf("tracker %d ID = %q, expected %q", idx, tr.ID, ids[idx])
+		}
+	}
+	if id := trackers[0].Sessions[0].ID; id != session {
+		t.Errorf("session ID = %q, expected %q", id, session)
+	}
+	if settings.Currency != "EUR" {
+		t.Errorf("currency = %q, expected EUR", settings.Currency)
+	}
+}
+
+func TestDecodeLegacyConfig(t *testing.T) {
+	config, err := decodeConfig([]byte("- label: Acme\n  elapsed: 1h0m0s\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Trackers) != 1 || config.Trackers[0].Label != "Acme" || config.Trackers[0].Elapsed != time.Hour {
+		t.Errorf("legacy configuration decoded as %+v", config.Trackers)
+	}
+}
+
+func TestAddTrackerAssignsIDs(t *testing.T) {
+	trackers = []*Tracker{}
+	tr := &Tracker{Label: "Acme", Sessions: []Session{{Start: time.Now()}}}
+	addTracker(tr)
+
+	if !uuid.Valid(tr.ID) || !uuid.Valid(tr.Sessions[0].ID) {
+		t.Fatalf("IDs = %q / %q, expected UUIDs", tr.ID, tr.Sessions[0].ID)
+	}
+	if findTracker(tr.ID) != tr {
+		t.Error("tracker not found by ID")
+	}
+
+	other := NewTracker("Globex", 0)
+	if other.ID == tr.ID {
+		t.Error("trackers share the same ID")
+	}
+	DeleteTracker(tr.ID)
+	if len(trackers) != 1 || trackers[0] != other {
+		t.Error("deleted the wrong tracker")
+	}
+}
+
+func BenchmarkEncodeConfig(b *testing.B) {
+	fillTrackers(b, benchTrackers, benchSessions)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := encodeConfig()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeConfig(b *testing.B) {
+	fillTrackers(b, benchTrackers, benchSessions)
+	contents, err := encodeConfig()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(contents)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := decodeConfig(contents)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadConfig measures what startup goes through before showing the
+// window, from reading the file to binding the trackers.
+func BenchmarkReadConfig(b *testing.B) {
+	fillTrackers(b, benchTrackers, benchSessions)
+	writeConfig(b)
+	home, _ := os.UserHomeDir()
+	if info, err := os.Stat(filepath.Join(home, ConfigFile)); err == nil {
+		b.SetBytes(info.Size())
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trackers = []*Tracker{}
+		readConfig()
+	}
+	b.StopTimer()
+	if len(trackers) != benchTrackers {
+		b.Fatalf("read %d trackers, expected %d", len(trackers), benchTrackers)
+	}
+}
+
+func BenchmarkSaveConfig(b *testing.B) {
+	fillTrackers(b, benchTrackers, benchSessions)
+	b.Setenv("HOME", b.TempDir())
+	store = nil
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		saveConfig()
+	}
+}
+
+func BenchmarkExportEntries(b *testing.B) {
+	fillTrackers(b, benchTrackers, benchSessions)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		entries := exportEntries()
+		if len(entries) != benchTrackers*benchSessions {
+			b.Fatalf("exported %d entries", len(entries))
+		}
+	}
+}
+
+func TestStoreConfigCancelled(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ConfigFile)
+	err := storeConfig(context.Background(), file, []byte("trackers: []\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = storeConfig(ctx, file, []byte("truncat"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("storeConfig() = %v, expected cancellation", err)
+	}
+	contents, _ := os.ReadFile(file)
+	if string(contents) != "trackers: []\n" {
+		t.Errorf("cancelled save left %q", contents)
+	}
+	if _, err := os.Stat(file + ".tmp"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("cancelled save left its temporary file")
+	}
+
+	_, _, err = loadConfig(ctx, file)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("loadConfig() = %v, expected cancellation", err)
+	}
+}
+
+func TestStoreConfigAbandoned(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ConfigFile)
+	err := storeConfig(context.Background(), file, []byte("trackers: []\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cancelled while writing, or right after
+	contents := bytes.Repeat([]byte("# padding\n"), 4<<20)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond, cancel)
+	err = storeConfig(ctx, file, contents)
+	saved, _ := os.ReadFile(file)
+	if err == nil {
+		if !bytes.Equal(saved, contents) {
+			t.Fatalf("saved %d bytes out of %d", len(saved), len(contents))
+		}
+		return
+	}
+	if string(saved) != "trackers: []\n" {
+		t.Errorf("abandoned save left %d bytes", len(saved))
+	}
+	// the writer removes its file once it gives up
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(file + ".tmp"); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if time.Since(start) > StoreTimeout {
+			t.Fatal("abandoned save left its temporary file")
+		}
+	}
+}
+
+// resetOverrides drops the overrides a test set up once done.
+func resetOverrides(t *testing.T) {
+	t.Cleanup(func() {
+		settingOverrides, overridden, fileSettings = nil, nil, Settings{}
+	})
+}
+
+func TestSettingsSchema(t *testing.T) {
+	s := Settings{}
+	for _, setting := range settingsSchema {
+		if _, err := config.Get(&s, setting.Key); err != nil {
+			t.Error(err)
+		}
+	}
+	_, err := settingsLoader(nil).Apply(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.BudgetAlerts) != 2 || s.Storage != StorageYAML || s.Invoice.NextNumber != 1 {
+		t.Errorf("defaults not applied: %+v", s)
+	}
+}
+
+func TestEmptiedSettings(t *testing.T) {
+	resetOverrides(t)
+	fillTrackers(t, 1, 1)
+	settings.BudgetAlerts, settings.TimerPresets = nil, []int{}
+	writeConfig(t)
+	readConfig()
+	if len(settings.BudgetAlerts) != 0 || len(settings.TimerPresets) != 0 {
+		t.Errorf("alerts = %v, presets = %v, expected left empty", settings.BudgetAlerts, settings.TimerPresets)
+	}
+
+	// missing from the file
+	err := os.WriteFile(configFile(), []byte("currency: EUR\ntrackers: []\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readConfig()
+	if !slices.Equal(settings.BudgetAlerts, []int{80, 100}) || !slices.Equal(settings.TimerPresets, []int{25, 50, 90}) {
+		t.Errorf("alerts = %v, presets = %v, expected the defaults", settings.BudgetAlerts, settings.TimerPresets)
+	}
+}
+
+func TestSettingsValidate(t *testing.T) {
+	s := Settings{
+		Currency:    "EURO",
+		IdleMinutes: -1,
+		UIScale:     1000,
+		Storage:     "floppy",
+		Sync:        SyncSettings{Provider: "ftp"},
+		Hooks:       []hooks.Hook{{Event: "stop", Command: "true"}, {Event: "start"}},
+		Invoice:     invoice.Template{NumberFormat: "INV-%d-%d"},
+	}
+	s.Notifications.setRoute("lunch", []string{"pager"})
+	err := s.Validate()
+	if err == nil {
+		t.Fatal("validated invalid settings")
+	}
+	for _, problem := range []string{"currency", "idle", "scale", "storage", "sync provider", `event "stop"`, "no command", "alert", "channel", "invoice number format"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("%s problem not reported in:\n%s", problem, err)
+		}
+	}
+	if err := (&Settings{}).Validate(); err != nil {
+		t.Errorf("default settings invalid: %v", err)
+	}
+}
+
+func TestSettingsOverrides(t *testing.T) {
+	resetOverrides(t)
+	fillTrackers(t, 1, 1)
+	settings.IdleMinutes = 3
+	writeConfig(t)
+
+	t.Setenv("CLOCKER_IDLE_MINUTES", "7")
+	settingOverrides = config.Overrides{{Key: "currency", Value: "USD"}}
+	readConfig()
+	if settings.IdleMinutes != 7 || settings.Currency != "USD" {
+		t.Fatalf("idle = %d, currency = %s, expected the overrides", settings.IdleMinutes, settings.Currency)
+	}
+
+	saveConfig()
+	contents, err := os.ReadFile(configFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := decodeConfig(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.IdleMinutes != 3 || saved.Currency != "EUR" {
+		t.Errorf("saved idle = %d, currency = %s, expected the file values", saved.IdleMinutes, saved.Currency)
+	}
+}
+
+func TestReloadSettings(t *testing.T) {
+	resetOverrides(t)
+	fillTrackers(t, 1, 1)
+	writeConfig(t)
+	readConfig()
+	contents, err := os.ReadFile(configFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// edited outside of clocker
+	edit := func(setting string) {
+		t.Helper()
+		err := os.WriteFile(configFile(), []byte(setting+"\n"+string(contents)), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reloadSettings(nil, configFile())
+	}
+	trackers[0].Label = "Renamed in memory"
+
+	edit("idle_minutes: -5")
+	if settings.IdleMinutes != 0 {
+		t.Errorf("idle = %d, expected invalid settings ignored", settings.IdleMinutes)
+	}
+
+	edit("high_contrast: true")
+	if !settings.HighContrast || settings.Currency != "EUR" {
+		t.Errorf("settings = %+v, expected the edit applied", settings)
+	}
+	if trackers[0].Label != "Renamed in memory" {
+		t.Error("reloading settings replaced the trackers")
+	}
+}
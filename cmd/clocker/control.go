@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/client"
+	"github.com/gxben/clocker/pkg/engine"
+)
+
+var cancelControl context.CancelFunc
+
+// controlFile is where start and stop commands get read from, empty when
+// disabled.
+func controlFile() string {
+	return settingFile(settings.ControlFile)
+}
+
+// startControl (re)starts reading the commands appended to the control
+// file, if any, e.g. by client.Client.Start.
+func startControl(w fyne.Window) {
+	if cancelControl != nil {
+		cancelControl()
+		cancelControl = nil
+	}
+	file := controlFile()
+	if file == "" {
+		return
+	}
+	log.Println("Reading commands from", file)
+	ctx, cancel := context.WithCancel(appCtx)
+	cancelControl = cancel
+	go func() {
+		// streaming again after an invalid command
+		for ctx.Err() == nil {
+			err := client.StreamCommands(ctx, file, client.DefaultPoll, func(c client.Command) error {
+				runControlCommand(w, c)
+				return nil
+			})
+			if ctx.Err() == nil {
+				log.Println(err)
+			}
+		}
+	}()
+}
+
+// controlledTracker is the tracker with the given ID, or label.
+func controlledTracker(name string) *Tracker {
+	if t := findTracker(name); t != nil {
+		return t
+	}
+	for _, t := range trackers {
+		if strings.EqualFold(t.Label, strings.TrimSpace(name)) {
+			return t
+		}
+	}
+	return nil
+}
+
+// runControlCommand starts or stops a tracker as asked from the control
+// file, archived ones being left alone, as well as closed periods which
+// only the user can override.
+func runControlCommand(w fyne.Window, c client.Command) {
+	stateLock.Lock()
+	t := controlledTracker(c.Tracker)
+	changed := false
+	switch {
+	case t == nil:
+		log.Println("No tracker", c.Tracker, "to", c.Action)
+	case t.State == engine.Archived:
+		log.Println("Not going to", c.Action, "archived tracker", t.Label)
+	case c.Action == client.ActionStart && t.Running():
+	case c.Action == client.ActionStart:
+		if p, closed := closedPeriod(timeRange{From: time.Now()}); closed {
+			log.Println("Not starting", t.Label, "in closed period", p)
+			break
+		}
+		t.Start()
+		changed = true
+	case c.Action == client.ActionStop && t.Running():
+		t.Stop()
+		changed = true
+	case c.Action != client.ActionStart && c.Action != client.ActionStop:
+		log.Printf("Unknown command %q for %s", c.Action, t.Label)
+	}
+	stateLock.Unlock()
+	if !changed {
+		return
+	}
+	refreshTray(w)
+	if t.Running() {
+		attachTaskbar(w)
+	}
+	refreshTaskbar()
+	saveConfig()
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/client"
+	"github.com/gxben/clocker/pkg/engine"
+)
+
+func TestRunControlCommand(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex")
+	acme, globex := trackers[0], trackers[1]
+
+	runControlCommand(w, client.Command{Action: client.ActionStart, Tracker: "acme"})
+	if !acme.Running() || len(acme.Sessions) != 1 {
+		t.Fatalf("Acme not started by label: %+v", acme.Sessions)
+	}
+	// already running
+	runControlCommand(w, client.Command{Action: client.ActionStart, Tracker: acme.ID})
+	if len(acme.Sessions) != 1 {
+		t.Errorf("started twice: %+v", acme.Sessions)
+	}
+	runControlCommand(w, client.Command{Action: client.ActionStop, Tracker: acme.ID})
+	if acme.Running() || acme.Sessions[0].Open() {
+		t.Error("Acme not stopped by ID")
+	}
+
+	_ = globex.Fire(engine.Archive)
+	runControlCommand(w, client.Command{Action: client.ActionStart, Tracker: "Globex"})
+	runControlCommand(w, client.Command{Action: "restart", Tracker: "Acme"})
+	runControlCommand(w, client.Command{Action: client.ActionStart, Tracker: "Initech"})
+	if globex.Running() || acme.Running() {
+		t.Error("started by an invalid command")
+	}
+}
+
+func TestStartControl(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+	acme := trackers[0]
+	settings.ControlFile = filepath.Join(t.TempDir(), "control.jsonl")
+	startControl(w)
+	t.Cleanup(func() {
+		cancelControl()
+		cancelControl = nil
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	c := client.New("")
+	c.Control = settings.ControlFile
+	err := c.Start("Acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !acme.Running() {
+		if time.Now().After(deadline) {
+			t.Fatal("Acme not started from the control file")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
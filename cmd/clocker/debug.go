@@ -0,0 +1,35 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"log"
+	"net/http"
+	_ "net/http/pprof" // #nosec G108 -- only served on loopback in debug mode
+	"time"
+)
+
+const (
+	DebugFlag = "debug"
+	PprofAddr = "localhost:6060"
+)
+
+// startProfiling serves the runtime profiles, to look into startup time or
+// memory usage with go tool pprof.
+func startProfiling(addr string) {
+	srv := &http.Server{
+		Addr:              addr,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		log.Println("Serving profiles on", "http://"+addr+"/debug/pprof/")
+		err := srv.ListenAndServe()
+		if err != nil {
+			log.Println(err)
+		}
+	}()
+}
@@ -0,0 +1,325 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gxben/clocker/pkg/uuid"
+)
+
+const (
+	// BackupMaxAge is how old the latest backup may get before doctor
+	// suggests a new one.
+	BackupMaxAge = 30 * 24 * time.Hour
+)
+
+// diagnosis checks one aspect of the data, fix repairing what it can and
+// reporting what it did.
+type diagnosis struct {
+	name  string
+	check func(c *Config) []string
+	fix   func(c *Config) []string
+}
+
+// doctor runs the diagnoses against the data of a storage.
+type doctor struct {
+	ctx     context.Context
+	storage Storage
+	now     time.Time
+}
+
+func (d *doctor) diagnoses() []diagnosis {
+	return []diagnosis{
+		{"IDs", checkIDs, fixIDs},
+		// merged trackers get their sessions checked next
+		{"labels", checkLabels, fixLabels},
+		{"sessions", d.checkSessions, d.fixSessions},
+		// which tracker the time belongs to is for the user to tell
+		{"overlaps", d.checkOverlaps, nil},
+		{"totals", checkTotals, fixTotals},
+		// backups are taken before fixing anything
+		{"backups", d.checkBackups, nil},
+	}
+}
+
+func checkIDs(c *Config) []string {
+	problems := []string{}
+	trackerIDs, sessionIDs := map[string]bool{}, map[string]bool{}
+	for _, t := range c.Trackers {
+		switch {
+		case t.ID == "":
+			problems = append(problems, fmt.Sprintf("%s has no ID", t.Label))
+		case trackerIDs[t.ID]:
+			problems = append(problems, fmt.Sprintf("%s shares ID %s with another tracker", t.Label, t.ID))
+		}
+		trackerIDs[t.ID] = true
+		for _, s := range t.Sessions {
+			switch {
+			case s.ID == "":
+				problems = append(problems, fmt.Sprintf("%s: session %s has no ID", t.Label, s.Start.Format(time.DateTime)))
+			case sessionIDs[s.ID]:
+				problems = append(problems, fmt.Sprintf("%s: session %s shares ID %s with another session", t.Label, s.Start.Format(time.DateTime), s.ID))
+			}
+			sessionIDs[s.ID] = true
+		}
+	}
+	return problems
+}
+
+func fixIDs(c *Config) []string {
+	fixed := []string{}
+	trackerIDs, sessionIDs := map[string]bool{}, map[string]bool{}
+	for _, t := range c.Trackers {
+		if t.ID == "" || trackerIDs[t.ID] {
+			t.ID = uuid.New()
+			fixed = append(fixed, fmt.Sprintf("gave %s a new ID", t.Label))
+		}
+		trackerIDs[t.ID] = true
+		for idx := range t.Sessions {
+			s := &t.Sessions[idx]
+			if s.ID == "" || sessionIDs[s.ID] {
+				s.ID = uuid.New()
+				fixed = append(fixed, fmt.Sprintf("%s: gave session %s a new ID", t.Label, s.Start.Format(time.DateTime)))
+			}
+			sessionIDs[s.ID] = true
+		}
+	}
+	return fixed
+}
+
+// end returns when the session ended, running ones ending now.
+func (d *doctor) end(s *Session) time.Time {
+	if s.Open() {
+		return d.now
+	}
+	return s.End
+}
+
+func (d *doctor) checkSessions(c *Config) []string {
+	problems := []string{}
+	for _, t := range c.Trackers {
+		sessions := slices.Clone(t.Sessions)
+		sort.SliceStable(sessions, func(i, j int) bool {
+			return sessions[i].Start.Before(sessions[j].Start)
+		})
+		for idx := range sessions {
+			s := &sessions[idx]
+			if !s.Open() && s.End.Before(s.Start) {
+				problems = append(problems, fmt.Sprintf("%s: session %s ends before it starts", t.Label, s.Start.Format(time.DateTime)))
+				continue
+			}
+			if idx > 0 && s.Start.Before(d.end(&sessions[idx-1])) {
+				problems = append(problems, fmt.Sprintf("%s: sessions %s and %s overlap", t.Label,
+					sessions[idx-1].Start.Format(time.DateTime), s.Start.Format(time.DateTime)))
+			}
+		}
+	}
+	return problems
+}
+
+func (d *doctor) checkOverlaps(c *Config) []string {
+	problems := []string{}
+	for _, o := range findOverlaps(c.Trackers, d.now) {
+		problems = append(problems, o.String())
+	}
+	return problems
+}
+
+// fixSessions swaps reversed session bounds, and trims overlapping
+// sessions, approved ones being left as they are.
+func (d *doctor) fixSessions(c *Config) []string {
+	fixed := []string{}
+	for _, t := range c.Trackers {
+		for idx := range t.Sessions {
+			s := &t.Sessions[idx]
+			if !s.Open() && s.End.Before(s.Start) && !s.Locked {
+				s.Start, s.End = s.End, s.Start
+				fixed = append(fixed, fmt.Sprintf("%s: swapped the bounds of session %s", t.Label, s.Start.Format(time.DateTime)))
+			}
+		}
+		t.sortSessions()
+
+		kept := []Session{}
+		for _, s := range t.Sessions {
+			if len(kept) == 0 {
+				kept = append(kept, s)
+				continue
+			}
+			prev := &kept[len(kept)-1]
+			overlap := d.end(prev).Sub(s.Start)
+			switch {
+			case overlap <= 0:
+			case !s.Locked:
+				if !s.Open() && !s.End.After(d.end(prev)) {
+					fixed = append(fixed, fmt.Sprintf("%s: removed session %s, covered by session %s", t.Label,
+						s.Start.Format(time.DateTime), prev.Start.Format(time.DateTime)))
+					t.uncount(&s, s.Duration())
+					continue
+				}
+				fixed = append(fixed, fmt.Sprintf("%s: started session %s at %s", t.Label, s.Start.Format(time.DateTime), d.end(prev).Format(time.TimeOnly)))
+				t.uncount(&s, overlap)
+				s.Start = d.end(prev)
+			case !prev.Locked && !prev.Open():
+				fixed = append(fixed, fmt.Sprintf("%s: ended session %s at %s", t.Label, prev.Start.Format(time.DateTime), s.Start.Format(time.TimeOnly)))
+				t.uncount(prev, overlap)
+				prev.End = s.Start
+			}
+			kept = append(kept, s)
+		}
+		t.Sessions = kept
+	}
+	return fixed
+}
+
+// uncount removes time taken off a session from the counter.
+func (t *Tracker) uncount(s *Session, d time.Duration) {
+	if !s.Open() && t.inCounter(s) {
+		t.Elapsed -= d
+	}
+}
+
+// sessionTotal sums the ended sessions since the counter was last reset.
+func (t *Tracker) sessionTotal() time.Duration {
+	total := time.Duration(0)
+	for idx := range t.Sessions {
+		s := &t.Sessions[idx]
+		if !s.Open() && t.inCounter(s) {
+			total += s.Duration()
+		}
+	}
+	return total
+}
+
+func checkTotals(c *Config) []string {
+	problems := []string{}
+	for _, t := range c.Trackers {
+		if total := t.sessionTotal(); t.Elapsed != total {
+			problems = append(problems, fmt.Sprintf("%s counts %s, its sessions sum up to %s", t.Label, shortDur(t.Elapsed), shortDur(total)))
+		}
+	}
+	return problems
+}
+
+func fixTotals(c *Config) []string {
+	fixed := []string{}
+	for _, t := range c.Trackers {
+		if total := t.sessionTotal(); t.Elapsed != total {
+			fixed = append(fixed, fmt.Sprintf("%s now counts %s", t.Label, shortDur(total)))
+			t.Elapsed = total
+		}
+	}
+	return fixed
+}
+
+// latestBackup returns the most recent backup file, if any.
+func latestBackup() (string, time.Time) {
+	files, _ := filepath.Glob(homeFile(".clocker.backup-*"))
+	latest, at := "", time.Time{}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err == nil && info.ModTime().After(at) {
+			latest, at = file, info.ModTime()
+		}
+	}
+	return latest, at
+}
+
+func (d *doctor) checkBackups(c *Config) []string {
+	file, at := latestBackup()
+	switch {
+	case file == "":
+		return []string{"no backup found"}
+	case d.now.Sub(at) > BackupMaxAge:
+		return []string{fmt.Sprintf("latest backup %s is %d days old", file, int(d.now.Sub(at).Hours()/24))}
+	}
+	return nil
+}
+
+func doctorCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fix := flags.Bool("fix", false, "apply the fixes, backing up the data first")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(appCtx, StoreTimeout)
+	defer cancel()
+	s, config, _, err := openStorage(ctx)
+	defer s.Close()
+	if err != nil {
+		if file, _ := latestBackup(); file != "" {
+			return fmt.Errorf("can't read the %s data: %w\nthe latest backup is %s", s.Name(), err, file)
+		}
+		return fmt.Errorf("can't read the %s data: %w", s.Name(), err)
+	}
+	sessions := 0
+	for _, t := range config.Trackers {
+		sessions += len(t.Sessions)
+	}
+	fmt.Fprintf(stdout, "[ok] %s data parses: %d trackers, %d sessions\n", s.Name(), len(config.Trackers), sessions)
+
+	d := &doctor{ctx: ctx, storage: s, now: time.Now()}
+	found := 0
+	for _, diag := range d.diagnoses() {
+		problems := diag.check(&config)
+		found += len(problems)
+		if len(problems) == 0 {
+			fmt.Fprintf(stdout, "[ok] %s\n", diag.name)
+		}
+		for _, p := range problems {
+			fmt.Fprintf(stdout, "[!!] %s: %s\n", diag.name, p)
+		}
+	}
+	if found == 0 {
+		return nil
+	}
+	if !*fix {
+		return fmt.Errorf("%d problems found, run clocker doctor --fix to repair them", found)
+	}
+
+	// the data gets backed up before being saved, whatever the state of
+	// previous backups
+	file, err := backup(ctx, s)
+	if err != nil {
+		return fmt.Errorf("backing up: %w", err)
+	}
+	fmt.Fprintln(stdout, "[fixed] backed up to", file)
+	for _, diag := range d.diagnoses() {
+		if diag.fix == nil {
+			continue
+		}
+		for _, f := range diag.fix(&config) {
+			fmt.Fprintf(stdout, "[fixed] %s: %s\n", diag.name, f)
+		}
+	}
+	err = s.Save(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	left := 0
+	for _, diag := range d.diagnoses() {
+		for _, p := range diag.check(&config) {
+			fmt.Fprintf(stdout, "[!!] %s: %s\n", diag.name, p)
+			left++
+		}
+	}
+	if left > 0 {
+		return fmt.Errorf("%d problems left to repair by hand", left)
+	}
+	return nil
+}
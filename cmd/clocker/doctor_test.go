@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// saveBrokenTrackers saves trackers with all the problems doctor knows of.
+func saveBrokenTrackers(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	store = nil
+	settings = Settings{}
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	acme := &Tracker{ID: "tracker-1", Label: "Acme", Sessions: []Session{
+		{ID: "a", Start: at(9, 0), End: at(10, 0)},
+		// overlaps the first one by half an hour
+		{ID: "b", Start: at(9, 30), End: at(11, 0)},
+		// covered by the second one
+		{ID: "c", Start: at(9, 45), End: at(10, 15)},
+		{ID: "d", Start: at(15, 0), End: at(14, 0)},
+	}}
+	acme.Elapsed = 4 * time.Hour
+	globex := &Tracker{ID: "tracker-1", Label: "Globex", Elapsed: time.Hour, Sessions: []Session{
+		{ID: "a", Start: at(9, 0), End: at(10, 0), Locked: true},
+		{ID: "e", Start: at(9, 30), End: at(10, 30), Locked: true},
+	}}
+	globex.Elapsed = 2 * time.Hour
+	trackers = []*Tracker{acme, globex}
+	saveConfig()
+	trackers = []*Tracker{}
+}
+
+func TestDoctor(t *testing.T) {
+	saveBrokenTrackers(t)
+
+	var out bytes.Buffer
+	err := doctorCommand(nil, &out)
+	if err == nil || !strings.Contains(err.Error(), "--fix") {
+		t.Errorf("doctor() = %v, expected the problems counted", err)
+	}
+	for _, problem := range []string{
+		"Globex shares ID tracker-1",
+		"shares ID a with another session",
+		"Acme: sessions 2026-03-02 09:00:00 and 2026-03-02 09:30:00 overlap",
+		"Acme: session 2026-03-02 15:00:00 ends before it starts",
+		"Acme and Globex both track 2026-03-02 09:00 to 10:00",
+		"Acme counts 4h, its sessions sum up to 2h",
+		"no backup found",
+	} {
+		if !strings.Contains(out.String(), problem) {
+			t.Errorf("report misses %q:\n%s", problem, out.String())
+		}
+	}
+}
+
+func TestDoctorFix(t *testing.T) {
+	saveBrokenTrackers(t)
+
+	var out bytes.Buffer
+	err := doctorCommand([]string{"--fix"}, &out)
+	// approved sessions, and overlaps between trackers, are left to repair
+	// by hand
+	if err == nil || !strings.Contains(err.Error(), "4 problems left") {
+		t.Errorf("doctor --fix = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "[!!] sessions: Globex: sessions 2026-03-02 09:00:00 and 2026-03-02 09:30:00 overlap") {
+		t.Errorf("report misses the approved overlap:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "[!!] overlaps: Globex and Acme both track 2026-03-02 10:00 to 10:30") {
+		t.Errorf("report misses the overlap left by the fix:\n%s", out.String())
+	}
+
+	readConfig()
+	acme, globex := trackers[0], trackers[1]
+	if acme.ID == globex.ID || globex.Sessions[0].ID == acme.Sessions[0].ID {
+		t.Error("IDs still shared")
+	}
+	if len(acme.Sessions) != 3 {
+		t.Fatalf("Acme sessions = %+v, expected the covered one removed", acme.Sessions)
+	}
+	second := acme.Sessions[1]
+	if second.Start.Hour() != 10 || second.Start.Minute() != 0 || second.End.Hour() != 11 {
+		t.Errorf("overlapping session now %s - %s, expected 10:00 - 11:00", second.Start, second.End)
+	}
+	if acme.Sessions[2].End.Before(acme.Sessions[2].Start) {
+		t.Error("reversed session left as is")
+	}
+	if acme.Elapsed != 3*time.Hour || globex.Elapsed != 2*time.Hour {
+		t.Errorf("elapsed = %s, %s, expected the session sums", acme.Elapsed, globex.Elapsed)
+	}
+
+	out.Reset()
+	_ = doctorCommand(nil, &out)
+	if !strings.Contains(out.String(), "[ok] backups") {
+		t.Errorf("backup not taken:\n%s", out.String())
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gxben/clocker/pkg/client"
+	"github.com/gxben/clocker/pkg/engine"
+	"github.com/gxben/clocker/pkg/hooks"
+)
+
+var eventLogLock sync.Mutex
+
+// eventLogFile is where events get appended, empty when disabled.
+func eventLogFile() string {
+	return settingFile(settings.EventLog)
+}
+
+// settingFile is the file named by a setting, relative to the home
+// directory when starting with ~/.
+func settingFile(setting string) string {
+	file := strings.TrimSpace(setting)
+	if file == "" {
+		return ""
+	}
+	if rest, ok := strings.CutPrefix(file, "~/"); ok {
+		file = homeFile(rest)
+	}
+	return filepath.Clean(file)
+}
+
+func newEventRecord(p hooks.Payload, detail string) client.Record {
+	r := client.Record{
+		Event:     p.Event,
+		At:        p.At,
+		TrackerID: p.Tracker.ID,
+		Tracker:   p.Tracker.Label,
+		Issue:     p.Tracker.Issue,
+		Receiver:  p.Tracker.Receiver,
+		State:     p.Tracker.State,
+		Elapsed:   p.Elapsed.Seconds(),
+		Detail:    detail,
+	}
+	if s := p.Session; s != nil {
+		r.SessionID, r.Start, r.Seconds = s.ID, &s.Start, s.Duration.Seconds()
+		if !s.End.IsZero() {
+			r.End = &s.End
+		}
+	}
+	return r
+}
+
+// logEvent appends the event to the event log, if enabled, as a line of
+// JSON. The log is never rewritten, so that it can be tailed.
+func logEvent(p hooks.Payload, detail string) {
+	file := eventLogFile()
+	if file == "" {
+		return
+	}
+	line, err := json.Marshal(newEventRecord(p, detail))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	eventLogLock.Lock()
+	defer eventLogLock.Unlock()
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// logChange appends an audited change to the event log, lifecycle events
+// being logged along with their hooks.
+func logChange(action string, t *Tracker, detail string) {
+	if slices.ContainsFunc(engine.Events, func(e engine.Event) bool { return e.String() == action }) {
+		return
+	}
+	p := hooks.Payload{Event: action, At: time.Now()}
+	if t != nil {
+		p.Tracker, p.Elapsed = t.trackerInfo(), t.Current()
+	}
+	logEvent(p, detail)
+}
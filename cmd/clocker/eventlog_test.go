@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gxben/clocker/pkg/client"
+)
+
+func readEvents(t *testing.T, file string) []client.Record {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	events := []client.Record{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e client.Record
+		err = json.Unmarshal(scanner.Bytes(), &e)
+		if err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestEventLog(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+	acme := trackers[0]
+
+	// disabled by default
+	tapIcon(t, w, "Start Acme")
+	tapIcon(t, w, "Pause Acme")
+
+	file := filepath.Join(t.TempDir(), "events.jsonl")
+	settings.EventLog = file
+	tapIcon(t, w, "Start Acme")
+	tapIcon(t, w, "Pause Acme")
+	session := acme.Sessions[1]
+	audit("edit", acme, "session=%s", session.ID)
+	archiveTracker(w, acme)
+
+	events := readEvents(t, file)
+	names := []string{}
+	for _, e := range events {
+		names = append(names, e.Event)
+	}
+	if len(events) != 4 || names[0] != "start" || names[1] != "pause" || names[2] != "edit" || names[3] != "archive" {
+		t.Fatalf("logged %v, expected start, pause, edit, archive", names)
+	}
+	start, pause := events[0], events[1]
+	if start.TrackerID != acme.ID || start.Tracker != "Acme" || start.SessionID != session.ID || start.End != nil {
+		t.Errorf("start logged as %+v", start)
+	}
+	if pause.End == nil || !pause.End.Equal(session.End) || pause.State != "paused" {
+		t.Errorf("pause logged as %+v", pause)
+	}
+	if events[2].Detail != "session="+session.ID {
+		t.Errorf("edit logged as %+v", events[2])
+	}
+}
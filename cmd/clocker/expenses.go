@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/export"
+	"github.com/gxben/clocker/pkg/invoice"
+)
+
+type Expense struct {
+	Amount      float64   `yaml:"amount"`
+	Date        time.Time `yaml:"date"`
+	Description string    `yaml:"description,omitempty"`
+	Receipt     string    `yaml:"receipt,omitempty"`
+	// Invoice is the number of the invoice that billed the expense.
+	Invoice string `yaml:"invoice,omitempty"`
+}
+
+func (t *Tracker) AddExpense(e Expense) {
+	t.Expenses = append(t.Expenses, e)
+	refreshTotals()
+}
+
+func (t *Tracker) DeleteExpense(idx int) {
+	t.Expenses = append(t.Expenses[:idx], t.Expenses[idx+1:]...)
+	refreshTotals()
+}
+
+// parseDate returns the beginning of the day, which parsing it as midnight
+// would put on the day before where clocks go forward at that time.
+func parseDate(s string) (time.Time, error) {
+	d, err := time.Parse(invoice.DateFormat, strings.TrimSpace(s))
+	if err != nil {
+		return d, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+	return export.Day(time.Date(d.Year(), d.Month(), d.Day(), 12, 0, 0, 0, time.Local)), nil
+}
+
+func addExpenseDialog(w fyne.Window, t *Tracker, done func()) {
+	amount := newEntry()
+	amount.SetPlaceHolder("0.00")
+	date := newEntry()
+	date.SetText(time.Now().Format(invoice.DateFormat))
+	description := newEntry()
+	receipt := newEntry()
+	receipt.SetPlaceHolder("Receipt file")
+	items := []*widget.FormItem{
+		widget.NewFormItem("Amount", amount),
+		widget.NewFormItem("Date", date),
+		widget.NewFormItem("Description", description),
+		widget.NewFormItem("Receipt", browseEntry(w, receipt, []string{".pdf", ".png", ".jpg", ".jpeg"})),
+	}
+
+	showForm("New Expense", "Add", "Cancel", items, func(b bool) {
+		if !b {
+			return
+		}
+		a, err := parseRate(amount.Text)
+		if err != nil || a == 0 {
+			showError(fmt.Errorf("invalid amount %q", amount.Text), w)
+			return
+		}
+		d, err := parseDate(date.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		t.AddExpense(Expense{
+			Amount:      a,
+			Date:        d,
+			Description: description.Text,
+			Receipt:     strings.TrimSpace(receipt.Text),
+		})
+		log.Println("Adding expense to", t.Label, description.Text)
+		done()
+	}, w)
+	w.Canvas().Focus(amount)
+}
+
+func expensesDialog(w fyne.Window, t *Tracker) {
+	list := container.NewVBox()
+	var refresh func()
+	refresh = func() {
+		list.RemoveAll()
+		if len(t.Expenses) == 0 {
+			list.Add(widget.NewLabel("No expenses recorded yet."))
+		}
+		for idx, e := range t.Expenses {
+			text := fmt.Sprintf("%s  %s  %s", e.Date.Format(invoice.DateFormat),
+				formatAmount(e.Amount, t.BillingCurrency()), e.Description)
+			if e.Receipt != "" {
+				text += " (receipt)"
+			}
+			if e.Invoice != "" {
+				text += fmt.Sprintf(" (%s)", e.Invoice)
+			}
+			label := widget.NewLabel(text)
+			label.Alignment = leadingAlignment()
+			trash := newIconButton("Delete expense", theme.DeleteIcon(), func() {
+				t.DeleteExpense(idx)
+				refresh()
+			})
+			if e.Invoice != "" {
+				trash.Disable()
+			}
+			list.Add(newRow(nil, nil, trash, label))
+		}
+	}
+	refresh()
+
+	add := widget.NewButtonWithIcon("Add expense", theme.ContentAddIcon(), func() {
+		addExpenseDialog(w, t, refresh)
+	})
+
+	content := container.NewBorder(nil, add, nil, nil, container.NewVScroll(list))
+	d := dialog.NewCustom(fmt.Sprintf("%s expenses", t.Label), "Close", content, w)
+	d.Resize(fyne.NewSize(360, 300))
+	showDialog(d)
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/export"
+)
+
+func exportEntries() []export.Entry {
+	entries := []export.Entry{}
+	now := time.Now()
+	for _, t := range trackers {
+		for _, s := range t.Sessions {
+			end := s.End
+			if s.Open() {
+				end = now
+			}
+			entries = append(entries, export.Entry{
+				Tracker:  t.Label,
+				Receiver: t.Receiver,
+				Issue:    t.Issue,
+				Start:    s.Start,
+				End:      end,
+				Note:     s.Note,
+			})
+		}
+	}
+	return entries
+}
+
+func startOfWeek(t time.Time) time.Time {
+	day := export.Day(t)
+	offset := (int(day.Weekday()) - int(weekStart()) + 7) % 7
+	return export.AddDays(day, -offset)
+}
+
+const (
+	DefaultExportFormat = "grid"
+)
+
+func exportRequest(from, to time.Time) export.Request {
+	return export.Request{
+		Entries: exportEntries(),
+		From:    from,
+		To:      to,
+		SAP:     settings.SAP,
+		Tempo:   settings.Tempo,
+		Standup: settings.Standup,
+	}
+}
+
+func exportTimesheet(w fyne.Window, format export.Format, from, to time.Time) {
+	save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		if uc == nil {
+			return
+		}
+		defer uc.Close()
+
+		err = format.Export(uc, exportRequest(from, to))
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		log.Println("Exported timesheet to", uc.URI())
+	}, w)
+	save.SetFileName(exportFileName(from, to, format))
+	save.SetFilter(storage.NewExtensionFileFilter([]string{filepath.Ext(format.Extension())}))
+	save.Show()
+}
+
+// exportFileName names files after the exported week or year, or the days
+// of other periods.
+func exportFileName(from, to time.Time, format export.Format) string {
+	year := time.Date(from.Year(), 1, 1, 0, 0, 0, 0, from.Location())
+	var period string
+	switch {
+	case from.Equal(startOfWeek(from)) && to.Equal(export.AddDays(from, 7)):
+		period = weekOf(from)
+	case from.Equal(year) && to.Equal(year.AddDate(1, 0, 0)):
+		period = fmt.Sprint(from.Year())
+	default:
+		period = from.Format(export.DateFormat) + "_" + export.AddDays(to, -1).Format(export.DateFormat)
+	}
+	return fmt.Sprintf("timesheet-%s%s", period, format.Extension())
+}
+
+// reviewYear is the year to be reviewed, the past one while in January.
+func reviewYear(now time.Time) (time.Time, time.Time) {
+	year := now.Year()
+	if now.Month() == time.January {
+		year--
+	}
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+	return from, from.AddDate(1, 0, 0)
+}
+
+func exportDialog(w fyne.Window) {
+	format := widget.NewSelect(export.Titles(), nil)
+	if f, ok := export.Lookup(DefaultExportFormat); ok {
+		format.SetSelected(f.Title)
+	}
+	week := startOfWeek(time.Now())
+	first := newEntry()
+	first.SetText(week.Format(export.DateFormat))
+	last := newEntry()
+	last.SetText(export.AddDays(week, 6).Format(export.DateFormat))
+	// reviews default to the year, the past one while in January
+	format.OnChanged = func(title string) {
+		f, ok := export.Lookup(title)
+		if !ok || !strings.HasPrefix(f.Name, "review-") {
+			return
+		}
+		from, to := reviewYear(time.Now())
+		first.SetText(from.Format(export.DateFormat))
+		last.SetText(export.AddDays(to, -1).Format(export.DateFormat))
+	}
+	items := []*widget.FormItem{
+		widget.NewFormItem("Format", format),
+		widget.NewFormItem("From", first),
+		{Text: "To", Widget: last, HintText: "Last day exported, this week by default"},
+	}
+
+	showForm("Export Timesheet", "Export", "Cancel", items, func(b bool) {
+		if !b {
+			return
+		}
+		f, ok := export.Lookup(format.Selected)
+		if !ok {
+			return
+		}
+		from, err := parseDate(first.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		to, err := parseDate(last.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		to = export.AddDays(to, 1)
+		if !to.After(from) {
+			showError(errors.New("nothing to export, the last day is before the first one"), w)
+			return
+		}
+		exportTimesheet(w, f, from, to)
+	}, w)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/fuzzy"
+)
+
+var (
+	// filterQuery narrows the trackers listed in the main window
+	filterQuery string
+	// trackerList holds the rows refreshed as the filter gets typed
+	trackerList *fyne.Container
+)
+
+func filtering() bool {
+	return strings.TrimSpace(filterQuery) != ""
+}
+
+// trackerScore ranks the tracker against the query, matching its label,
+// client and issue fuzzily, then the notes of its sessions word for word.
+func trackerScore(t *Tracker, query string) (int, bool) {
+	if score, ok := fuzzy.Match(query, t.Label, t.Receiver, t.Issue); ok {
+		return score, true
+	}
+	terms := strings.Fields(strings.ToLower(query))
+	for _, s := range t.Sessions {
+		if s.Note != "" && matchesTerms(s.Note, terms) {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// filterTrackers keeps the trackers matching the filter, best matches
+// first, or all of them in order without a filter.
+func filterTrackers(list []*Tracker) []*Tracker {
+	if !filtering() {
+		return list
+	}
+	type match struct {
+		t     *Tracker
+		score int
+	}
+	matches := []match{}
+	for _, t := range list {
+		if score, ok := trackerScore(t, filterQuery); ok {
+			matches = append(matches, match{t, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	filtered := []*Tracker{}
+	for _, m := range matches {
+		filtered = append(filtered, m.t)
+	}
+	return filtered
+}
+
+// listedTrackers are the visible trackers matching the filter, as listed
+// in the main window and driven by the keyboard.
+func listedTrackers() []*Tracker {
+	return filterTrackers(visibleTrackers())
+}
+
+// refreshList lists the trackers matching the filter again, leaving the
+// filter box alone for the typing to go on.
+func refreshList(w fyne.Window) {
+	if trackerList == nil {
+		return
+	}
+	trackerList.Objects = makeTrackerList(w).Objects
+	trackerList.Refresh()
+	selectRow(0)
+}
+
+func makeFilterBox(w fyne.Window) fyne.CanvasObject {
+	box := newEntry()
+	box.SetText(filterQuery)
+	box.SetPlaceHolder("Filter trackers, clients, issues and notes")
+	box.OnChanged = func(text string) {
+		filterQuery = text
+		refreshList(w)
+	}
+	return box
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+
+	"github.com/gxben/clocker/pkg/engine"
+)
+
+func TestFilterTrackers(t *testing.T) {
+	w := newTestWindow(t, "Acme billing", "Globex", "Initech", "Billing archive")
+	acme, globex, initech, archived := trackers[0], trackers[1], trackers[2], trackers[3]
+	globex.Receiver = "Big Invoices Ltd"
+	initech.Sessions = []Session{{ID: "a", Start: time.Now().Add(-time.Hour), End: time.Now(), Note: "Reviewed the billing export"}}
+	archived.State = engine.Archived
+	update(w)
+
+	filterQuery = "bil"
+	listed := listedTrackers()
+	if len(listed) != 3 || listed[0] != acme || listed[1] != globex || listed[2] != initech {
+		t.Errorf("listed %v, expected Acme billing, then Globex for its client, then Initech for its notes", listed)
+	}
+	filterQuery = "xyz"
+	if len(listedTrackers()) != 0 {
+		t.Error("listed trackers not matching")
+	}
+
+	// typing in the box refreshes the list and its groups
+	filterQuery = ""
+	update(w)
+	var box *entry
+	for _, o := range test.LaidOutObjects(w.Content()) {
+		if e, ok := o.(*entry); ok && e.PlaceHolder == "Filter trackers, clients, issues and notes" {
+			box = e
+		}
+	}
+	if box == nil {
+		t.Fatal("no filter box in window")
+	}
+	w.Canvas().Focus(box)
+	test.Type(box, "billng")
+	buttons := iconButtons(w)
+	if _, ok := buttons["Start Acme billing"]; !ok {
+		t.Error("matching tracker not listed")
+	}
+	if _, ok := buttons["Start Globex"]; ok {
+		t.Error("tracker not matching still listed")
+	}
+	if _, ok := buttons["Restore Billing archive"]; !ok {
+		t.Error("matching archived tracker not shown")
+	}
+	if w.Canvas().Focused() != box {
+		t.Error("refreshing the list lost the focus of the filter box")
+	}
+
+	typedKey(w, &fyne.KeyEvent{Name: fyne.KeySpace})
+	if !acme.Running() {
+		t.Error("keyboard doesn't drive the first match")
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/gxben/clocker/pkg/engine"
+	"github.com/gxben/clocker/pkg/hooks"
+)
+
+func (t *Tracker) trackerInfo() hooks.TrackerInfo {
+	return hooks.TrackerInfo{
+		ID:       t.ID,
+		Label:    t.Label,
+		Issue:    t.Issue,
+		Receiver: t.Receiver,
+		State:    t.State.String(),
+	}
+}
+
+// hookPayload describes the tracker after the event, along with the
+// session it started or ended.
+func (t *Tracker) hookPayload(e engine.Event) hooks.Payload {
+	p := hooks.Payload{
+		Event:   e.String(),
+		At:      time.Now(),
+		Tracker: t.trackerInfo(),
+		Elapsed: t.Current(),
+	}
+	if (e == engine.Start || e == engine.Pause) && len(t.Sessions) > 0 {
+		s := &t.Sessions[len(t.Sessions)-1]
+		p.Session = &hooks.SessionInfo{ID: s.ID, Start: s.Start, End: s.End, Duration: s.Duration()}
+	}
+	return p
+}
+
+// fireHooks logs the event, then runs its configured hooks in the
+// background.
+func fireHooks(t *Tracker, e engine.Event) {
+	p := t.hookPayload(e)
+	logEvent(p, "")
+	if len(settings.Hooks) == 0 {
+		return
+	}
+	r := hooks.Runner{Hooks: slices.Clone(settings.Hooks), Logger: log.Default()}
+	go func() {
+		// failures are logged along with the output
+		_ = r.Fire(appCtx, p)
+	}()
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/engine"
+	"github.com/gxben/clocker/pkg/hooks"
+)
+
+func TestHookPayload(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	acme.Issue = "PROJ-1"
+	acme.Start()
+	acme.Sessions[0].Start = acme.Sessions[0].Start.Add(-time.Hour)
+	acme.Stop()
+
+	p := acme.hookPayload(engine.Pause)
+	if p.Event != "pause" || p.Tracker.ID != acme.ID || p.Tracker.Issue != "PROJ-1" || p.Tracker.State != "paused" {
+		t.Errorf("payload = %+v", p)
+	}
+	if p.Session == nil || p.Session.ID != acme.Sessions[0].ID || p.Session.End.IsZero() || p.Session.Duration < time.Hour {
+		t.Errorf("session = %+v, expected the ended one", p.Session)
+	}
+	if p.Elapsed.Hours() < 1 {
+		t.Errorf("elapsed = %s, expected the session counted", p.Elapsed)
+	}
+	if acme.hookPayload(engine.Archive).Session != nil {
+		t.Error("archiving has no session")
+	}
+}
+
+func TestFireHooks(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "js" {
+		t.Skip("commands written for a POSIX shell")
+	}
+	newTestWindow(t, "Acme")
+	out := filepath.Join(t.TempDir(), "events")
+	settings.Hooks = []hooks.Hook{
+		{Event: "start", Command: "echo {{.Event}} {{quote .Tracker.Label}} >> " + out},
+	}
+
+	trackers[0].Start()
+	trackers[0].Stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		contents, _ := os.ReadFile(out)
+		if string(contents) == "start Acme\n" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	contents, _ := os.ReadFile(out)
+	t.Errorf("hooks wrote %q, expected the start one only", contents)
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/clock"
+	"github.com/gxben/clocker/pkg/idle"
+)
+
+var idleMonitor idle.Monitor
+
+// DiscardIdle removes the idle time from the running session, splitting it
+// around the time the user was away.
+func (t *Tracker) DiscardIdle(since, until time.Time) {
+	if len(t.Sessions) == 0 {
+		return
+	}
+	s := &t.Sessions[len(t.Sessions)-1]
+	if !s.Open() {
+		return
+	}
+	if since.Before(s.Start) {
+		since = s.Start
+	}
+	if !until.After(since) {
+		return
+	}
+
+	if s.Start.Before(since) {
+		t.closeSession(since)
+		recordSession(t, t.Sessions[len(t.Sessions)-1])
+		t.Sessions = append(t.Sessions, newSession(until))
+	} else {
+		s.Start = until
+	}
+	recordSession(t, t.Sessions[len(t.Sessions)-1])
+
+	refreshClock()
+	log.Println("Discarded idle time from", t.Label, until.Sub(since))
+}
+
+// ReallocateIdle moves the idle time from the running trackers to the one
+// with the given label, e.g. lunch or a meeting, created if there is none.
+// The time can't go to a running tracker, it would count twice.
+func ReallocateIdle(active []*Tracker, label string, since, until time.Time) (*Tracker, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return nil, errors.New("no tracker to move the idle time to")
+	}
+	t := findLabel(label)
+	created := t == nil
+	if created {
+		log.Println("Adding new clock", label)
+		t = NewTracker(label, 0)
+	}
+	if t.Running() {
+		return t, fmt.Errorf("%s is running, stop it first", t.Label)
+	}
+	err := t.AddSession(since, until)
+	if err != nil {
+		return t, err
+	}
+	if created {
+		// the store only takes sessions of the trackers it knows of
+		saveConfig()
+	} else if idx := slices.IndexFunc(t.Sessions, func(s Session) bool {
+		return s.Start.Equal(since) && s.End.Equal(until)
+	}); idx >= 0 {
+		recordSession(t, t.Sessions[idx])
+	}
+	for _, a := range active {
+		a.DiscardIdle(since, until)
+	}
+	return t, nil
+}
+
+// reallocateIdleDialog asks for the tracker the idle time goes to.
+func reallocateIdleDialog(w fyne.Window, active []*Tracker, since, until time.Time) {
+	labels := []string{}
+	for _, t := range visibleTrackers() {
+		if !t.Running() {
+			labels = append(labels, t.Label)
+		}
+	}
+	target := widget.NewSelectEntry(labels)
+	target.SetPlaceHolder("Lunch")
+	text := lang.LocalizeKey("idle.move", "Record the {{.Duration}} away on another tracker, created if needed.", map[string]any{
+		"Duration": shortDur(until.Sub(since).Round(time.Minute)),
+	})
+	items := []*widget.FormItem{
+		widget.NewFormItem("", widget.NewLabel(text)),
+		widget.NewFormItem(lang.L("Tracker"), target),
+	}
+	showForm(lang.L("Move to…"), lang.L("Move"), lang.L("Cancel"), items, func(b bool) {
+		if !b {
+			return
+		}
+		t := findLabel(target.Text)
+		guardClosedPeriods(w, t, "move the idle time", []timeRange{{since, until}}, func() {
+			_, err := ReallocateIdle(active, target.Text, since, until)
+			if err != nil {
+				showError(err, w)
+			}
+			update(w)
+		})
+	}, w)
+}
+
+func idleReturnDialog(w fyne.Window, since, until time.Time) {
+	active := []*Tracker{}
+	labels := []string{}
+	for _, t := range trackers {
+		if t.Running() {
+			active = append(active, t)
+			labels = append(labels, t.Label)
+		}
+	}
+	if len(active) == 0 {
+		return
+	}
+
+	idleTime := until.Sub(since).Round(time.Minute)
+	text := lang.LocalizeKey("idle.return", "You have been away for {{.Duration}} since {{.Since}}.\nKeep this time on {{.Trackers}} ?", map[string]any{
+		"Duration": shortDur(idleTime),
+		"Since":    since.Format("15:04"),
+		"Trackers": strings.Join(labels, ", "),
+	})
+	notifyUser(AlertIdle, lang.L("Welcome back"), text)
+	d := dialog.NewCustomWithoutButtons(lang.L("Welcome back"), widget.NewLabel(text), w)
+	d.SetButtons([]fyne.CanvasObject{
+		widget.NewButton(lang.L("Discard"), func() {
+			d.Hide()
+			for _, t := range active {
+				t.DiscardIdle(since, until)
+			}
+			saveConfig()
+		}),
+		widget.NewButton(lang.L("Move to…"), func() {
+			d.Hide()
+			reallocateIdleDialog(w, active, since, until)
+		}),
+		&widget.Button{Text: lang.L("Keep"), Importance: widget.HighImportance, OnTapped: d.Hide},
+	})
+	showDialog(d)
+}
+
+// startIdleDetection (re)starts watching user activity, according to the
+// configured idle timeout.
+func startIdleDetection(w fyne.Window) {
+	if idleMonitor != nil {
+		_ = idleMonitor.Close()
+		idleMonitor = nil
+	}
+	if settings.IdleMinutes <= 0 {
+		return
+	}
+
+	m, err := idle.New(time.Duration(settings.IdleMinutes)*time.Minute, clock.Real)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Println("Detecting idle time using", m.Name())
+	idleMonitor = m
+
+	go func() {
+		since := time.Time{}
+		for e := range m.Events() {
+			if e.Idle {
+				since = e.Since
+				continue
+			}
+			if since.IsZero() {
+				continue
+			}
+			idleReturnDialog(w, since, e.Since)
+			since = time.Time{}
+		}
+	}()
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestReallocateIdle(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Meeting")
+	acme, meeting := trackers[0], trackers[1]
+	tapIcon(t, w, "Start Acme")
+	start := time.Now().Add(-2 * time.Hour)
+	acme.Sessions[0].Start = start
+	since, until := start.Add(30*time.Minute), start.Add(90*time.Minute)
+
+	idleReturnDialog(w, since, until)
+	tapButton(t, w, "Move to…")
+	for _, o := range test.LaidOutObjects(topDialog(t, w)) {
+		if e, ok := o.(*widget.SelectEntry); ok {
+			e.SetText("meeting")
+		}
+	}
+	tapButton(t, w, "Move")
+
+	if len(meeting.Sessions) != 1 || !meeting.Sessions[0].Start.Equal(since) || !meeting.Sessions[0].End.Equal(until) {
+		t.Fatalf("meeting sessions %+v, expected the idle time", meeting.Sessions)
+	}
+	if len(acme.Sessions) != 2 || !acme.Sessions[0].End.Equal(since) || !acme.Sessions[1].Start.Equal(until) || !acme.Running() {
+		t.Errorf("acme sessions %+v, expected split around the idle time and still running", acme.Sessions)
+	}
+
+	// a new tracker gets the idle time, unless it overlaps
+	lunch, err := ReallocateIdle([]*Tracker{acme}, " Lunch ", until.Add(5*time.Minute), until.Add(20*time.Minute))
+	if err != nil || lunch.Label != "Lunch" || len(trackers) != 3 {
+		t.Fatalf("idle time not moved to a new tracker: %v", err)
+	}
+	_, err = ReallocateIdle([]*Tracker{acme}, "Lunch", until.Add(10*time.Minute), until.Add(30*time.Minute))
+	if err == nil {
+		t.Error("moved idle time over a session")
+	}
+	if len(acme.Sessions) != 3 {
+		t.Errorf("failed move still discarded the idle time: %+v", acme.Sessions)
+	}
+
+	// nor to a running tracker, already counting it
+	tapIcon(t, w, "Start Meeting")
+	_, err = ReallocateIdle([]*Tracker{acme}, "Meeting", until.Add(40*time.Minute), until.Add(50*time.Minute))
+	if err == nil || len(meeting.Sessions) != 2 {
+		t.Errorf("moved idle time to a running tracker: %v, %+v", err, meeting.Sessions)
+	}
+
+	config := storedConfig(t)
+	if len(config.Trackers) != 3 || len(config.Trackers[0].Sessions) != 3 || len(config.Trackers[2].Sessions) != 1 {
+		t.Errorf("stored trackers %+v, expected the moved idle time", config.Trackers)
+	}
+}
+
+func TestDiscardIdle(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+	acme := trackers[0]
+	tapIcon(t, w, "Start Acme")
+	start := time.Now().Add(-2 * time.Hour)
+	acme.Sessions[0].Start = start
+	since, until := start.Add(30*time.Minute), start.Add(90*time.Minute)
+
+	idleReturnDialog(w, since, until)
+	tapButton(t, w, "Discard")
+
+	if len(acme.Sessions) != 2 || !acme.Sessions[0].End.Equal(since) || !acme.Sessions[1].Start.Equal(until) || !acme.Running() {
+		t.Errorf("acme sessions %+v, expected split around the idle time and still running", acme.Sessions)
+	}
+	config := storedConfig(t)
+	if len(config.Trackers) != 1 || len(config.Trackers[0].Sessions) != 2 || !config.Trackers[0].Sessions[1].Start.Equal(until) {
+		t.Errorf("stored trackers %+v, expected the idle time discarded", config.Trackers)
+	}
+}
+
+func storedConfig(t *testing.T) Config {
+	t.Helper()
+	config, _, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
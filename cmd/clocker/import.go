@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+
+	"github.com/gxben/clocker/pkg/importer"
+)
+
+const (
+	// PreviewConflicts is how many conflicts the preview details.
+	PreviewConflicts = 10
+)
+
+// planImport matches the change set with the current trackers, leaving out
+// sessions in closed periods.
+func planImport(cs importer.ChangeSet) importer.Plan {
+	existing := []importer.Tracker{}
+	for _, t := range trackers {
+		e := importer.Tracker{ID: t.ID, Label: t.Label, Issue: t.Issue, Receiver: t.Receiver}
+		for _, s := range t.Sessions {
+			e.Sessions = append(e.Sessions, importer.Session{Start: s.Start, End: s.End})
+		}
+		existing = append(existing, e)
+	}
+	closed := func(tracker string, s importer.Session) string {
+		if p, ok := closedPeriod(timeRange{s.Start, s.End}); ok {
+			return fmt.Sprintf("period %s is closed", p)
+		}
+		return ""
+	}
+	return importer.NewPlan(cs, existing, closed)
+}
+
+// previewImport details the plan, for the user to confirm it.
+func previewImport(plan importer.Plan) string {
+	lines := []string{plan.Summary()}
+	for idx, c := range plan.Conflicts {
+		if idx == PreviewConflicts {
+			lines = append(lines, fmt.Sprintf("and %d more", len(plan.Conflicts)-idx))
+			break
+		}
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (t *Tracker) importSessions(sessions []importer.Session) {
+	for _, s := range sessions {
+		session := newSession(s.Start)
+		session.End = s.End
+		t.Sessions = append(t.Sessions, session)
+		if t.inCounter(&session) {
+			t.Elapsed += session.Duration()
+		}
+	}
+	t.sortSessions()
+	t.refreshElapsed()
+}
+
+// applyImport creates the new trackers and adds the sessions.
+func applyImport(plan importer.Plan, format string) {
+	for id, sessions := range plan.Sessions {
+		t := findTracker(id)
+		if t == nil {
+			continue
+		}
+		t.importSessions(sessions)
+		audit("import", t, "format=%s sessions=%d", format, len(sessions))
+	}
+	for _, n := range plan.New {
+		t := NewTracker(n.Label, 0)
+		t.Issue = n.Issue
+		t.Receiver = n.Receiver
+		t.importSessions(n.Sessions)
+		audit("import", t, "format=%s sessions=%d", format, len(n.Sessions))
+	}
+	refreshTotals()
+	log.Println("Imported", plan.Summary())
+}
+
+func readImport(format importer.Format, r io.Reader) (importer.Plan, error) {
+	cs, err := format.Import(r)
+	if err != nil {
+		return importer.Plan{}, err
+	}
+	return planImport(cs), nil
+}
+
+func importExtensions() []string {
+	extensions := []string{}
+	for _, f := range importer.Formats() {
+		for _, ext := range f.Extensions {
+			ext = filepath.Ext(ext)
+			if !slices.Contains(extensions, ext) {
+				extensions = append(extensions, ext)
+			}
+		}
+	}
+	return extensions
+}
+
+func importDialog(w fyne.Window) {
+	open := dialog.NewFileOpen(func(uc fyne.URIReadCloser, err error) {
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		if uc == nil {
+			return
+		}
+		defer uc.Close()
+
+		format, ok := importer.Detect(uc.URI().Name())
+		if !ok {
+			showError(fmt.Errorf("can't tell the format of %s", uc.URI().Name()), w)
+			return
+		}
+		plan, err := readImport(format, uc)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		if plan.Empty() {
+			showInformation("Nothing to import", previewImport(plan), w)
+			return
+		}
+		showConfirm("Import "+format.Title+" ?", previewImport(plan), func(b bool) {
+			if !b {
+				return
+			}
+			applyImport(plan, format.Name)
+			update(w)
+			mergeDuplicatesDialog(w)
+		}, w)
+	}, w)
+	open.SetFilter(storage.NewExtensionFileFilter(importExtensions()))
+	open.Show()
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/invoice"
+)
+
+// NoReceiver is the client of the trackers without any receiver.
+const NoReceiver = "No client"
+
+func receiverOf(t *Tracker) string {
+	if t.Receiver == "" {
+		return NoReceiver
+	}
+	return t.Receiver
+}
+
+// invoiceReceivers lists the clients with something to bill, sorted.
+func invoiceReceivers() []string {
+	receivers := []string{}
+	for _, t := range trackers {
+		r := receiverOf(t)
+		if !slices.Contains(receivers, r) && len(invoiceLines(r)) > 0 {
+			receivers = append(receivers, r)
+		}
+	}
+	sort.Strings(receivers)
+	return receivers
+}
+
+// invoiceLines lists what the trackers of the receiver bill.
+func invoiceLines(receiver string) []invoice.Line {
+	lines := []invoice.Line{}
+	for _, t := range trackers {
+		if receiverOf(t) != receiver {
+			continue
+		}
+		unbilled := t.unbilled()
+		rates := []float64{}
+		for rate := range unbilled {
+			rates = append(rates, rate)
+		}
+		sort.Float64s(rates)
+		for _, rate := range rates {
+			spent := time.Duration(0)
+			for _, s := range unbilled[rate] {
+				spent += s.Duration()
+			}
+			lines = append(lines, invoice.Line{
+				Description: t.Label,
+				Quantity:    spent.Hours(),
+				Unit:        "h",
+				Rate:        rate,
+				Currency:    t.BillingCurrency(),
+			})
+		}
+		for _, e := range t.Expenses {
+			if e.Invoice != "" {
+				continue
+			}
+			lines = append(lines, invoice.Line{
+				Description: fmt.Sprintf("%s: %s (%s)", t.Label, e.Description, e.Date.Format(invoice.DateFormat)),
+				Quantity:    1,
+				Rate:        e.Amount,
+				Currency:    t.BillingCurrency(),
+			})
+		}
+	}
+	return lines
+}
+
+// markInvoiced marks what the trackers of the receiver have not been
+// billed yet as billed by the invoice, i.e. what invoiceLines listed.
+func markInvoiced(receiver, number string) {
+	for _, t := range trackers {
+		if receiverOf(t) == receiver {
+			t.LockInvoiced(number)
+			t.InvoiceExpenses(number)
+		}
+	}
+}
+
+func exportInvoice(w fyne.Window, receiver, client string) {
+	tpl := settings.Invoice
+	inv := invoice.Invoice{
+		Number: tpl.Next(),
+		Date:   time.Now(),
+		Client: client,
+	}
+
+	save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		if uc == nil {
+			return
+		}
+		defer uc.Close()
+
+		// the lines get listed and marked at once, sessions ending while
+		// the file was picked going on the invoice
+		inv.Lines = invoiceLines(receiver)
+		err = invoice.Render(uc, tpl, inv)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		log.Println("Exported invoice", inv.Number, "to", uc.URI())
+
+		// only consume the invoice number once it has been issued
+		settings.Invoice.NextNumber = tpl.NextNumber
+		markInvoiced(receiver, inv.Number)
+		saveConfig()
+	}, w)
+	save.SetFileName(inv.Number + ".pdf")
+	save.SetFilter(storage.NewExtensionFileFilter([]string{".pdf"}))
+	save.Show()
+}
+
+func invoiceDialog(w fyne.Window) {
+	receivers := invoiceReceivers()
+	if len(receivers) == 0 {
+		showInformation("Export Invoice", "There is no billable time to invoice yet.", w)
+		return
+	}
+
+	tpl := settings.Invoice
+	client := newMultiLineEntry()
+	client.SetPlaceHolder("Client name and address")
+	receiver := widget.NewSelect(receivers, func(r string) {
+		if r != NoReceiver {
+			client.SetText(r)
+		}
+	})
+	receiver.SetSelected(receivers[0])
+	items := []*widget.FormItem{
+		widget.NewFormItem("Number", widget.NewLabel(tpl.Next())),
+		{Text: "Client", Widget: receiver, HintText: "Only the trackers with this receiver get billed"},
+		widget.NewFormItem("Bill to", client),
+	}
+
+	showForm("Export Invoice", "Export", "Cancel", items, func(b bool) {
+		if !b {
+			return
+		}
+		exportInvoice(w, receiver.Selected, client.Text)
+	}, w)
+}
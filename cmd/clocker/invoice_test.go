@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestInvoiceReceivers(t *testing.T) {
+	newTestWindow(t, "Acme", "Globex", "Initech", "Hooli")
+	acme, globex, initech := trackers[0], trackers[1], trackers[2]
+	acme.Receiver, globex.Receiver = "Big Corp", "Big Corp"
+	acme.Rate, globex.Rate, initech.Rate = 80, 100, 50
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	for _, tracker := range trackers {
+		tracker.Sessions = []Session{{ID: tracker.Label, Start: day, End: day.Add(time.Hour)}}
+	}
+
+	// hooli bills nothing, having no rate
+	if receivers := invoiceReceivers(); !slices.Equal(receivers, []string{"Big Corp", NoReceiver}) {
+		t.Errorf("receivers = %v", receivers)
+	}
+	lines := invoiceLines("Big Corp")
+	if len(lines) != 2 || lines[0].Description != "Acme" || lines[1].Description != "Globex" {
+		t.Errorf("Big Corp billed %+v", lines)
+	}
+	if lines = invoiceLines(NoReceiver); len(lines) != 1 || lines[0].Description != "Initech" {
+		t.Errorf("trackers without a receiver billed %+v", lines)
+	}
+}
+
+func TestInvoiceExpenses(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	acme.AddExpense(Expense{Amount: 45.5, Date: day, Description: "train"})
+
+	if lines := invoiceLines(NoReceiver); len(lines) != 1 || lines[0].Rate != 45.5 {
+		t.Fatalf("billed %+v, expected the expense", lines)
+	}
+	acme.InvoiceExpenses("INV-0001")
+	if lines := invoiceLines(NoReceiver); len(lines) != 0 || acme.ExpensesAmount() != 0 {
+		t.Errorf("billed %+v again", lines)
+	}
+
+	// expenses added later go on the next invoice only
+	acme.AddExpense(Expense{Amount: 12, Date: day, Description: "taxi"})
+	lines := invoiceLines(NoReceiver)
+	if len(lines) != 1 || lines[0].Rate != 12 {
+		t.Errorf("billed %+v, expected the taxi only", lines)
+	}
+	acme.InvoiceExpenses("INV-0002")
+	if acme.Expenses[0].Invoice != "INV-0001" || acme.Expenses[1].Invoice != "INV-0002" {
+		t.Errorf("expenses = %+v", acme.Expenses)
+	}
+}
+
+func TestInvoiceSessions(t *testing.T) {
+	newTestWindow(t, "Acme", "Hooli")
+	acme, hooli := trackers[0], trackers[1]
+	acme.Rate = 80
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	acme.Sessions = []Session{
+		{ID: "a", Start: day, End: day.Add(time.Hour)},
+		{ID: "b", Start: day.Add(2 * time.Hour), End: day.Add(4 * time.Hour)},
+		{ID: "c", Start: day.AddDate(0, 0, 7)},
+	}
+	hooli.Sessions = []Session{{ID: "d", Start: day, End: day.Add(time.Hour)}}
+	// approved sessions are still to bill
+	acme.LockWeek(weekOf(day))
+
+	lines := invoiceLines(NoReceiver)
+	if len(lines) != 1 || lines[0].Quantity != 3 {
+		t.Fatalf("billed %+v, expected the 3 closed hours", lines)
+	}
+	markInvoiced(NoReceiver, "INV-0001")
+	for _, s := range acme.Sessions[:2] {
+		if !s.Locked || s.Invoice != "INV-0001" {
+			t.Errorf("billed session %+v not marked", s)
+		}
+	}
+	if acme.Sessions[2].Invoice != "" || hooli.Sessions[0].Locked || hooli.Sessions[0].Invoice != "" {
+		t.Errorf("marked sessions not billed: %+v %+v", acme.Sessions[2], hooli.Sessions[0])
+	}
+	if lines = invoiceLines(NoReceiver); len(lines) != 0 {
+		t.Errorf("billed %+v again", lines)
+	}
+
+	// the running session gets billed once it ends
+	acme.Sessions[2].End = acme.Sessions[2].Start.Add(30 * time.Minute)
+	if lines = invoiceLines(NoReceiver); len(lines) != 1 || lines[0].Quantity != 0.5 {
+		t.Errorf("billed %+v, expected the ended session", lines)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	// selected is the tracker row driven by the keyboard, only highlighted
+	// once the keyboard has been used to navigate
+	selected   = 0
+	navigating = false
+	highlights = []*canvas.Rectangle{}
+
+	// dialogs are the ones currently open, the last one on top
+	dialogs = []dialog.Dialog{}
+)
+
+// showDialog keeps track of the dialog, so that Esc can close it.
+func showDialog(d dialog.Dialog) {
+	dialogs = append(dialogs, d)
+	d.SetOnClosed(func() {
+		dialogs = slices.DeleteFunc(dialogs, func(o dialog.Dialog) bool {
+			return o == d
+		})
+	})
+	d.Show()
+}
+
+func closeDialog() {
+	if len(dialogs) > 0 {
+		dialogs[len(dialogs)-1].Hide()
+	}
+}
+
+func showForm(title, confirm, dismiss string, items []*widget.FormItem, callback func(bool), w fyne.Window) {
+	showDialog(dialog.NewForm(title, confirm, dismiss, items, callback, w))
+}
+
+func showConfirm(title, message string, callback func(bool), w fyne.Window) {
+	showDialog(dialog.NewConfirm(title, message, callback, w))
+}
+
+func showCustomConfirm(title, confirm, dismiss string, content fyne.CanvasObject, callback func(bool), w fyne.Window) {
+	showDialog(dialog.NewCustomConfirm(title, confirm, dismiss, content, callback, w))
+}
+
+func showInformation(title, message string, w fyne.Window) {
+	showDialog(dialog.NewInformation(title, message, w))
+}
+
+func showError(err error, w fyne.Window) {
+	showDialog(dialog.NewError(err, w))
+}
+
+// entry closes the dialog it belongs to on Esc, which would otherwise be
+// swallowed by the text field.
+type entry struct {
+	widget.Entry
+}
+
+func newEntry() *entry {
+	e := &entry{}
+	e.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func newMultiLineEntry() *entry {
+	e := newEntry()
+	e.MultiLine = true
+	return e
+}
+
+func (e *entry) TypedKey(key *fyne.KeyEvent) {
+	if key.Name == fyne.KeyEscape {
+		closeDialog()
+		return
+	}
+	e.Entry.TypedKey(key)
+}
+
+// highlightRow wraps a tracker row with the indicator shown when it is
+// selected from the keyboard.
+func highlightRow(row fyne.CanvasObject) fyne.CanvasObject {
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameSelection))
+	bg.Hide()
+	highlights = append(highlights, bg)
+	return container.NewStack(bg, row)
+}
+
+func selectRow(idx int) {
+	visible := listedTrackers()
+	if len(visible) == 0 {
+		return
+	}
+	selected = min(max(idx, 0), len(visible)-1)
+	for i, h := range highlights {
+		if navigating && i == selected {
+			h.Show()
+		} else {
+			h.Hide()
+		}
+	}
+}
+
+// typedKey handles the keys typed while no widget has the focus: arrows
+// move through trackers, Space toggles, F2 renames, Enter opens sessions.
+func typedKey(w fyne.Window, key *fyne.KeyEvent) {
+	if key.Name == fyne.KeyEscape {
+		closeDialog()
+		return
+	}
+	visible := listedTrackers()
+	if len(dialogs) > 0 || len(visible) == 0 {
+		return
+	}
+
+	navigating = true
+	switch key.Name {
+	case fyne.KeyUp:
+		selectRow(selected - 1)
+	case fyne.KeyDown:
+		selectRow(selected + 1)
+	case fyne.KeyHome:
+		selectRow(0)
+	case fyne.KeyEnd:
+		selectRow(len(visible) - 1)
+	case fyne.KeySpace:
+		toggleTracker(w, visible[selected])
+	case fyne.KeyF2:
+		editTrackerDialog(w, visible[selected])
+	case fyne.KeyReturn, fyne.KeyEnter:
+		sessionsDialog(w, visible[selected])
+	default:
+		return
+	}
+	_ = hintStr.Set(visible[selected].Label + ": Space to start or pause, F2 to edit, Enter for sessions")
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+)
+
+// rtlLanguages are written from right to left.
+var rtlLanguages = []string{"ar", "ckb", "dv", "fa", "he", "ps", "sd", "ug", "ur", "yi"}
+
+// rightToLeft tells whether the user locale reads from right to left, the
+// layout being mirrored then.
+func rightToLeft() bool {
+	language, _, _ := strings.Cut(lang.SystemLocale().LanguageString(), "-")
+	return slices.Contains(rtlLanguages, strings.ToLower(language))
+}
+
+// leadingAlignment is where text starts in the reading direction.
+func leadingAlignment() fyne.TextAlign {
+	if rightToLeft() {
+		return fyne.TextAlignTrailing
+	}
+	return fyne.TextAlignLeading
+}
+
+// newRow lays out start and end on each side of the reading direction,
+// around center.
+func newRow(bottom, start, end, center fyne.CanvasObject) *fyne.Container {
+	if rightToLeft() {
+		start, end = end, start
+	}
+	return container.NewBorder(nil, bottom, start, end, center)
+}
+
+// newLine lays out objects horizontally in the reading direction.
+func newLine(objects ...fyne.CanvasObject) *fyne.Container {
+	if rightToLeft() {
+		objects = slices.Clone(objects)
+		slices.Reverse(objects)
+	}
+	return container.NewHBox(objects...)
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func weekOf(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+func (t *Tracker) HasLockedSessions() bool {
+	for _, s := range t.Sessions {
+		if s.Locked {
+			return true
+		}
+	}
+	return false
+}
+
+// lockSessions approves all closed sessions matching the filter and
+// returns how many got locked.
+func (t *Tracker) lockSessions(filter func(s *Session) bool) int {
+	count := 0
+	for idx := range t.Sessions {
+		s := &t.Sessions[idx]
+		if s.Locked || s.Open() || !filter(s) {
+			continue
+		}
+		s.Locked = true
+		count++
+	}
+	return count
+}
+
+func (t *Tracker) LockWeek(week string) {
+	count := t.lockSessions(func(s *Session) bool {
+		return weekOf(s.Start) == week
+	})
+	if count > 0 {
+		audit("lock", t, "week=%s sessions=%d", week, count)
+	}
+}
+
+func (t *Tracker) UnlockWeek(week string) {
+	count := 0
+	for idx := range t.Sessions {
+		s := &t.Sessions[idx]
+		if s.Locked && weekOf(s.Start) == week {
+			s.Locked = false
+			count++
+		}
+	}
+	if count > 0 {
+		audit("unlock", t, "week=%s sessions=%d", week, count)
+	}
+}
+
+// LockInvoiced locks the sessions not billed yet, already approved ones
+// included, and records them as billed by the given invoice.
+func (t *Tracker) LockInvoiced(invoice string) {
+	count := 0
+	for _, sessions := range t.unbilled() {
+		for _, s := range sessions {
+			s.Locked, s.Invoice = true, invoice
+			count++
+		}
+	}
+	if count > 0 {
+		audit("lock", t, "invoice=%s sessions=%d", invoice, count)
+	}
+}
+
+// InvoiceExpenses marks the expenses not billed yet as billed by the given
+// invoice.
+func (t *Tracker) InvoiceExpenses(invoice string) {
+	count := 0
+	for idx := range t.Expenses {
+		e := &t.Expenses[idx]
+		if e.Invoice == "" {
+			e.Invoice = invoice
+			count++
+		}
+	}
+	if count > 0 {
+		audit("lock", t, "invoice=%s expenses=%d", invoice, count)
+	}
+}
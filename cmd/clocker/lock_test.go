@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readAudit(t *testing.T) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), AuditFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestLockWeek(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	monday := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	nextMonday := monday.AddDate(0, 0, 7)
+	acme.Since = monday
+	acme.Sessions = []Session{
+		{ID: "a", Start: monday, End: monday.Add(2 * time.Hour)},
+		{ID: "b", Start: nextMonday, End: nextMonday.Add(time.Hour)},
+	}
+	acme.Elapsed = 3 * time.Hour
+
+	acme.LockWeek(weekOf(monday))
+	if !acme.Sessions[0].Locked || acme.Sessions[1].Locked {
+		t.Fatalf("locked sessions %+v, expected only the week of %s", acme.Sessions, weekOf(monday))
+	}
+
+	edits := map[string]func() error{
+		"update": func() error { return acme.UpdateSession("a", monday, monday.Add(time.Hour)) },
+		"note":   func() error { return acme.SetSessionNote("a", "review") },
+		"delete": func() error { return acme.DeleteSession("a") },
+		"cut":    func() error { return acme.CutSession("a", monday, monday.Add(time.Hour)) },
+	}
+	for name, edit := range edits {
+		if err := edit(); err == nil || !strings.Contains(err.Error(), "locked") {
+			t.Errorf("%s of a locked session: %v, expected it to be rejected", name, err)
+		}
+	}
+	if s := acme.Sessions[0]; len(acme.Sessions) != 2 || !s.End.Equal(monday.Add(2*time.Hour)) || s.Note != "" {
+		t.Errorf("locked session changed to %+v", acme.Sessions)
+	}
+	if acme.Elapsed != 3*time.Hour {
+		t.Errorf("elapsed %s, expected 3h", acme.Elapsed)
+	}
+	if err := acme.UpdateSession("b", nextMonday, nextMonday.Add(2*time.Hour)); err != nil {
+		t.Errorf("edit of an unlocked session: %v", err)
+	}
+
+	acme.UnlockWeek(weekOf(monday))
+	if acme.Sessions[0].Locked {
+		t.Fatal("session still locked")
+	}
+	entries := readAudit(t)
+	last := strings.Split(entries[len(entries)-1], "\t")
+	if len(last) != 5 || last[1] != "unlock" || last[2] != acme.ID || last[4] != "week=2026-W10 sessions=1" {
+		t.Errorf("last audit entry %q, expected the unlock of week 2026-W10", entries[len(entries)-1])
+	}
+	if err := acme.UpdateSession("a", monday, monday.Add(time.Hour)); err != nil {
+		t.Errorf("edit of an unlocked session: %v", err)
+	}
+}
+
+func TestLockInvoiced(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	monday := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	acme.Since = monday
+	acme.Sessions = []Session{
+		{ID: "a", Start: monday, End: monday.Add(2 * time.Hour), Locked: true},
+		{ID: "b", Start: monday.Add(24 * time.Hour), End: monday.Add(25 * time.Hour)},
+		{ID: "c", Start: monday.Add(48 * time.Hour), End: monday.Add(49 * time.Hour), Locked: true, Invoice: "INV-0001"},
+	}
+	acme.Elapsed = 4 * time.Hour
+	acme.Rate = 100
+
+	acme.LockInvoiced("INV-0002")
+	for _, s := range acme.Sessions {
+		expected := "INV-0002"
+		if s.ID == "c" {
+			expected = "INV-0001"
+		}
+		if !s.Locked || s.Invoice != expected {
+			t.Errorf("session %s locked=%v invoice=%q, expected locked by %s", s.ID, s.Locked, s.Invoice, expected)
+		}
+	}
+	if err := acme.DeleteSession("b"); err == nil {
+		t.Error("deleted an invoiced session")
+	}
+	entries := readAudit(t)
+	if last := entries[len(entries)-1]; !strings.Contains(last, "\tlock\t") || !strings.HasSuffix(last, "invoice=INV-0002 sessions=2") {
+		t.Errorf("last audit entry %q, expected the lock by INV-0002", last)
+	}
+}
@@ -7,12 +7,16 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
+	"io/fs"
 	"log"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -21,9 +25,15 @@ import (
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
-	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/clock"
+	"github.com/gxben/clocker/pkg/engine"
+	"github.com/gxben/clocker/pkg/export"
+	"github.com/gxben/clocker/pkg/hooks"
+	"github.com/gxben/clocker/pkg/invoice"
+	"github.com/gxben/clocker/pkg/uuid"
 )
 
 const (
@@ -33,63 +43,175 @@ const (
 
 var trackers = []*Tracker{}
 
+var settings = Settings{}
+
+// stateLock keeps the background loops changing trackers or settings, e.g.
+// rollovers and syncs, from doing so while saving or along each other.
+var stateLock sync.Mutex
+
+type Settings struct {
+	Currency      string                `yaml:"currency,omitempty"`
+	ExchangeRates map[string]float64    `yaml:"exchange_rates,omitempty"`
+	Invoice       invoice.Template      `yaml:"invoice,omitempty"`
+	BudgetAlerts  []int                 `yaml:"budget_alerts"`
+	ClosedPeriods []Period              `yaml:"closed_periods,omitempty"`
+	SAP           export.SAPMapping     `yaml:"sap,omitempty"`
+	Tempo         export.TempoOptions   `yaml:"tempo,omitempty"`
+	MenuBar       bool                  `yaml:"menu_bar,omitempty"`
+	IdleMinutes   int                   `yaml:"idle_minutes,omitempty"`
+	StartHidden   bool                  `yaml:"start_minimized,omitempty"`
+	UIScale       int                   `yaml:"ui_scale,omitempty"`
+	TextScale     int                   `yaml:"text_scale,omitempty"`
+	HighContrast  bool                  `yaml:"high_contrast,omitempty"`
+	PowerPrompt   bool                  `yaml:"power_prompt,omitempty"`
+	Storage       string                `yaml:"storage,omitempty"`
+	Notifications NotifySettings        `yaml:"notifications,omitempty"`
+	Sync          SyncSettings          `yaml:"sync,omitempty"`
+	Hooks         []hooks.Hook          `yaml:"hooks,omitempty"`
+	WeekStart     string                `yaml:"week_start,omitempty"`
+	Rollover      bool                  `yaml:"weekly_rollover,omitempty"`
+	TimerPresets  []int                 `yaml:"timer_presets"`
+	Standup       export.StandupOptions `yaml:"standup,omitempty"`
+	EventLog      string                `yaml:"event_log,omitempty"`
+	ControlFile   string                `yaml:"control_file,omitempty"`
+}
+
+type Config struct {
+	Version  int `yaml:"version,omitempty"`
+	Settings `yaml:",inline"`
+	Trackers []*Tracker `yaml:"trackers"`
+	// Deleted trackers, for syncs to tell them from new ones
+	Deleted []Tombstone `yaml:"deleted_trackers,omitempty"`
+
+	// legacy files only held the list of trackers
+	legacy bool
+	// keys set in the file, telling missing settings from emptied ones
+	keys []string
+}
+
 type Tracker struct {
-	Label   string        `yaml:"label"`
-	Elapsed time.Duration `yaml:"elapsed"`
-	Active  bool          `yaml:"-"`
-	Timer   chan struct{} `yaml:"-"`
+	ID       string        `yaml:"id"`
+	Label    string        `yaml:"label"`
+	Elapsed  time.Duration `yaml:"elapsed"`
+	Since    time.Time     `yaml:"since,omitempty"`
+	Sessions []Session     `yaml:"sessions,omitempty"`
+	Rate     float64       `yaml:"rate,omitempty"`
+	Rates    []RateChange  `yaml:"rates,omitempty"`
+	Currency string        `yaml:"currency,omitempty"`
+	Receiver string        `yaml:"receiver,omitempty"`
+	Issue    string        `yaml:"issue,omitempty"`
+	Expenses []Expense     `yaml:"expenses,omitempty"`
+	Budget   float64       `yaml:"budget,omitempty"`
+	Alerted  int           `yaml:"budget_alerted,omitempty"`
+	History  []WeekTotal   `yaml:"history,omitempty"`
+	Alarm    *Alarm        `yaml:"alarm,omitempty"`
+	Goal     time.Duration `yaml:"weekly_goal,omitempty"`
+	Reached  time.Time     `yaml:"goal_reached,omitempty"`
+	Modified time.Time     `yaml:"modified,omitempty"`
+	Deleted  []Tombstone   `yaml:"deleted_sessions,omitempty"`
+
+	engine.Lifecycle `yaml:",inline"`
 
 	// UI References
-	PlayButton *widget.Button `yaml:"-"`
+	PlayButton *iconButton `yaml:"-"`
+
+	// deadline pauses the tracker when set, its countdown being over
+	deadline time.Time
 
 	// Data Bindings
 	LabelStr   binding.String `yaml:"-"`
 	ElapsedStr binding.String `yaml:"-"`
+	BudgetBurn binding.Float  `yaml:"-"`
 }
 
 func (t *Tracker) Start() {
-
-	go func() {
-		for {
-			select {
-			default:
-			case <-t.Timer: // stop call
-				return
-			}
-			t.Elapsed += ClockFrequency
-			_ = t.ElapsedStr.Set(shortDur(t.Elapsed))
-			time.Sleep(ClockFrequency)
-		}
-	}()
-
-	t.Active = true
+	err := t.Fire(engine.Start)
+	if err != nil {
+		log.Println(t.Label, err)
+		return
+	}
+	t.Sessions = append(t.Sessions, newSession(time.Now()))
+	recordSession(t, t.Sessions[len(t.Sessions)-1])
 	t.PlayButton.SetIcon(theme.MediaPauseIcon())
+	t.PlayButton.SetName("Pause " + t.Label)
+	wakeClock()
+	fireHooks(t, engine.Start)
 }
 
 func (t *Tracker) Stop() {
-	t.Timer <- struct{}{}
-	t.Active = false
+	t.stop(time.Now())
+}
+
+// stop closes the running session at end.
+func (t *Tracker) stop(end time.Time) {
+	err := t.Fire(engine.Pause)
+	if err != nil {
+		log.Println(t.Label, err)
+		return
+	}
+	t.deadline = time.Time{}
+	t.closeSession(end)
+	if len(t.Sessions) > 0 {
+		recordSession(t, t.Sessions[len(t.Sessions)-1])
+	}
+	t.refreshElapsed()
 	t.PlayButton.SetIcon(theme.MediaPlayIcon())
+	t.PlayButton.SetName("Start " + t.Label)
+	wakeClock()
+	fireHooks(t, engine.Pause)
 }
 
-func NewTracker(label string, duration time.Duration) {
+func NewTracker(label string, duration time.Duration) *Tracker {
 	t := &Tracker{
-		Label:      label,
-		Elapsed:    duration,
-		Active:     false,
-		Timer:      make(chan struct{}, 1),
-		LabelStr:   binding.NewString(),
-		ElapsedStr: binding.NewString(),
+		Label:   label,
+		Elapsed: duration,
+	}
+	addTracker(t)
+	return t
+}
+
+// assignIDs gives one to the tracker and sessions saved before having one,
+// returning whether the tracker got one and how many sessions did.
+func (t *Tracker) assignIDs() (bool, int) {
+	tracker := t.ID == ""
+	if tracker {
+		t.ID = uuid.New()
+	}
+	sessions := 0
+	for idx := range t.Sessions {
+		if t.Sessions[idx].ID == "" {
+			t.Sessions[idx].ID = uuid.New()
+			sessions++
+		}
 	}
+	return tracker, sessions
+}
+
+func addTracker(t *Tracker) {
+	t.assignIDs()
+	t.Recover()
+	t.LabelStr = binding.NewString()
+	t.ElapsedStr = binding.NewString()
+	t.BudgetBurn = binding.NewFloat()
 
 	_ = t.LabelStr.Set(t.Label)
-	_ = t.ElapsedStr.Set(shortDur(t.Elapsed))
+	t.refreshElapsed()
 	trackers = append(trackers, t)
 }
 
-func DeleteTracker(t *Tracker) {
-	for idx, id := range trackers {
-		if id == t {
+// findTracker returns the tracker with the given ID, or nil.
+func findTracker(id string) *Tracker {
+	for _, t := range trackers {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func DeleteTracker(id string) {
+	for idx, t := range trackers {
+		if t.ID == id {
 			trackers = append(trackers[:idx], trackers[idx+1:]...)
 			break
 		}
@@ -98,10 +220,18 @@ func DeleteTracker(t *Tracker) {
 
 func ResetTrackers() {
 	for _, t := range trackers {
-		t.Stop()
+		if t.Running() {
+			t.Stop()
+		}
+		if t.Fire(engine.Reset) != nil {
+			continue
+		}
 		t.Elapsed = 0
-		_ = t.ElapsedStr.Set("0s")
+		t.Since = time.Now()
+		t.refreshElapsed()
+		fireHooks(t, engine.Reset)
 	}
+	refreshTotals()
 }
 
 func shortDur(d time.Duration) string {
@@ -116,13 +246,13 @@ func shortDur(d time.Duration) string {
 }
 
 func addTrackerDialog(w fyne.Window) {
-	tracker := widget.NewEntry()
+	tracker := newEntry()
 	items := []*widget.FormItem{
 		widget.NewFormItem("", tracker),
 		widget.NewFormItem("", widget.NewLabel("")),
 	}
 
-	dialog.ShowForm("New Tracker", "Add", "Cancel", items, func(b bool) {
+	showForm("New Tracker", "Add", "Cancel", items, func(b bool) {
 		if !b {
 			return
 		}
@@ -130,39 +260,143 @@ func addTrackerDialog(w fyne.Window) {
 		NewTracker(tracker.Text, 0)
 		update(w)
 	}, w)
+	w.Canvas().Focus(tracker)
 }
 
 func editTrackerDialog(w fyne.Window, t *Tracker) {
-	tracker := widget.NewEntry()
+	tracker := newEntry()
 	tracker.SetText(t.Label)
+	rate := newEntry()
+	rate.SetText(formatRate(t.CurrentRate()))
+	rate.SetPlaceHolder("0.00")
+	effective := newEntry()
+	effective.SetText(time.Now().Format(invoice.DateFormat))
+	currency := newEntry()
+	currency.SetText(t.Currency)
+	currency.SetPlaceHolder(settings.Currency)
+	budget := newEntry()
+	budget.SetText(formatRate(t.Budget))
+	budget.SetPlaceHolder("No budget")
+	goal := newEntry()
+	goal.SetText(formatGoal(t.Goal))
+	goal.SetPlaceHolder("No goal")
+	receiver := newEntry()
+	receiver.SetText(t.Receiver)
+	receiver.SetPlaceHolder("WBS element, order or cost center")
+	issue := newEntry()
+	issue.SetText(t.Issue)
+	issue.SetPlaceHolder("PROJ-123")
+	alarm := newEntry()
+	alarm.SetPlaceHolder("16:45")
+	reminder := newEntry()
+	reminder.SetPlaceHolder("Stop and write the summary")
+	pause := widget.NewCheck("Pause the tracker", nil)
+	if t.Alarm != nil {
+		alarm.SetText(formatAlarmTime(t.Alarm.At, time.Now()))
+		reminder.SetText(t.Alarm.Message)
+		pause.SetChecked(t.Alarm.Pause)
+	}
 	items := []*widget.FormItem{
 		widget.NewFormItem("", tracker),
+		widget.NewFormItem("Hourly rate", rate),
+		{Text: "Effective from", Widget: effective, HintText: "Date the new rate applies from"},
+		widget.NewFormItem("Currency", currency),
+		widget.NewFormItem("Budget", budget),
+		{Text: "Weekly goal", Widget: goal, HintText: "Hours to reach every week"},
+		{Text: "Receiver", Widget: receiver, HintText: "SAP receiver object"},
+		{Text: "Issue", Widget: issue, HintText: "Linked Jira issue key"},
+		{Text: "Alarm", Widget: alarm, HintText: "Time to be reminded at, empty for none"},
+		widget.NewFormItem("Reminder", reminder),
+		widget.NewFormItem("", pause),
 		widget.NewFormItem("", widget.NewLabel("")),
 	}
 
-	dialog.ShowForm("Edit Tracker", "Update", "Cancel", items, func(b bool) {
+	showForm("Edit Tracker", "Update", "Cancel", items, func(b bool) {
 		if !b {
 			return
 		}
+		r, err := parseRate(rate.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		from, err := parseDate(effective.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		limit, err := parseRate(budget.Text)
+		if err != nil {
+			showError(fmt.Errorf("invalid budget %q", budget.Text), w)
+			return
+		}
+		target, err := parseGoal(goal.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		ring, err := parseAlarmTime(alarm.Text, time.Now())
+		if err != nil {
+			showError(err, w)
+			return
+		}
 		t.Label = tracker.Text
+		t.SetRate(r, from)
+		t.Currency = strings.ToUpper(strings.TrimSpace(currency.Text))
+		t.Budget = limit
+		t.Goal = target
+		t.Receiver = strings.TrimSpace(receiver.Text)
+		t.Issue = strings.ToUpper(strings.TrimSpace(issue.Text))
+		t.Alarm = nil
+		if !ring.IsZero() {
+			t.Alarm = &Alarm{At: ring, Message: strings.TrimSpace(reminder.Text), Pause: pause.Checked}
+		}
+		wakeAlarms()
 		_ = t.LabelStr.Set(tracker.Text)
+		t.refreshElapsed()
+		update(w)
 		log.Println("Updating new clock", tracker.Text)
 	}, w)
+	w.Canvas().Focus(tracker)
+}
+
+func toggleTracker(w fyne.Window, t *Tracker) {
+	if t.Running() {
+		t.Stop()
+		refreshTray(w)
+		refreshTaskbar()
+		return
+	}
+	guardClosedPeriods(w, t, "start tracking", []timeRange{{From: time.Now()}}, func() {
+		t.Start()
+		refreshTray(w)
+		attachTaskbar(w)
+		refreshTaskbar()
+	})
 }
 
 func deleteTrackerDialog(w fyne.Window, t *Tracker) {
+	if t.HasLockedSessions() {
+		text := fmt.Sprintf("Tracker %s has approved sessions, unlock them before deleting it.\nArchive it instead ?", t.Label)
+		showConfirm("Delete Tracker", text, func(b bool) {
+			if b {
+				archiveTracker(w, t)
+			}
+		}, w)
+		return
+	}
 	text := fmt.Sprintf("Are you sure you want to delete tracker %s ?", t.Label)
-	dialog.ShowConfirm("Delete Tracker ?", text, func(b bool) {
+	showConfirm("Delete Tracker ?", text, func(b bool) {
 		if !b {
 			return
 		}
-		DeleteTracker(t)
+		DeleteTracker(t.ID)
 		update(w)
 	}, w)
 }
 
 func resetTrackersDialog(w fyne.Window) {
-	dialog.ShowConfirm("Reset timers ?", "Are you sure you want to reset all counters ?", func(b bool) {
+	showConfirm("Reset timers ?", "Are you sure you want to reset all counters ?", func(b bool) {
 		if !b {
 			return
 		}
@@ -172,100 +406,291 @@ func resetTrackersDialog(w fyne.Window) {
 }
 
 func makeMenu(w fyne.Window) fyne.CanvasObject {
-	return container.NewGridWithColumns(2,
-		widget.NewButtonWithIcon("", theme.ListIcon(), func() {
+	buttons := []fyne.CanvasObject{
+		newIconButton("Add tracker", theme.ListIcon(), func() {
 			addTrackerDialog(w)
 		}),
-		widget.NewButtonWithIcon("", theme.HistoryIcon(), func() {
+		newIconButton("Reset counters", theme.HistoryIcon(), func() {
 			resetTrackersDialog(w)
 		}),
-	)
+		newIconButton("Import sessions", theme.UploadIcon(), func() {
+			importDialog(w)
+		}),
+		newIconButton("Export timesheet", theme.DownloadIcon(), func() {
+			exportDialog(w)
+		}),
+		newIconButton("Export invoice", theme.DocumentPrintIcon(), func() {
+			invoiceDialog(w)
+		}),
+		newIconButton("Standup summary", theme.ContentCopyIcon(), func() {
+			standupDialog(w)
+		}),
+		newIconButton("Search notes", theme.SearchIcon(), func() {
+			searchDialog(w)
+		}),
+		newIconButton("Statistics", theme.InfoIcon(), func() {
+			statsDialog(w)
+		}),
+		newIconButton("Compare weeks", theme.GridIcon(), func() {
+			compareDialog(w)
+		}),
+		newIconButton("Overlapping sessions", theme.WarningIcon(), func() {
+			overlapsDialog(w)
+		}),
+		newIconButton("Settings", theme.SettingsIcon(), func() {
+			settingsDialog(w)
+		}),
+	}
+	if syncScheduler != nil {
+		syncButton := newIconButton("Sync now", theme.ViewRefreshIcon(), syncNow)
+		buttons = slices.Insert(buttons, len(buttons)-1, fyne.CanvasObject(syncButton))
+	}
+	if rightToLeft() {
+		slices.Reverse(buttons)
+	}
+	return container.NewGridWithColumns(len(buttons), buttons...)
 }
 
-func makeTrackerList(w fyne.Window) fyne.CanvasObject {
+func makeTrackerList(w fyne.Window) *fyne.Container {
 	trackerList := []fyne.CanvasObject{}
-	for _, t := range trackers {
-		playButton := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {})
-		playButton.OnTapped = func() {
-			if t.Active {
-				t.Stop()
-			} else {
-				t.Start()
-			}
+	highlights = highlights[:0]
+	listed := listedTrackers()
+	if filtering() && len(listed) > 0 {
+		trackerList = append(trackerList, widget.NewLabelWithStyle(fmt.Sprintf("Trackers (%d)", len(listed)),
+			leadingAlignment(), fyne.TextStyle{Bold: true}))
+	}
+	for _, t := range listed {
+		icon, name := theme.MediaPlayIcon(), "Start "+t.Label
+		if t.Running() {
+			icon, name = theme.MediaPauseIcon(), "Pause "+t.Label
 		}
+		playButton := newIconButton(name, icon, func() {
+			toggleTracker(w, t)
+		})
 		t.PlayButton = playButton
 
 		label := widget.NewLabel("")
 		label.Bind(t.LabelStr)
+		label.Alignment = leadingAlignment()
 
 		elapsed := widget.NewLabel("")
 		elapsed.Bind(t.ElapsedStr)
 
-		editButton := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+		editButton := newIconButton("Edit "+t.Label, theme.DocumentCreateIcon(), func() {
 			editTrackerDialog(w, t)
 		})
 
-		trashButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		sessionsButton := newIconButton("Sessions of "+t.Label, theme.DocumentIcon(), func() {
+			sessionsDialog(w, t)
+		})
+
+		expensesButton := newIconButton("Expenses of "+t.Label, theme.MailAttachmentIcon(), func() {
+			expensesDialog(w, t)
+		})
+
+		trashButton := newIconButton("Delete "+t.Label, theme.DeleteIcon(), func() {
 			deleteTrackerDialog(w, t)
 		})
 
-		settingsBox := container.NewHBox(elapsed, editButton, trashButton)
+		settingsBox := newLine(elapsed, editButton, sessionsButton, expensesButton, trashButton)
 
-		c := container.NewBorder(nil, nil, playButton, settingsBox, label)
-		trackerList = append(trackerList, c)
+		c := newRow(makeBudgetBar(t), newLine(playButton, makeTimerButton(w, t)), settingsBox, label)
+		trackerList = append(trackerList, highlightRow(c))
 	}
 
+	if archived := makeArchivedList(w); archived != nil {
+		trackerList = append(trackerList, archived)
+	}
+	if filtering() && len(trackerList) == 0 {
+		trackerList = append(trackerList, widget.NewLabel(fmt.Sprintf("No tracker matching %q.", strings.TrimSpace(filterQuery))))
+	}
 	return container.NewVBox(trackerList...)
 }
 
 func update(w fyne.Window) {
 	menu := makeMenu(w)
-	trackers := makeTrackerList(w)
-	panel := container.NewBorder(nil, menu, nil, nil, trackers)
+	trackerList = makeTrackerList(w)
+	totals := makeTotals()
+	footer := container.NewVBox(makeQuickEntry(w), totals, menu)
+	if status := makeSyncStatus(); status != nil {
+		footer.Add(status)
+	}
+	footer.Add(makeHint())
+	panel := container.NewBorder(makeFilterBox(w), footer, nil, nil, trackerList)
 	w.SetContent(panel)
+	selectRow(selected)
+	refreshTotals()
+	refreshTray(w)
 	saveConfig()
 }
 
-func readConfig() {
-	var trackers []Tracker
-
-	home, _ := os.UserHomeDir()
-	confFile := filepath.Clean(fmt.Sprintf("%s/%s", home, ConfigFile))
-
-	cfg, err := os.Open(confFile)
+func decodeConfig(contents []byte) (Config, error) {
+	var config Config
+	err := yaml.Unmarshal(contents, &config)
 	if err != nil {
-		return
+		// legacy configuration files only hold the list of trackers
+		err = yaml.Unmarshal(contents, &config.Trackers)
+		config.legacy = err == nil
+	}
+	if err == nil {
+		config.keys = settingKeys(contents)
+	}
+	return config, err
+}
+
+func currentConfig() Config {
+	return Config{
+		Version:  SchemaVersion,
+		Settings: savedSettings(),
+		Trackers: trackers,
+		Deleted:  deletedTrackers,
 	}
-	defer cfg.Close()
+}
+
+func encodeConfig() ([]byte, error) {
+	return yaml.Marshal(currentConfig())
+}
 
-	contents, _ := io.ReadAll(cfg)
-	err = yaml.Unmarshal(contents, &trackers)
+func readConfig() {
+	ctx, cancel := context.WithTimeout(appCtx, StoreTimeout)
+	defer cancel()
+
+	s, config, saved, err := openStorage(ctx)
+	store = s
+	if errors.Is(err, fs.ErrNotExist) {
+		// first start, still with defaults and overrides
+		config, err = Config{}, nil
+	}
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
+	if config.Version > SchemaVersion {
+		log.Printf("Data saved by a newer clocker, version %d, some of it may be lost on save", config.Version)
+	}
+	alive := saved
+	if beat := lastHeartbeat(); beat.After(alive) {
+		alive = beat
+	}
 
-	for _, t := range trackers {
-		NewTracker(t.Label, t.Elapsed)
+	settings, err = loadSettings(config.Settings, config.keys)
+	if err != nil {
+		log.Println(err)
+	}
+	trackers = []*Tracker{}
+	for _, t := range config.Trackers {
+		// sessions left open by an unexpected exit end with the last time
+		// clocker was seen alive
+		t.closeInterrupted(alive)
+		addTracker(t)
 	}
+	deletedTrackers = config.Deleted
+	rememberTrackers()
 }
 
 func saveConfig() {
-	home, _ := os.UserHomeDir()
-	confFile := fmt.Sprintf("%s/%s", home, ConfigFile)
-
-	content, _ := yaml.Marshal(trackers)
-	_ = os.WriteFile(confFile, content, 0600)
+	// saving on shutdown must still go through once appCtx is cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	dataGeneration++
+	err := switchStorage()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	stampChanges(time.Now())
+	err = store.Save(ctx, currentConfig())
+	if err == nil {
+		err = savePointer(ctx)
+	}
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	triggerSync()
 }
 
 func main() {
+	if runCommand(os.Args[1:]) {
+		return
+	}
+
+	minimized := flag.Bool(MinimizedFlag, false, "start hidden in the system tray")
+	debug := flag.Bool(DebugFlag, false, "serve runtime profiles on "+PprofAddr)
+	flag.Var(&settingOverrides, SetFlag, "override a `key=value` setting, e.g. idle_minutes=5")
+	flag.Parse()
+
+	if *debug {
+		startProfiling(PprofAddr)
+	}
+
 	a := app.New()
+	a.SetIcon(resource("icons/clocker.svg"))
+	loadTranslations()
 	w := a.NewWindow("Clocker")
+	start := time.Now()
 	readConfig()
+	if *debug {
+		log.Println("Loaded configuration in", time.Since(start))
+	}
+	applyTheme()
+	startSync(w)
+	startControl(w)
 	update(w)
+	mergeDuplicatesDialog(w)
+	go watchConfig(appCtx, w)
+	startIdleDetection(w)
+	startPowerMonitoring(w)
+	go runClock(appCtx, w, clock.Real)
+	go runRollover(appCtx, clock.Real)
+	go runAlarms(appCtx, w, clock.Real)
 	w.Resize(fyne.NewSize(400, 800))
-	w.SetOnClosed(func() {
-		saveConfig()
+	w.Canvas().SetOnTypedKey(func(key *fyne.KeyEvent) {
+		typedKey(w, key)
 	})
+	w.SetCloseIntercept(func() {
+		// in menu bar mode, trackers keep running with the window closed
+		if menuBarMode() {
+			hideWindow(w)
+			return
+		}
+		w.Close()
+	})
+	w.SetOnClosed(shutdown)
+	a.Lifecycle().SetOnStopped(shutdown)
+
+	// without a tray, there would be no way to bring the window back
+	if menuBarMode() || (*minimized && trayEnabled()) {
+		windowShown = false
+		a.Run()
+		return
+	}
 	w.ShowAndRun()
 }
+
+// shutdownOnce saves once, whether the window closes, the app stops or the
+// system shuts down first.
+var shutdownOnce sync.Once
+
+func shutdown() {
+	shutdownOnce.Do(func() {
+		cancelApp()
+		if idleMonitor != nil {
+			_ = idleMonitor.Close()
+		}
+		for _, t := range trackers {
+			if t.Running() {
+				t.Stop()
+			}
+		}
+		saveConfig()
+		clearHeartbeat()
+		// closed last, as it holds the system shutdown until saved
+		if powerMonitor != nil {
+			_ = powerMonitor.Close()
+		}
+	})
+}
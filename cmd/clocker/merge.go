@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// labelKey compares labels the same way imports match trackers.
+func labelKey(label string) string {
+	return strings.ToLower(strings.TrimSpace(label))
+}
+
+// duplicateLabels groups the trackers sharing a label, in the order they
+// first appear.
+func duplicateLabels(list []*Tracker) [][]*Tracker {
+	groups := map[string][]*Tracker{}
+	keys := []string{}
+	for _, t := range list {
+		key := labelKey(t.Label)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], t)
+	}
+	duplicates := [][]*Tracker{}
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			duplicates = append(duplicates, groups[key])
+		}
+	}
+	return duplicates
+}
+
+// merge moves the time, sessions and expenses of other to t, which keeps
+// its own settings unless unset. A session of other still running gets
+// closed.
+func (t *Tracker) merge(other *Tracker) {
+	other.closeSession(time.Now())
+	t.Elapsed += other.Elapsed
+	if other.Since.Before(t.Since) {
+		t.Since = other.Since
+	}
+	t.Sessions = append(t.Sessions, other.Sessions...)
+	t.sortSessions()
+	t.Expenses = append(t.Expenses, other.Expenses...)
+	t.History = append(t.History, other.History...)
+	sort.SliceStable(t.History, func(i, j int) bool {
+		return t.History[i].Week.Before(t.History[j].Week)
+	})
+
+	if t.Rate == 0 && len(t.Rates) == 0 {
+		t.Rate, t.Rates = other.Rate, other.Rates
+	}
+	if t.Currency == "" {
+		t.Currency = other.Currency
+	}
+	if t.Receiver == "" {
+		t.Receiver = other.Receiver
+	}
+	if t.Issue == "" {
+		t.Issue = other.Issue
+	}
+	if t.Budget == 0 {
+		t.Budget = other.Budget
+	}
+	if t.Goal == 0 {
+		t.Goal = other.Goal
+	}
+	if t.Alarm == nil {
+		t.Alarm = other.Alarm
+	}
+}
+
+// mergeDuplicates merges the trackers sharing a label into the first of
+// them, returning the remaining trackers and what got merged.
+func mergeDuplicates(list []*Tracker) ([]*Tracker, []string) {
+	merged := map[*Tracker]bool{}
+	report := []string{}
+	for _, group := range duplicateLabels(list) {
+		into := group[0]
+		for _, t := range group[1:] {
+			into.merge(t)
+			merged[t] = true
+		}
+		report = append(report, fmt.Sprintf("merged %d trackers into %s", len(group), into.Label))
+	}
+	remaining := []*Tracker{}
+	for _, t := range list {
+		if !merged[t] {
+			remaining = append(remaining, t)
+		}
+	}
+	return remaining, report
+}
+
+func checkLabels(c *Config) []string {
+	problems := []string{}
+	for _, group := range duplicateLabels(c.Trackers) {
+		problems = append(problems, fmt.Sprintf("%d trackers labelled %s", len(group), group[0].Label))
+	}
+	return problems
+}
+
+func fixLabels(c *Config) []string {
+	remaining, report := mergeDuplicates(c.Trackers)
+	c.Trackers = remaining
+	return report
+}
+
+// mergeDuplicatesDialog offers to merge trackers sharing a label, as left
+// by hand edits or previous imports.
+func mergeDuplicatesDialog(w fyne.Window) {
+	groups := duplicateLabels(trackers)
+	if len(groups) == 0 {
+		return
+	}
+	lines := []string{"Several trackers share the same label:"}
+	for _, group := range groups {
+		lines = append(lines, fmt.Sprintf("%s (%d)", group[0].Label, len(group)))
+	}
+	lines = append(lines, "Merge them, summing their time and sessions ?")
+	showConfirm("Merge duplicates ?", strings.Join(lines, "\n"), func(b bool) {
+		if !b {
+			return
+		}
+		for _, group := range groups {
+			audit("merge", group[0], "trackers=%d", len(group))
+		}
+		var report []string
+		trackers, report = mergeDuplicates(trackers)
+		for _, t := range trackers {
+			t.refreshElapsed()
+		}
+		refreshTotals()
+		log.Println(strings.Join(report, ", "))
+		update(w)
+		saveConfig()
+	}, w)
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func duplicateTrackers() []*Tracker {
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	return []*Tracker{
+		{ID: "tracker-1", Label: "Acme", Elapsed: time.Hour, Since: day, Sessions: []Session{
+			{ID: "a", Start: day, End: day.Add(time.Hour)},
+		}},
+		{ID: "tracker-2", Label: "Globex", Elapsed: time.Hour},
+		{ID: "tracker-3", Label: " acme", Elapsed: 2 * time.Hour, Rate: 80, Issue: "ACME-1", Since: day.AddDate(0, 0, -1), Sessions: []Session{
+			{ID: "b", Start: day.AddDate(0, 0, -1), End: day.AddDate(0, 0, -1).Add(2 * time.Hour)},
+		}},
+	}
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	merged, report := mergeDuplicates(duplicateTrackers())
+	if len(merged) != 2 || merged[0].Label != "Acme" || merged[1].Label != "Globex" {
+		t.Fatalf("kept %d trackers, expected Acme and Globex", len(merged))
+	}
+	if len(report) != 1 || report[0] != "merged 2 trackers into Acme" {
+		t.Errorf("report = %v", report)
+	}
+
+	acme := merged[0]
+	if acme.ID != "tracker-1" || acme.Elapsed != 3*time.Hour {
+		t.Errorf("merged into %s counting %s, expected tracker-1 with 3h", acme.ID, acme.Elapsed)
+	}
+	if len(acme.Sessions) != 2 || acme.Sessions[0].ID != "b" || acme.Sessions[1].ID != "a" {
+		t.Errorf("sessions %+v, expected b then a", acme.Sessions)
+	}
+	if !acme.Since.Equal(acme.Sessions[0].Start) {
+		t.Errorf("counter since %s, expected the earliest one", acme.Since)
+	}
+	if acme.Rate != 80 || acme.Issue != "ACME-1" {
+		t.Errorf("rate %v and issue %q not taken from the duplicate", acme.Rate, acme.Issue)
+	}
+}
+
+func TestMergeDuplicatesDialog(t *testing.T) {
+	w := newTestWindow(t)
+	for _, d := range duplicateTrackers() {
+		addTracker(d)
+	}
+
+	mergeDuplicatesDialog(w)
+	tapButton(t, w, "Yes")
+	if len(trackers) != 2 {
+		t.Fatalf("%d trackers left, expected 2", len(trackers))
+	}
+	if _, ok := iconButtons(w)["Start acme"]; ok {
+		t.Error("list still shows the merged tracker")
+	}
+	if s, _ := trackers[0].ElapsedStr.Get(); s != "3h" {
+		t.Errorf("counter shows %s, expected 3h", s)
+	}
+}
+
+func TestDoctorLabels(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store = nil
+	settings = Settings{}
+	trackers = duplicateTrackers()
+	saveConfig()
+
+	var out bytes.Buffer
+	err := doctorCommand(nil, &out)
+	if err == nil || !strings.Contains(out.String(), "[!!] labels: 2 trackers labelled Acme") {
+		t.Fatalf("duplicates not reported:\n%s", out.String())
+	}
+	out.Reset()
+	err = doctorCommand([]string{"--fix"}, &out)
+	if err != nil {
+		t.Fatalf("doctor --fix = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "[fixed] labels: merged 2 trackers into Acme") {
+		t.Errorf("merge not reported:\n%s", out.String())
+	}
+}
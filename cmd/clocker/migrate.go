@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// SchemaVersion is the version of the data files written. Unversioned
+	// files are version 0, whether legacy lists of trackers or settings
+	// and trackers saved before these were given IDs.
+	SchemaVersion = 1
+
+	BackupFormat = ".clocker.backup-20060102-150405.yaml"
+)
+
+// migration upgrades data from the previous version, reporting what it
+// changed.
+type migration struct {
+	version int
+	migrate func(c *Config) []string
+}
+
+var migrations = []migration{
+	{1, func(c *Config) []string {
+		changes := []string{}
+		if c.legacy {
+			changes = append(changes, "converted the legacy list of trackers")
+		}
+		trackerIDs, sessionIDs := 0, 0
+		for _, t := range c.Trackers {
+			tracker, sessions := t.assignIDs()
+			if tracker {
+				trackerIDs++
+			}
+			sessionIDs += sessions
+		}
+		if trackerIDs > 0 || sessionIDs > 0 {
+			changes = append(changes, fmt.Sprintf("assigned IDs to %d trackers and %d sessions", trackerIDs, sessionIDs))
+		}
+		return changes
+	}},
+}
+
+// migrateConfig upgrades the configuration to the current version.
+func migrateConfig(c *Config) ([]string, error) {
+	if c.Version > SchemaVersion {
+		return nil, fmt.Errorf("data saved by a newer clocker, version %d, this one only knows version %d", c.Version, SchemaVersion)
+	}
+	report := []string{}
+	for _, m := range migrations {
+		if m.version <= c.Version {
+			continue
+		}
+		report = append(report, fmt.Sprintf("schema version %d to %d", c.Version, m.version))
+		for _, change := range m.migrate(c) {
+			report = append(report, "  "+change)
+		}
+		c.Version = m.version
+	}
+	return report, nil
+}
+
+// backup writes a snapshot of the storage next to the configuration file,
+// which can be copied over it to revert the migration.
+func backup(ctx context.Context, s Storage) (string, error) {
+	file := homeFile(time.Now().Format(BackupFormat))
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	err = s.Snapshot(ctx, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(file)
+		return "", err
+	}
+	return file, nil
+}
+
+func migrateCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	to := flags.String("to", "", "storage backend to move to, one of "+strings.Join(storageNames(), ", "))
+	dryRun := flags.Bool("dry-run", false, "only report what would change")
+	noBackup := flags.Bool("no-backup", false, "don't back up the data first")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if _, ok := storages[*to]; *to != "" && !ok {
+		return fmt.Errorf("unsupported storage %q, expected one of %s", *to, strings.Join(storageNames(), ", "))
+	}
+
+	ctx, cancel := context.WithTimeout(appCtx, StoreTimeout)
+	defer cancel()
+	source, config, _, err := openStorage(ctx)
+	defer source.Close()
+	if errors.Is(err, fs.ErrNotExist) {
+		return errors.New("nothing to migrate yet")
+	}
+	if err != nil {
+		return err
+	}
+
+	report, err := migrateConfig(&config)
+	if err != nil {
+		return err
+	}
+	target := source.Name()
+	if *to != "" && *to != target {
+		report = append(report, fmt.Sprintf("storage %s to %s", target, *to))
+		target = *to
+	}
+	sessions := 0
+	for _, t := range config.Trackers {
+		sessions += len(t.Sessions)
+	}
+
+	if len(report) == 0 {
+		fmt.Fprintf(stdout, "Already up to date: version %d in %s, %d trackers and %d sessions\n", SchemaVersion, target, len(config.Trackers), sessions)
+		return nil
+	}
+	if *dryRun {
+		fmt.Fprintln(stdout, "Would migrate, nothing changed:")
+		fmt.Fprintln(stdout, strings.Join(report, "\n"))
+		return nil
+	}
+
+	if !*noBackup {
+		file, err := backup(ctx, source)
+		if err != nil {
+			return fmt.Errorf("backing up: %w", err)
+		}
+		report = append(report, "backed up to "+file+", copy it to "+configFile()+" to revert")
+	}
+
+	dest := source
+	if target != source.Name() {
+		dest, err = newStorage(target)
+		if err != nil {
+			return err
+		}
+		defer dest.Close()
+	}
+	config.Storage = target
+	err = dest.Save(ctx, config)
+	if err != nil {
+		return err
+	}
+	if target != StorageYAML {
+		// the configuration file points to the backend
+		pointer, _ := newStorage(StorageYAML)
+		err = pointer.Save(ctx, Config{Version: SchemaVersion, Settings: Settings{Storage: target}, Trackers: []*Tracker{}})
+		if err != nil {
+			return err
+		}
+	}
+	report = append(report, fmt.Sprintf("migrated %d trackers and %d sessions", len(config.Trackers), sessions))
+	fmt.Fprintln(stdout, strings.Join(report, "\n"))
+	return nil
+}
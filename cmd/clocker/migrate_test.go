@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const legacyConfig = `- label: Acme
+  elapsed: 2h0m0s
+  sessions:
+    - start: 2026-03-02T09:00:00Z
+      end: 2026-03-02T11:00:00Z
+- label: Globex
+  elapsed: 0s
+`
+
+func writeLegacyConfig(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	store = nil
+	err := os.WriteFile(configFile(), []byte(legacyConfig), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	home := writeLegacyConfig(t)
+
+	var out bytes.Buffer
+	err := migrateCommand([]string{"--dry-run", "--to", StorageJSON}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range []string{"schema version 0 to 1", "legacy list", "2 trackers and 1 sessions", "storage yaml to json"} {
+		if !strings.Contains(out.String(), change) {
+			t.Errorf("report misses %q:\n%s", change, out.String())
+		}
+	}
+	if readString(t, configFile()) != legacyConfig {
+		t.Error("dry run changed the configuration file")
+	}
+	files, _ := os.ReadDir(home)
+	if len(files) != 1 {
+		t.Errorf("dry run left %d files, expected the configuration file only", len(files))
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	home := writeLegacyConfig(t)
+
+	var out bytes.Buffer
+	err := migrateCommand([]string{"--to", StorageJSON}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "migrated 2 trackers and 1 sessions") {
+		t.Errorf("report = %s", out.String())
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(home, ".clocker.backup-*.yaml"))
+	if len(backups) != 1 {
+		t.Fatalf("%d backups, expected one", len(backups))
+	}
+	saved, err := decodeConfig([]byte(readString(t, backups[0])))
+	if err != nil || len(saved.Trackers) != 2 || saved.Trackers[0].ID != "" {
+		t.Errorf("backup holds %+v, %v, expected the data as it was", saved, err)
+	}
+
+	trackers = []*Tracker{}
+	readConfig()
+	if store.Name() != StorageJSON || len(trackers) != 2 {
+		t.Fatalf("read %d trackers from %s, expected 2 from json", len(trackers), store.Name())
+	}
+	migrated, err := decodeConfig([]byte(readString(t, homeFile(JSONFile))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrated.Version != SchemaVersion || migrated.Trackers[0].ID == "" || migrated.Trackers[0].Sessions[0].ID == "" {
+		t.Errorf("migrated %+v, expected versioned data with IDs", migrated)
+	}
+
+	out.Reset()
+	err = migrateCommand([]string{"--to", StorageJSON}, &out)
+	if err != nil || !strings.HasPrefix(out.String(), "Already up to date") {
+		t.Errorf("migrating again = %q, %v", out.String(), err)
+	}
+}
+
+func TestMigrateNewer(t *testing.T) {
+	writeLegacyConfig(t)
+	_ = os.WriteFile(configFile(), []byte("version: 99\ntrackers: []\n"), 0o600)
+	err := migrateCommand(nil, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("migrate() = %v, expected to refuse newer data", err)
+	}
+}
+
+func readString(t *testing.T, file string) string {
+	t.Helper()
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(contents)
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/notify"
+)
+
+// Alert kinds, each routed to its own notification channels.
+const (
+	AlertBudget = "budget"
+	AlertIdle   = "idle"
+	AlertPower  = "power"
+	AlertTimer  = "timer"
+	AlertAlarm  = "alarm"
+	AlertGoal   = "goal"
+)
+
+var alertKinds = []string{
+	AlertBudget,
+	AlertIdle,
+	AlertPower,
+	AlertTimer,
+	AlertAlarm,
+	AlertGoal,
+}
+
+// defaultRoute is used for alerts without a configured one.
+var defaultRoute = []string{notify.ChannelDesktop, notify.ChannelSound}
+
+var desktopNotifier = notify.New(fyneNotifier{})
+
+type NotifySettings struct {
+	// Routes list the channels of each alert kind, an empty list silencing
+	// it.
+	Routes  map[string][]string   `yaml:"routes,omitempty"`
+	Webhook notify.WebhookOptions `yaml:"webhook,omitempty"`
+	Email   notify.EmailOptions   `yaml:"email,omitempty"`
+}
+
+func (s *NotifySettings) route(kind string) []string {
+	if route, ok := s.Routes[kind]; ok {
+		return route
+	}
+	return defaultRoute
+}
+
+// setRoute only keeps the routes differing from the default one.
+func (s *NotifySettings) setRoute(kind string, route []string) {
+	if slices.Equal(route, defaultRoute) {
+		delete(s.Routes, kind)
+		return
+	}
+	if s.Routes == nil {
+		s.Routes = map[string][]string{}
+	}
+	s.Routes[kind] = route
+}
+
+// fyneNotifier relies on the toolkit notifications, used whenever no native
+// backend is available.
+type fyneNotifier struct{}
+
+func (fyneNotifier) Name() string {
+	return "fyne"
+}
+
+func (fyneNotifier) Notify(title, body string) error {
+	fyne.CurrentApp().SendNotification(fyne.NewNotification(title, body))
+	return nil
+}
+
+func alertTitle(kind string) string {
+	switch kind {
+	case AlertBudget:
+		return "Budget alerts"
+	case AlertIdle:
+		return "Idle return"
+	case AlertPower:
+		return "Battery critical"
+	case AlertTimer:
+		return "Timer over"
+	case AlertAlarm:
+		return "Alarms"
+	case AlertGoal:
+		return "Weekly goal reached"
+	}
+	return kind
+}
+
+// sortedChannels lists the selected channels in the order they're offered,
+// whatever the order they got ticked in.
+func sortedChannels(selected []string) []string {
+	channels := []string{}
+	for _, c := range notify.Channels {
+		if slices.Contains(selected, c) {
+			channels = append(channels, c)
+		}
+	}
+	return channels
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func newRouter(s NotifySettings) *notify.Router {
+	return &notify.Router{
+		Channels: map[string]notify.Notifier{
+			notify.ChannelDesktop: notify.Desktop(desktopNotifier),
+			notify.ChannelSound: notify.Func(notify.ChannelSound, func(notify.Notification) error {
+				go playSound("sounds/alert.wav")
+				return nil
+			}),
+			notify.ChannelWebhook: notify.NewWebhook(s.Webhook),
+			notify.ChannelEmail:   notify.NewEmail(s.Email),
+		},
+		Routes:  s.Routes,
+		Default: defaultRoute,
+	}
+}
+
+// notifyUser raises an alert through the channels configured for its kind.
+// Remote ones may block for a while, hence sending in the background.
+func notifyUser(kind, title, body string) {
+	log.Println(title, ":", body)
+	router := newRouter(settings.Notifications)
+	n := notify.Notification{Kind: kind, Title: title, Body: body, At: time.Now()}
+	go func() {
+		err := router.Notify(n)
+		if err != nil {
+			log.Println(fmt.Errorf("notifying %s alert: %w", kind, err))
+		}
+	}()
+}
+
+// playSound plays a sound asset. Players need an actual file, so it gets
+// copied to the user cache first.
+func playSound(name string) {
+	data, err := fs.ReadFile(resources, name)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	file := filepath.Join(dir, "clocker", path.Base(name))
+	_ = os.MkdirAll(filepath.Dir(file), 0o755)
+	err = os.WriteFile(file, data, 0o644)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	err = notify.PlaySound(file)
+	if err != nil {
+		log.Println(err)
+	}
+}
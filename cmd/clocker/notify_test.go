@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gxben/clocker/pkg/notify"
+)
+
+func TestNotifyRoutes(t *testing.T) {
+	s := NotifySettings{}
+	if !slices.Equal(s.route(AlertBudget), defaultRoute) {
+		t.Fatalf("route = %v, expected the default one", s.route(AlertBudget))
+	}
+
+	s.setRoute(AlertBudget, sortedChannels([]string{notify.ChannelWebhook, notify.ChannelDesktop}))
+	s.setRoute(AlertIdle, []string{})
+	s.setRoute(AlertPower, defaultRoute)
+	if len(s.Routes) != 2 {
+		t.Errorf("routes = %v, expected the default one left out", s.Routes)
+	}
+	if route := s.route(AlertBudget); !slices.Equal(route, []string{notify.ChannelDesktop, notify.ChannelWebhook}) {
+		t.Errorf("budget route = %v", route)
+	}
+	if route := s.route(AlertIdle); len(route) != 0 {
+		t.Errorf("idle route = %v, expected silenced", route)
+	}
+
+	// silenced alerts survive a round trip through the configuration
+	settings = Settings{Notifications: s}
+	contents, err := encodeConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := decodeConfig(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route, ok := config.Notifications.Routes[AlertIdle]; !ok || len(route) != 0 {
+		t.Errorf("idle route read back as %v", route)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// width of the timeline drawn for each overlap
+	overlapBarWidth  = 240
+	overlapBarHeight = 10
+)
+
+// trackedSession is a session along with the tracker holding it.
+type trackedSession struct {
+	Tracker *Tracker
+	Session Session
+}
+
+// end returns when the session ended, running ones ending at now.
+func (ts trackedSession) end(now time.Time) time.Time {
+	if ts.Session.Open() {
+		return now
+	}
+	return ts.Session.End
+}
+
+// Overlap is time tracked by two trackers at once, from Start to End.
+type Overlap struct {
+	First  trackedSession
+	Second trackedSession
+	Start  time.Time
+	End    time.Time
+}
+
+func (o Overlap) String() string {
+	return fmt.Sprintf("%s and %s both track %s to %s", o.First.Tracker.Label, o.Second.Tracker.Label,
+		o.Start.Format(SessionTimeFormat), o.End.Format("15:04"))
+}
+
+// findOverlaps lists the sessions of different trackers overlapping in
+// time, running ones lasting until now, earliest first. Overlaps within a
+// tracker are left to doctor.
+func findOverlaps(list []*Tracker, now time.Time) []Overlap {
+	sessions := []trackedSession{}
+	for _, t := range list {
+		for _, s := range t.Sessions {
+			if s.Open() || s.End.After(s.Start) {
+				sessions = append(sessions, trackedSession{t, s})
+			}
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Session.Start.Before(sessions[j].Session.Start)
+	})
+
+	overlaps := []Overlap{}
+	for i, first := range sessions {
+		end := first.end(now)
+		for _, second := range sessions[i+1:] {
+			if !second.Session.Start.Before(end) {
+				break
+			}
+			if second.Tracker == first.Tracker {
+				continue
+			}
+			overlaps = append(overlaps, Overlap{
+				First:  first,
+				Second: second,
+				Start:  second.Session.Start,
+				End:    earlier(end, second.end(now)),
+			})
+		}
+	}
+	return overlaps
+}
+
+func earlier(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// CutSession takes [from, to) out of an unlocked session, which gets
+// trimmed, split in two around it, or deleted when nothing is left.
+func (t *Tracker) CutSession(id string, from, to time.Time) error {
+	idx := t.sessionIndex(id)
+	if idx < 0 {
+		return errNoSession
+	}
+	s := t.Sessions[idx]
+	switch {
+	case s.Locked:
+		return errors.New("session is locked, unlock it first")
+	case s.Open():
+		return errors.New("session is running, stop it first")
+	}
+	from, to = later(from, s.Start), earlier(to, s.End)
+	switch {
+	case !to.After(from):
+		return nil
+	case from.Equal(s.Start) && to.Equal(s.End):
+		return t.DeleteSession(id)
+	case from.Equal(s.Start):
+		return t.UpdateSession(id, to, s.End)
+	case to.Equal(s.End):
+		return t.UpdateSession(id, s.Start, from)
+	}
+
+	err := t.UpdateSession(id, s.Start, from)
+	if err != nil {
+		return err
+	}
+	rest := newSession(to)
+	rest.End, rest.Note, rest.Invoice = s.End, s.Note, s.Invoice
+	t.Sessions = append(t.Sessions, rest)
+	t.sortSessions()
+	if t.inCounter(&rest) {
+		t.Elapsed += rest.Duration()
+	}
+	audit("split", t, "session=%s new=%s %s/%s", s.ID, rest.ID, rest.Start.Format(time.RFC3339), rest.End.Format(time.RFC3339))
+	t.refreshElapsed()
+	refreshTotals()
+	return nil
+}
+
+// cutAction names what cutting the overlap out of the session does.
+func cutAction(s Session, o Overlap) string {
+	switch {
+	case !o.Start.After(s.Start) && !o.End.Before(s.End):
+		return "Delete"
+	case o.Start.After(s.Start) && o.End.Before(s.End):
+		return "Split"
+	default:
+		return "Trim"
+	}
+}
+
+// overlapBar draws the session over the span of both sessions, the
+// overlapping part highlighted.
+func overlapBar(s trackedSession, o Overlap, now time.Time) fyne.CanvasObject {
+	from := earlier(o.First.Session.Start, o.Second.Session.Start)
+	to := later(o.First.end(now), o.Second.end(now))
+	x := func(at time.Time) float32 {
+		return overlapBarWidth * float32(at.Sub(from)) / float32(to.Sub(from))
+	}
+
+	track := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	track.Resize(fyne.NewSize(overlapBarWidth, overlapBarHeight))
+	session := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+	session.Move(fyne.NewPos(x(s.Session.Start), 0))
+	session.Resize(fyne.NewSize(x(s.end(now))-x(s.Session.Start), overlapBarHeight))
+	overlap := canvas.NewRectangle(color.NRGBA{R: 0xd3, G: 0x2f, B: 0x2f, A: 0xff})
+	overlap.Move(fyne.NewPos(x(o.Start), 0))
+	overlap.Resize(fyne.NewSize(x(o.End)-x(o.Start), overlapBarHeight))
+
+	size := canvas.NewRectangle(color.Transparent)
+	size.SetMinSize(fyne.NewSize(overlapBarWidth, overlapBarHeight))
+	return container.NewStack(size, container.NewWithoutLayout(track, session, overlap))
+}
+
+// overlapsDialog lists the sessions of different trackers overlapping in
+// time, offering to trim, split or delete one of them.
+func overlapsDialog(w fyne.Window) {
+	list := container.NewVBox()
+	var refresh func()
+	refresh = func() {
+		list.RemoveAll()
+		now := time.Now()
+		overlaps := findOverlaps(trackers, now)
+		if len(overlaps) == 0 {
+			list.Add(widget.NewLabel("No overlapping sessions."))
+			return
+		}
+		for _, o := range overlaps {
+			header := fmt.Sprintf("%s to %s  %s on both", o.Start.Format("Mon 02 Jan 15:04"), o.End.Format("15:04"),
+				shortDur(o.End.Sub(o.Start).Round(time.Minute)))
+			list.Add(widget.NewLabelWithStyle(header, leadingAlignment(), fyne.TextStyle{Bold: true}))
+			for _, ts := range []trackedSession{o.First, o.Second} {
+				t, s := ts.Tracker, ts.Session
+				text := fmt.Sprintf("%s  %s to %s", t.Label, s.Start.Format("15:04"), ts.end(now).Format("15:04"))
+				cut := widget.NewButton(cutAction(s, o), func() {
+					guardClosedPeriods(w, t, "change this session", []timeRange{{s.Start, s.End}}, func() {
+						err := t.CutSession(s.ID, o.Start, o.End)
+						if err != nil {
+							showError(err, w)
+						}
+						saveConfig()
+						refresh()
+					})
+				})
+				trash := newIconButton("Delete session of "+t.Label, theme.DeleteIcon(), func() {
+					guardClosedPeriods(w, t, "delete this session", []timeRange{{s.Start, s.End}}, func() {
+						err := t.DeleteSession(s.ID)
+						if err != nil {
+							showError(err, w)
+						}
+						saveConfig()
+						refresh()
+					})
+				})
+				if s.Locked || s.Open() {
+					cut.Disable()
+					trash.Disable()
+				}
+				if cutAction(s, o) == "Delete" {
+					cut.Hide()
+				}
+				switch {
+				case s.Locked:
+					text += "  (approved)"
+				case s.Open():
+					text += "  (running)"
+				}
+				label := widget.NewLabel(text)
+				label.Alignment = leadingAlignment()
+				list.Add(newRow(nil, nil, newLine(cut, trash), container.NewVBox(label, overlapBar(ts, o, now))))
+			}
+		}
+	}
+	refresh()
+
+	d := dialog.NewCustom("Overlapping sessions", "Close", container.NewVScroll(list), w)
+	d.Resize(fyne.NewSize(460, 480))
+	showDialog(d)
+}
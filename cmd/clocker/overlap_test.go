@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	newTestWindow(t, "Acme", "Globex", "Initech")
+	acme, globex, initech := trackers[0], trackers[1], trackers[2]
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	acme.Sessions = []Session{
+		{ID: "a", Start: day, End: day.Add(2 * time.Hour)},
+		{ID: "b", Start: day.Add(4 * time.Hour), End: day.Add(5 * time.Hour)},
+	}
+	globex.Sessions = []Session{{ID: "c", Start: day.Add(90 * time.Minute), End: day.Add(3 * time.Hour)}}
+	// sessions only touching don't overlap
+	initech.Sessions = []Session{{ID: "d", Start: day.Add(5 * time.Hour), End: day.Add(6 * time.Hour)}}
+
+	overlaps := findOverlaps(trackers, time.Now())
+	if len(overlaps) != 1 {
+		t.Fatalf("overlaps = %+v, expected one", overlaps)
+	}
+	o := overlaps[0]
+	if o.First.Session.ID != "a" || o.Second.Session.ID != "c" || !o.Start.Equal(day.Add(90*time.Minute)) || !o.End.Equal(day.Add(2*time.Hour)) {
+		t.Errorf("overlap = %+v", o)
+	}
+	if o.String() != "Acme and Globex both track 2026-03-02 10:30 to 11:00" {
+		t.Errorf("overlap shown as %q", o)
+	}
+
+	// a running session lasts until now
+	initech.Sessions = append(initech.Sessions, newSession(day.Add(150*time.Minute)))
+	if overlaps = findOverlaps(trackers, day.Add(7*time.Hour)); len(overlaps) != 3 {
+		t.Errorf("overlaps with a running session = %+v", overlaps)
+	}
+}
+
+func TestCutSession(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	acme.Since = day
+	acme.Sessions = []Session{{ID: "a", Start: day, End: day.Add(4 * time.Hour), Note: "review"}}
+	acme.Elapsed = 4 * time.Hour
+
+	// split around the middle
+	err := acme.CutSession("a", day.Add(time.Hour), day.Add(2*time.Hour))
+	if err != nil || len(acme.Sessions) != 2 || !acme.Sessions[0].End.Equal(day.Add(time.Hour)) ||
+		!acme.Sessions[1].Start.Equal(day.Add(2*time.Hour)) || acme.Sessions[1].Note != "review" {
+		t.Fatalf("split = %v %+v", err, acme.Sessions)
+	}
+	// trimmed at its end
+	rest := acme.Sessions[1].ID
+	err = acme.CutSession(rest, day.Add(3*time.Hour), day.Add(5*time.Hour))
+	if err != nil || !acme.Sessions[1].End.Equal(day.Add(3*time.Hour)) {
+		t.Errorf("trim = %v %+v", err, acme.Sessions)
+	}
+	// deleted when covered
+	err = acme.CutSession("a", day.Add(-time.Hour), day.Add(time.Hour))
+	if err != nil || len(acme.Sessions) != 1 || acme.Sessions[0].ID != rest {
+		t.Errorf("delete = %v %+v", err, acme.Sessions)
+	}
+	if acme.Elapsed != time.Hour {
+		t.Errorf("elapsed = %s, expected the time left", acme.Elapsed)
+	}
+
+	acme.Sessions[0].Locked = true
+	if err = acme.CutSession(rest, day, day.Add(5*time.Hour)); err == nil {
+		t.Error("cut an approved session")
+	}
+}
+
+func TestOverlapsDialog(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex")
+	acme, globex := trackers[0], trackers[1]
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	acme.Sessions = []Session{{ID: "a", Start: day, End: day.Add(3 * time.Hour)}}
+	globex.Sessions = []Session{{ID: "b", Start: day.Add(time.Hour), End: day.Add(2 * time.Hour)}}
+
+	tapIcon(t, w, "Overlapping sessions")
+	tapButton(t, w, "Split")
+	if len(acme.Sessions) != 2 || !acme.Sessions[0].End.Equal(day.Add(time.Hour)) || !acme.Sessions[1].Start.Equal(day.Add(2*time.Hour)) {
+		t.Errorf("acme sessions = %+v, expected split around globex", acme.Sessions)
+	}
+	if overlaps := findOverlaps(trackers, time.Now()); len(overlaps) != 0 {
+		t.Errorf("overlaps left: %+v", overlaps)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gxben/clocker/pkg/export"
+)
+
+const (
+	// the working week projections are based on, mondays to fridays
+	WorkDayStart = 9 * time.Hour
+	WorkDayEnd   = 17 * time.Hour
+	WorkDays     = 5
+	// MinPaceFraction is the share of the working week to be over before
+	// projecting, earlier ones being mostly noise
+	MinPaceFraction = 0.1
+)
+
+// workedFraction is how much of the working week started on week is over
+// at now, between 0 and 1.
+func workedFraction(week, now time.Time) float64 {
+	worked := time.Duration(0)
+	for day := week; day.Before(now) && day.Before(export.AddDays(week, 7)); day = export.AddDays(day, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		start, end := clockOn(day, WorkDayStart), clockOn(day, WorkDayEnd)
+		if now.Before(end) {
+			end = now
+		}
+		if end.After(start) {
+			worked += end.Sub(start)
+		}
+	}
+	return float64(worked) / float64(WorkDays*(WorkDayEnd-WorkDayStart))
+}
+
+// clockOn returns the wall clock time of the day, e.g. 09:00 rather than
+// 9 hours after midnight, which differ on daylight saving time changes.
+func clockOn(day time.Time, clock time.Duration) time.Time {
+	year, month, d := day.Date()
+	return time.Date(year, month, d, 0, 0, 0, int(clock), day.Location())
+}
+
+// weekTime is the time tracked from week up to now.
+func (t *Tracker) weekTime(week, now time.Time) time.Duration {
+	total := time.Duration(0)
+	// sessions being sorted, only the last ones are in the week
+	for i := len(t.Sessions) - 1; i >= 0; i-- {
+		s := &t.Sessions[i]
+		end := s.End
+		if s.Open() {
+			end = now
+		}
+		if !end.After(week) {
+			break
+		}
+		start := s.Start
+		if start.Before(week) {
+			start = week
+		}
+		total += end.Sub(start)
+	}
+	return total
+}
+
+// projection is the weekly total the tracker would reach at its current
+// pace, to the quarter of an hour, unknown early in the working week.
+func (t *Tracker) projection(now time.Time) (time.Duration, bool) {
+	week := startOfWeek(now)
+	fraction := workedFraction(week, now)
+	if fraction < MinPaceFraction {
+		return 0, false
+	}
+	projected := time.Duration(float64(t.weekTime(week, now)) / fraction).Round(time.Hour / 4)
+	return projected, projected > 0
+}
+
+// paceText tells the projected weekly total, and whether it hits the goal.
+func (t *Tracker) paceText(now time.Time) string {
+	projected, ok := t.projection(now)
+	if !ok {
+		return ""
+	}
+	text := " → " + shortDur(projected)
+	if t.Goal > 0 {
+		pace := "behind"
+		if projected >= t.Goal {
+			pace = "on pace"
+		}
+		text += fmt.Sprintf(" of %s, %s", shortDur(t.Goal), pace)
+	}
+	return text
+}
+
+// checkGoal notifies the user the first time the tracker reaches its
+// weekly goal in the week of now, telling whether it just did.
+func (t *Tracker) checkGoal(now time.Time) bool {
+	if t.Goal <= 0 {
+		return false
+	}
+	week := startOfWeek(now)
+	if !t.Reached.Before(week) || t.weekTime(week, now) < t.Goal {
+		return false
+	}
+	t.Reached = week
+	notifyUser(AlertGoal, t.Label, fmt.Sprintf("The weekly goal of %s is reached.", shortDur(t.Goal)))
+	return true
+}
+
+// parseGoal reads a weekly goal as hours, e.g. 30 or 37.5, or a duration
+// such as 37h30m.
+func parseGoal(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if hours, err := strconv.ParseFloat(s, 64); err == nil && hours >= 0 {
+		return time.Duration(hours * float64(time.Hour)).Round(time.Minute), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid weekly goal %q", s)
+	}
+	return d, nil
+}
+
+func formatGoal(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return shortDur(d)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProjection(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	week := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	acme.Sessions = []Session{
+		// last week, left out
+		{ID: "a", Start: week.Add(-2 * time.Hour), End: week.Add(-time.Hour)},
+		{ID: "b", Start: week.Add(9 * time.Hour), End: week.Add(17 * time.Hour)},
+		{ID: "c", Start: week.Add(33 * time.Hour)},
+	}
+
+	// monday morning is too early to tell
+	if _, ok := acme.projection(week.Add(10 * time.Hour)); ok {
+		t.Error("projected after an hour of the week")
+	}
+	if f := workedFraction(week, week.AddDate(0, 0, 5)); f != 1 {
+		t.Errorf("working week %.0f%% over on saturday", 100*f)
+	}
+
+	// tuesday 13:00, 12h spent in 12h of 40h
+	now := week.Add(37 * time.Hour)
+	projected, ok := acme.projection(now)
+	if !ok || projected != 40*time.Hour {
+		t.Fatalf("projected %s, expected 40h", projected)
+	}
+	acme.Goal = 35 * time.Hour
+	if text := acme.paceText(now); text != " → 40h of 35h, on pace" {
+		t.Errorf("pace shown as %q", text)
+	}
+	acme.Goal = 45 * time.Hour
+	if text := acme.paceText(now); text != " → 40h of 45h, behind" {
+		t.Errorf("pace shown as %q", text)
+	}
+}
+
+func TestCheckGoal(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	week := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	acme.Sessions = []Session{
+		{ID: "a", Start: week.Add(9 * time.Hour), End: week.Add(17 * time.Hour)},
+		{ID: "b", Start: week.Add(33 * time.Hour)},
+	}
+	if acme.checkGoal(week.Add(35 * time.Hour)) {
+		t.Error("no goal, yet reached")
+	}
+
+	acme.Goal = 10 * time.Hour
+	if acme.checkGoal(week.Add(34 * time.Hour)) {
+		t.Error("reached the goal with 9h out of 10h")
+	}
+	if !acme.checkGoal(week.Add(35*time.Hour)) || !acme.Reached.Equal(week) {
+		t.Fatalf("goal reached the week of %s", acme.Reached)
+	}
+	// once per week
+	if acme.checkGoal(week.Add(36 * time.Hour)) {
+		t.Error("goal notified twice in the same week")
+	}
+	next := week.AddDate(0, 0, 7)
+	acme.Sessions[1].End = next.Add(time.Hour)
+	if acme.checkGoal(next.Add(2 * time.Hour)) {
+		t.Error("goal of next week reached after an hour")
+	}
+}
+
+func TestClockOn(t *testing.T) {
+	setLocal(t, "Europe/Paris")
+	// 9 hours after midnight is 10:00 when clocks go forward
+	day := time.Date(2026, 3, 29, 0, 0, 0, 0, time.Local)
+	if at := clockOn(day, WorkDayStart); at.Format(time.DateTime) != "2026-03-29 09:00:00" || at.Sub(day) != 8*time.Hour {
+		t.Errorf("work starts at %s", at)
+	}
+}
+
+func TestParseGoal(t *testing.T) {
+	for text, expected := range map[string]time.Duration{"": 0, "30": 30 * time.Hour, " 37.5 ": 37*time.Hour + 30*time.Minute, "20h30m": 20*time.Hour + 30*time.Minute} {
+		if d, err := parseGoal(text); err != nil || d != expected {
+			t.Errorf("parseGoal(%q) = %s, %v", text, d, err)
+		}
+	}
+	for _, text := range []string{"-3", "lots", "-1h"} {
+		if _, err := parseGoal(text); err == nil {
+			t.Errorf("parsed %q as a goal", text)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/export"
+	"github.com/gxben/clocker/pkg/invoice"
+)
+
+// Period is a closed accounting date range, both ends included.
+type Period struct {
+	From time.Time `yaml:"from"`
+	To   time.Time `yaml:"to"`
+}
+
+func (p Period) Contains(at time.Time) bool {
+	return !at.Before(p.From) && at.Before(export.AddDays(p.To, 1))
+}
+
+// timeRange is the time from From up to To excluded, or the instant From
+// when To isn't after it.
+type timeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Overlaps tells whether some of the range falls into the period.
+func (p Period) Overlaps(r timeRange) bool {
+	if !r.To.After(r.From) {
+		return p.Contains(r.From)
+	}
+	return r.From.Before(export.AddDays(p.To, 1)) && r.To.After(p.From)
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%s to %s", p.From.Format(invoice.DateFormat), p.To.Format(invoice.DateFormat))
+}
+
+// closedPeriod returns the first closed period the range overlaps.
+func closedPeriod(r timeRange) (Period, bool) {
+	for _, p := range settings.ClosedPeriods {
+		if p.Overlaps(r) {
+			return p, true
+		}
+	}
+	return Period{}, false
+}
+
+func formatPeriods(periods []Period) string {
+	lines := []string{}
+	for _, p := range periods {
+		lines = append(lines, p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parsePeriods reads one "YYYY-MM-DD to YYYY-MM-DD" range per line.
+func parsePeriods(s string) ([]Period, error) {
+	periods := []Period{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		from, to, found := strings.Cut(line, " to ")
+		if !found {
+			return nil, fmt.Errorf("invalid period %q", line)
+		}
+		f, err := parseDate(from)
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseDate(to)
+		if err != nil {
+			return nil, err
+		}
+		if t.Before(f) {
+			return nil, fmt.Errorf("invalid period %q, it ends before it starts", line)
+		}
+		periods = append(periods, Period{From: f, To: t})
+	}
+	return periods, nil
+}
+
+// setClosedPeriods replaces the closed periods, recording the ones being
+// closed and re-opened in the audit log.
+func setClosedPeriods(periods []Period) {
+	contains := func(list []Period, p Period) bool {
+		for _, c := range list {
+			if c.From.Equal(p.From) && c.To.Equal(p.To) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range settings.ClosedPeriods {
+		if !contains(periods, p) {
+			audit("reopen", nil, "period=%s", p)
+		}
+	}
+	for _, p := range periods {
+		if !contains(settings.ClosedPeriods, p) {
+			audit("close", nil, "period=%s", p)
+		}
+	}
+	settings.ClosedPeriods = periods
+}
+
+// guardClosedPeriods runs the action right away unless one of the given
+// ranges overlaps a closed period, in which case the user has to
+// explicitly override it first. Overrides are recorded in the audit log.
+func guardClosedPeriods(w fyne.Window, t *Tracker, action string, ranges []timeRange, do func()) {
+	for _, r := range ranges {
+		p, closed := closedPeriod(r)
+		if !closed {
+			continue
+		}
+
+		text := fmt.Sprintf("The period %s is closed.\nDo you want to override it and %s anyway ?", p, action)
+		showConfirm("Closed period", text, func(b bool) {
+			if !b {
+				return
+			}
+			audit("override", t, "period=%s action=%q", p, action)
+			do()
+		}, w)
+		return
+	}
+	do()
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodOverlaps(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2026, 3, d, hour, 0, 0, 0, time.Local)
+	}
+	p := Period{From: day(2, 0), To: day(8, 0)}
+	for _, c := range []struct {
+		r        timeRange
+		expected bool
+	}{
+		{timeRange{day(3, 9), day(3, 17)}, true},
+		// spanning the whole period
+		{timeRange{day(1, 20), day(9, 8)}, true},
+		{timeRange{day(8, 23), day(9, 1)}, true},
+		// ending when the period starts
+		{timeRange{day(1, 20), day(2, 0)}, false},
+		{timeRange{day(9, 0), day(9, 1)}, false},
+		// instants
+		{timeRange{From: day(2, 0)}, true},
+		{timeRange{From: day(9, 0)}, false},
+	} {
+		if p.Overlaps(c.r) != c.expected {
+			t.Errorf("%s overlaps %v: %t", p, c.r, !c.expected)
+		}
+	}
+}
+
+func TestGuardClosedPeriods(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+	acme := trackers[0]
+	settings.ClosedPeriods = []Period{{From: time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local), To: time.Date(2026, 3, 8, 0, 0, 0, 0, time.Local)}}
+	done := 0
+	do := func() { done++ }
+
+	// ending right when the period starts
+	guardClosedPeriods(w, acme, "add this session", []timeRange{{time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local), settings.ClosedPeriods[0].From}}, do)
+	if done != 1 {
+		t.Fatal("session before the period guarded")
+	}
+
+	// starting before and ending after the period
+	guardClosedPeriods(w, acme, "add this session", []timeRange{{time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local), time.Date(2026, 3, 9, 8, 0, 0, 0, time.Local)}}, do)
+	if done != 1 {
+		t.Fatal("session over the period added without confirmation")
+	}
+	tapButton(t, w, "Yes")
+	if done != 2 {
+		t.Error("override not applied")
+	}
+}
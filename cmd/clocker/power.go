@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/power"
+)
+
+const (
+	// HeartbeatFile holds the last time clocker was seen running trackers,
+	// telling when sessions got cut by a power loss or crash.
+	HeartbeatFile = ".clocker.alive"
+)
+
+var (
+	powerMonitor power.Monitor
+	lastBeat     time.Time
+)
+
+func heartbeatFile() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Clean(fmt.Sprintf("%s/%s", home, HeartbeatFile))
+}
+
+// heartbeat records that trackers are still running, once per minute.
+func heartbeat() {
+	now := time.Now()
+	if now.Sub(lastBeat) < HiddenClockFrequency {
+		return
+	}
+	lastBeat = now
+	err := os.WriteFile(heartbeatFile(), []byte(now.Format(time.RFC3339)), 0600)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// lastHeartbeat is when clocker was last seen alive, zero if it exited
+// cleanly.
+func lastHeartbeat() time.Time {
+	content, err := os.ReadFile(heartbeatFile())
+	if err != nil {
+		return time.Time{}
+	}
+	at, _ := time.Parse(time.RFC3339, strings.TrimSpace(string(content)))
+	return at
+}
+
+func clearHeartbeat() {
+	_ = os.Remove(heartbeatFile())
+}
+
+// closeInterrupted ends the session left open by an unexpected exit, at
+// the last time clocker was known alive, and flags it for review.
+func (t *Tracker) closeInterrupted(alive time.Time) {
+	if len(t.Sessions) == 0 {
+		return
+	}
+	s := &t.Sessions[len(t.Sessions)-1]
+	if !s.Open() {
+		return
+	}
+	end := alive
+	if end.Before(s.Start) {
+		end = s.Start
+	}
+	t.closeSession(end)
+	s.Interrupted = true
+	audit("power-loss", t, "session=%s %s end=%s", s.ID, s.Start.Format(time.RFC3339), end.Format(time.RFC3339))
+}
+
+func startPowerMonitoring(w fyne.Window) {
+	m, err := power.New()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Println("Monitoring power state using", m.Name())
+	powerMonitor = m
+
+	go func() {
+		for e := range m.Events() {
+			switch {
+			case e.Shutdown:
+				log.Println("System is shutting down")
+				shutdown()
+				if e.Release != nil {
+					e.Release()
+				}
+			case e.Battery == power.BatteryCritical && anyActive():
+				notifyUser(AlertPower, "Battery critical", "Trackers are still running.")
+				if settings.PowerPrompt {
+					stopTrackersDialog(w)
+				}
+			default:
+				log.Println("Battery level is", e.Battery)
+			}
+		}
+	}()
+}
+
+func stopTrackersDialog(w fyne.Window) {
+	showConfirm("Battery critical", "The system may shut down soon.\nStop running trackers ?", func(b bool) {
+		if !b {
+			return
+		}
+		for _, t := range trackers {
+			if t.Running() {
+				t.Stop()
+			}
+		}
+		update(w)
+	}, w)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/gxben/clocker/pkg/quickentry"
+)
+
+// findLabel returns the tracker with the given label, or nil.
+func findLabel(label string) *Tracker {
+	for _, t := range trackers {
+		if labelKey(t.Label) == labelKey(label) {
+			return t
+		}
+	}
+	return nil
+}
+
+// AddSession records a past session, unless it overlaps another one.
+func (t *Tracker) AddSession(start, end time.Time) error {
+	for idx := range t.Sessions {
+		s := &t.Sessions[idx]
+		if !s.overlaps(start, end) {
+			continue
+		}
+		if s.Open() {
+			return fmt.Errorf("%s runs since %s", t.Label, s.Start.Format(SessionTimeFormat))
+		}
+		return fmt.Errorf("%s already has a session from %s to %s", t.Label, s.Start.Format(SessionTimeFormat), s.End.Format("15:04"))
+	}
+	session := newSession(start)
+	session.End = end
+	t.Sessions = append(t.Sessions, session)
+	t.sortSessions()
+	if t.inCounter(&session) {
+		t.Elapsed += session.Duration()
+	}
+	audit("add", t, "session=%s %s/%s", session.ID, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	t.refreshElapsed()
+	refreshTotals()
+	return nil
+}
+
+// quickAdd records the session a phrase describes, on the tracker with
+// the label it gives, created if there is none.
+func quickAdd(w fyne.Window, phrase string) {
+	e, err := quickentry.Parse(phrase, time.Now())
+	if err != nil {
+		showError(err, w)
+		return
+	}
+	t := findLabel(e.Label)
+	guardClosedPeriods(w, t, "add this session", []timeRange{{e.Start, e.End}}, func() {
+		if t == nil {
+			log.Println("Adding new clock", e.Label)
+			t = NewTracker(e.Label, 0)
+		}
+		err := t.AddSession(e.Start, e.End)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		update(w)
+	})
+}
+
+// makeQuickEntry is the box to type past sessions in, e.g. "2h on Acme
+// yesterday".
+func makeQuickEntry(w fyne.Window) fyne.CanvasObject {
+	box := newEntry()
+	box.SetPlaceHolder("2h on Acme yesterday, Acme 09:00-11:30")
+	// the list refresh brings an empty box back
+	box.OnSubmitted = func(phrase string) {
+		quickAdd(w, phrase)
+	}
+	return box
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuickAdd(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+	yesterday := time.Now().AddDate(0, 0, -1)
+	day := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, time.Local)
+
+	quickAdd(w, "acme 09:00-11:30 yesterday")
+	acme := trackers[0]
+	if len(acme.Sessions) != 1 || !acme.Sessions[0].Start.Equal(day.Add(9*time.Hour)) || acme.Sessions[0].Duration() != 150*time.Minute {
+		t.Fatalf("sessions %+v, expected yesterday 09:00-11:30", acme.Sessions)
+	}
+	if acme.Elapsed != 150*time.Minute {
+		t.Errorf("counter at %s, expected 2h30m", acme.Elapsed)
+	}
+
+	quickAdd(w, "2h on Globex yesterday")
+	if len(trackers) != 2 || trackers[1].Label != "Globex" || len(trackers[1].Sessions) != 1 {
+		t.Fatalf("no Globex tracker with its session")
+	}
+	if _, ok := iconButtons(w)["Start Globex"]; !ok {
+		t.Error("list not refreshed with the new tracker")
+	}
+
+	// overlapping sessions would count time twice
+	quickAdd(w, "1h on Acme at 10:00 yesterday")
+	if len(acme.Sessions) != 1 {
+		t.Error("added an overlapping session")
+	}
+	if len(dialogs) != 1 {
+		t.Errorf("%d dialogs open, expected the error", len(dialogs))
+	}
+}
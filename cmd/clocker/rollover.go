@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock"
+	"github.com/gxben/clocker/pkg/export"
+)
+
+const (
+	// RolloverFrequency is how often the week boundary gets checked, so
+	// that it's caught up soon after the computer wakes up.
+	RolloverFrequency = time.Minute
+)
+
+// WeekTotal is the counter time of a past week, kept when rolling over.
+type WeekTotal struct {
+	Week    time.Time     `yaml:"week"`
+	Elapsed time.Duration `yaml:"elapsed"`
+}
+
+// weekdayNames are the valid week_start values.
+func weekdayNames() []string {
+	names := []string{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		names = append(names, strings.ToLower(day.String()))
+	}
+	return names
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, true
+		}
+	}
+	return time.Monday, false
+}
+
+// weekStart is the configured first day of the week, monday by default.
+func weekStart() time.Weekday {
+	day, _ := parseWeekday(settings.WeekStart)
+	return day
+}
+
+// rollover moves the counter time of the weeks before the given one into
+// the history, and restarts the counter at its beginning. A running
+// session gets split at the boundary.
+func (t *Tracker) rollover(week time.Time) bool {
+	if !t.Since.Before(week) {
+		return false
+	}
+	if n := len(t.Sessions); n > 0 && t.Sessions[n-1].Open() && t.Sessions[n-1].Start.Before(week) {
+		t.closeSession(week)
+		recordSession(t, t.Sessions[len(t.Sessions)-1])
+		t.Sessions = append(t.Sessions, newSession(week))
+		recordSession(t, t.Sessions[len(t.Sessions)-1])
+	}
+
+	totals := map[time.Time]time.Duration{}
+	kept, rolled := time.Duration(0), time.Duration(0)
+	for idx := range t.Sessions {
+		s := &t.Sessions[idx]
+		if s.Open() || !t.inCounter(s) {
+			continue
+		}
+		if !s.Start.Before(week) {
+			kept += s.Duration()
+			continue
+		}
+		totals[startOfWeek(s.Start)] += s.Duration()
+		rolled += s.Duration()
+	}
+	// time counted without sessions goes to the week the counter started,
+	// or the previous one for counters never reset
+	if rest := t.Elapsed - kept - rolled; rest != 0 {
+		since := t.Since
+		if since.IsZero() {
+			since = export.AddDays(week, -7)
+		}
+		totals[startOfWeek(since)] += rest
+	}
+
+	weeks := []time.Time{}
+	for w, d := range totals {
+		if d != 0 {
+			weeks = append(weeks, w)
+		}
+	}
+	sort.Slice(weeks, func(i, j int) bool {
+		return weeks[i].Before(weeks[j])
+	})
+	for _, w := range weeks {
+		t.History = append(t.History, WeekTotal{Week: w, Elapsed: totals[w]})
+	}
+	t.Elapsed = kept
+	t.Since = week
+	return true
+}
+
+// rolloverTrackers restarts all counters once the week is over, when
+// enabled in the settings, telling whether any was.
+func rolloverTrackers(now time.Time) bool {
+	if !settings.Rollover {
+		return false
+	}
+	week := startOfWeek(now)
+	rolled := false
+	for _, t := range trackers {
+		if !t.rollover(week) {
+			continue
+		}
+		audit("rollover", t, "week=%s", week.Format(export.DateFormat))
+		t.refreshElapsed()
+		rolled = true
+	}
+	if rolled {
+		log.Println("Rolled counters over to the week of", week.Format(export.DateFormat))
+		refreshTotals()
+	}
+	return rolled
+}
+
+// runRollover checks for the week boundary until ctx is done, saving the
+// history as soon as counters get rolled over.
+func runRollover(ctx context.Context, clk clock.Clock) {
+	for {
+		stateLock.Lock()
+		rolled := rolloverTrackers(clk.Now())
+		stateLock.Unlock()
+		if rolled {
+			saveConfig()
+		}
+		timer := clk.NewTimer(RolloverFrequency)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock/clocktest"
+)
+
+func TestStartOfWeek(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	settings = Settings{}
+	wednesday := time.Date(2026, 3, 4, 15, 0, 0, 0, time.Local)
+
+	if got := startOfWeek(wednesday); !got.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("week starts on %s, expected monday 2026-03-02", got)
+	}
+	settings.WeekStart = "sunday"
+	if got := startOfWeek(wednesday); !got.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("week starts on %s, expected sunday 2026-03-01", got)
+	}
+	settings.WeekStart = "thursday"
+	if got := startOfWeek(wednesday); !got.Equal(time.Date(2026, 2, 26, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("week starts on %s, expected thursday 2026-02-26", got)
+	}
+}
+
+func TestRollover(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	lastWeek := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	week := lastWeek.AddDate(0, 0, 7)
+	monday, friday := lastWeek.Add(9*time.Hour), lastWeek.AddDate(0, 0, 4).Add(9*time.Hour)
+	acme.Since = lastWeek
+	acme.Sessions = []Session{
+		{ID: "a", Start: monday, End: monday.Add(2 * time.Hour)},
+		{ID: "b", Start: friday, End: friday.Add(time.Hour)},
+		// running since sunday evening
+		{ID: "c", Start: week.Add(-time.Hour)},
+	}
+	// plus half an hour added by hand
+	acme.Elapsed = 3*time.Hour + 30*time.Minute
+
+	clk := clocktest.NewFake(week.Add(-30 * time.Minute))
+	if rolloverTrackers(week.Add(time.Hour)) {
+		t.Fatal("rolled over while disabled")
+	}
+	settings.Rollover = true
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		runRollover(ctx, clk)
+		close(done)
+	}()
+	clk.BlockUntil(1)
+	if !acme.Since.Equal(lastWeek) {
+		t.Fatal("rolled over before the end of the week")
+	}
+	clk.Advance(90 * time.Minute)
+	clk.BlockUntil(1)
+	cancel()
+	<-done
+
+	if !acme.Since.Equal(week) {
+		t.Errorf("counter since %s, expected %s", acme.Since, week)
+	}
+	if len(acme.History) != 1 || !acme.History[0].Week.Equal(lastWeek) || acme.History[0].Elapsed != 4*time.Hour+30*time.Minute {
+		t.Fatalf("history %+v, expected 4h30m for the week of %s", acme.History, lastWeek)
+	}
+	if n := len(acme.Sessions); n != 4 || !acme.Sessions[2].End.Equal(week) || !acme.Sessions[3].Open() || !acme.Sessions[3].Start.Equal(week) {
+		t.Errorf("running session not split at the week boundary: %+v", acme.Sessions)
+	}
+	if acme.Elapsed != 0 {
+		t.Errorf("counter at %s, expected nothing but the running session", acme.Elapsed)
+	}
+
+	// once per week only
+	if rolloverTrackers(week.Add(2 * time.Hour)) {
+		t.Error("rolled over twice in the same week")
+	}
+}
+
+func TestRolloverSkippedWeeks(t *testing.T) {
+	newTestWindow(t, "Acme")
+	settings.Rollover = true
+	acme := trackers[0]
+	first := time.Date(2026, 3, 3, 9, 0, 0, 0, time.Local)
+	acme.Since = startOfWeek(first)
+	acme.Sessions = []Session{
+		{ID: "a", Start: first, End: first.Add(time.Hour)},
+		{ID: "b", Start: first.AddDate(0, 0, 14), End: first.AddDate(0, 0, 14).Add(2 * time.Hour)},
+	}
+	acme.Elapsed = 3 * time.Hour
+
+	rolloverTrackers(first.AddDate(0, 0, 28))
+	if len(acme.History) != 2 || acme.History[0].Elapsed != time.Hour || acme.History[1].Elapsed != 2*time.Hour {
+		t.Errorf("history %+v, expected 1h then 2h two weeks later", acme.History)
+	}
+	if acme.Elapsed != 0 {
+		t.Errorf("counter at %s after rollover", acme.Elapsed)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxSearchResults keeps the result list short enough to be shown.
+const maxSearchResults = 100
+
+// noteMatch is a session whose note, or an expense whose description,
+// matches a search.
+type noteMatch struct {
+	Tracker  *Tracker
+	At       time.Time
+	Duration time.Duration
+	Text     string
+	Expense  bool
+}
+
+// matchesTerms tells whether the text holds all the terms, ignoring case.
+func matchesTerms(text string, terms []string) bool {
+	text = strings.ToLower(text)
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchNotes returns the sessions and expenses of all trackers, archived
+// ones included, described with all the words of the query, most recent
+// first.
+func searchNotes(query string) []noteMatch {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	matches := []noteMatch{}
+	for _, t := range trackers {
+		for _, s := range t.Sessions {
+			if s.Note != "" && matchesTerms(s.Note, terms) {
+				matches = append(matches, noteMatch{Tracker: t, At: s.Start, Duration: s.Duration(), Text: s.Note})
+			}
+		}
+		for _, e := range t.Expenses {
+			if e.Description != "" && matchesTerms(e.Description, terms) {
+				matches = append(matches, noteMatch{Tracker: t, At: e.Date, Text: e.Description, Expense: true})
+			}
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b noteMatch) int {
+		return b.At.Compare(a.At)
+	})
+	return matches
+}
+
+func (m *noteMatch) String() string {
+	if m.Expense {
+		return fmt.Sprintf("%s  %s  expense\n%s", m.At.Format("Mon 02 Jan 2006"), m.Tracker.Label, m.Text)
+	}
+	return fmt.Sprintf("%s  %s  %s\n%s", m.At.Format("Mon 02 Jan 2006 15:04"), m.Tracker.Label,
+		shortDur(m.Duration.Round(time.Second)), m.Text)
+}
+
+// searchDialog looks for words in the notes of all sessions, as typed.
+func searchDialog(w fyne.Window) {
+	query := newEntry()
+	query.SetPlaceHolder("billing module")
+	results := container.NewVBox()
+
+	var d *dialog.CustomDialog
+	query.OnChanged = func(text string) {
+		results.RemoveAll()
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		matches := searchNotes(text)
+		if len(matches) == 0 {
+			results.Add(widget.NewLabel("No matching notes."))
+		}
+		if len(matches) > maxSearchResults {
+			matches = matches[:maxSearchResults]
+		}
+		for _, m := range matches {
+			label := widget.NewLabel(m.String())
+			label.Alignment = leadingAlignment()
+			label.Wrapping = fyne.TextWrapWord
+			t := m.Tracker
+			show := newIconButton("Sessions of "+t.Label, theme.ListIcon(), func() {
+				d.Hide()
+				sessionsDialog(w, t)
+			})
+			if m.Expense {
+				show = newIconButton("Expenses of "+t.Label, theme.ListIcon(), func() {
+					d.Hide()
+					expensesDialog(w, t)
+				})
+			}
+			results.Add(newRow(nil, nil, show, label))
+		}
+	}
+
+	content := container.NewBorder(query, nil, nil, nil, container.NewVScroll(results))
+	d = dialog.NewCustom("Search notes", "Close", content, w)
+	d.Resize(fyne.NewSize(460, 480))
+	showDialog(d)
+	w.Canvas().Focus(query)
+}
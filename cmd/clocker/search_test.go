@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestSearchNotes(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex")
+	acme, globex := trackers[0], trackers[1]
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	acme.Sessions = []Session{
+		{ID: "a", Start: day, End: day.Add(time.Hour), Note: "Billing module: fixed rounding"},
+		{ID: "b", Start: day.AddDate(0, 0, 1), End: day.AddDate(0, 0, 1).Add(time.Hour), Note: "Login page"},
+	}
+	globex.Sessions = []Session{{ID: "c", Start: day.AddDate(0, 0, 2), End: day.AddDate(0, 0, 2).Add(time.Hour), Note: "Reviewed the billing MODULE"}}
+	globex.Expenses = []Expense{{Amount: 12, Date: day.AddDate(0, 0, 3), Description: "Billing module training"}}
+
+	matches := searchNotes("  module billing ")
+	if len(matches) != 3 {
+		t.Fatalf("found %d matches, expected 3", len(matches))
+	}
+	if !matches[0].Expense || matches[1].Tracker != globex || matches[2].Text != acme.Sessions[0].Note {
+		t.Errorf("matches not sorted most recent first: %v", matches)
+	}
+	if len(searchNotes("billing login")) != 0 || len(searchNotes(" ")) != 0 {
+		t.Error("matched notes missing a word")
+	}
+
+	tapIcon(t, w, "Search notes")
+	typeFocused(t, w, "login")
+	found := false
+	for _, o := range test.LaidOutObjects(topDialog(t, w)) {
+		if l, ok := o.(*widget.Label); ok && strings.HasPrefix(l.Text, "Tue 03 Mar 2026 09:00  Acme  1h") {
+			found = strings.HasSuffix(l.Text, "\nLogin page")
+		}
+	}
+	if !found {
+		t.Error("search dialog doesn't show the matching session")
+	}
+}
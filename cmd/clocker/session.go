@@ -0,0 +1,320 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/uuid"
+)
+
+const (
+	SessionTimeFormat = "2006-01-02 15:04"
+)
+
+var errNoSession = errors.New("session no longer exists")
+
+type Session struct {
+	ID          string    `yaml:"id"`
+	Start       time.Time `yaml:"start"`
+	End         time.Time `yaml:"end,omitempty"`
+	Locked      bool      `yaml:"locked,omitempty"`
+	Invoice     string    `yaml:"invoice,omitempty"`
+	Interrupted bool      `yaml:"interrupted,omitempty"`
+	Note        string    `yaml:"note,omitempty"`
+	Modified    time.Time `yaml:"modified,omitempty"`
+}
+
+func newSession(start time.Time) Session {
+	return Session{ID: uuid.New(), Start: start}
+}
+
+func (s *Session) Open() bool {
+	return s.End.IsZero()
+}
+
+func (s *Session) Duration() time.Duration {
+	if s.Open() {
+		return time.Since(s.Start)
+	}
+	return s.End.Sub(s.Start)
+}
+
+func (t *Tracker) closeSession(end time.Time) {
+	if len(t.Sessions) == 0 {
+		return
+	}
+	s := &t.Sessions[len(t.Sessions)-1]
+	if s.Open() {
+		s.End = end
+		if t.inCounter(s) {
+			t.Elapsed += s.Duration()
+		}
+	}
+}
+
+// CounterSessions returns the sessions accounted in the current counter,
+// i.e. the ones started since the last reset.
+func (t *Tracker) CounterSessions() []Session {
+	sessions := []Session{}
+	for _, s := range t.Sessions {
+		if s.Start.Before(t.Since) {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// sessionIndex returns the position of the session with the given ID, or
+// -1 if the tracker doesn't hold it.
+func (t *Tracker) sessionIndex(id string) int {
+	for idx, s := range t.Sessions {
+		if s.ID == id {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (t *Tracker) inCounter(s *Session) bool {
+	return !s.Start.Before(t.Since)
+}
+
+// sortSessions orders sessions by start, keeping the running one last.
+func (t *Tracker) sortSessions() {
+	sort.SliceStable(t.Sessions, func(i, j int) bool {
+		if t.Sessions[i].Open() != t.Sessions[j].Open() {
+			return t.Sessions[j].Open()
+		}
+		return t.Sessions[i].Start.Before(t.Sessions[j].Start)
+	})
+}
+
+// UpdateSession changes the boundaries of an unlocked session, keeping the
+// counter in sync.
+func (t *Tracker) UpdateSession(id string, start, end time.Time) error {
+	idx := t.sessionIndex(id)
+	if idx < 0 {
+		return errNoSession
+	}
+	s := &t.Sessions[idx]
+	if s.Locked {
+		return errors.New("session is locked, unlock it first")
+	}
+	if !end.After(start) {
+		return errors.New("session must end after it starts")
+	}
+
+	before := s.Duration()
+	counted := t.inCounter(s)
+	audit("edit", t, "session=%s %s/%s new=%s/%s", s.ID, s.Start.Format(time.RFC3339), s.End.Format(time.RFC3339),
+		start.Format(time.RFC3339), end.Format(time.RFC3339))
+	s.Start = start
+	s.End = end
+	if counted {
+		t.Elapsed -= before
+	}
+	if t.inCounter(s) {
+		t.Elapsed += s.Duration()
+	}
+	t.refreshElapsed()
+	refreshTotals()
+	return nil
+}
+
+// SetSessionNote describes what was done during an unlocked session.
+func (t *Tracker) SetSessionNote(id, note string) error {
+	idx := t.sessionIndex(id)
+	if idx < 0 {
+		return errNoSession
+	}
+	s := &t.Sessions[idx]
+	if s.Locked {
+		return errors.New("session is locked, unlock it first")
+	}
+	s.Note = strings.TrimSpace(note)
+	return nil
+}
+
+func (t *Tracker) DeleteSession(id string) error {
+	idx := t.sessionIndex(id)
+	if idx < 0 {
+		return errNoSession
+	}
+	s := t.Sessions[idx]
+	if s.Locked {
+		return errors.New("session is locked, unlock it first")
+	}
+	audit("delete", t, "session=%s %s/%s", s.ID, s.Start.Format(time.RFC3339), s.End.Format(time.RFC3339))
+	if t.inCounter(&s) {
+		t.Elapsed -= s.Duration()
+		t.refreshElapsed()
+	}
+	t.Sessions = append(t.Sessions[:idx], t.Sessions[idx+1:]...)
+	refreshTotals()
+	return nil
+}
+
+func parseSessionTime(s string) (time.Time, error) {
+	at, err := time.ParseInLocation(SessionTimeFormat, strings.TrimSpace(s), time.Local)
+	if err != nil {
+		return at, fmt.Errorf("invalid time %q, expected YYYY-MM-DD HH:MM", s)
+	}
+	return at, nil
+}
+
+func editSessionDialog(w fyne.Window, t *Tracker, s Session, done func()) {
+	start := newEntry()
+	start.SetText(s.Start.Format(SessionTimeFormat))
+	end := newEntry()
+	end.SetText(s.End.Format(SessionTimeFormat))
+	note := newEntry()
+	note.SetText(s.Note)
+	note.SetPlaceHolder("What was done")
+	items := []*widget.FormItem{
+		widget.NewFormItem("Start", start),
+		widget.NewFormItem("End", end),
+		widget.NewFormItem("Note", note),
+	}
+
+	showForm("Edit Session", "Update", "Cancel", items, func(b bool) {
+		if !b {
+			return
+		}
+		from, err := parseSessionTime(start.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		to, err := parseSessionTime(end.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		ranges := []timeRange{{s.Start, s.End}, {from, to}}
+		guardClosedPeriods(w, t, "edit this session", ranges, func() {
+			err := t.UpdateSession(s.ID, from, to)
+			if err == nil {
+				err = t.SetSessionNote(s.ID, note.Text)
+			}
+			if err != nil {
+				showError(err, w)
+				return
+			}
+			done()
+		})
+	}, w)
+	w.Canvas().Focus(start)
+}
+
+func unlockWeekDialog(w fyne.Window, t *Tracker, week string, done func()) {
+	text := fmt.Sprintf("Sessions of week %s have been approved.\nUnlocking them is recorded in the audit log.", week)
+	showConfirm("Unlock sessions ?", text, func(b bool) {
+		if !b {
+			return
+		}
+		t.UnlockWeek(week)
+		done()
+	}, w)
+}
+
+func sessionsDialog(w fyne.Window, t *Tracker) {
+	list := container.NewVBox()
+	var refresh func()
+	refresh = func() {
+		list.RemoveAll()
+		if len(t.Sessions) == 0 {
+			list.Add(widget.NewLabel("No sessions recorded yet."))
+		}
+
+		// group sessions per week, most recent first
+		weeks := map[string][]int{}
+		for idx, s := range t.Sessions {
+			week := weekOf(s.Start)
+			weeks[week] = append(weeks[week], idx)
+		}
+		keys := []string{}
+		for week := range weeks {
+			keys = append(keys, week)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+
+		for _, week := range keys {
+			total := time.Duration(0)
+			locked := true
+			for _, idx := range weeks[week] {
+				total += t.Sessions[idx].Duration()
+				locked = locked && t.Sessions[idx].Locked
+			}
+
+			header := widget.NewLabelWithStyle(fmt.Sprintf("Week %s  %s", week, shortDur(total.Round(time.Second))),
+				leadingAlignment(), fyne.TextStyle{Bold: true})
+			lock := widget.NewButtonWithIcon("Lock", theme.ConfirmIcon(), func() {
+				t.LockWeek(week)
+				refresh()
+			})
+			if locked {
+				lock = widget.NewButtonWithIcon("Unlock", theme.CancelIcon(), func() {
+					unlockWeekDialog(w, t, week, refresh)
+				})
+			}
+			list.Add(newRow(nil, nil, lock, header))
+
+			for _, idx := range weeks[week] {
+				s := t.Sessions[idx]
+				text := fmt.Sprintf("%s  %s", s.Start.Format("Mon 02 Jan 15:04"), shortDur(s.Duration().Round(time.Second)))
+				switch {
+				case s.Open():
+					text += "  (running)"
+				case s.Invoice != "":
+					text += fmt.Sprintf("  (%s)", s.Invoice)
+				case s.Interrupted:
+					text += "  (interrupted)"
+				}
+				if s.Note != "" {
+					text += "\n" + s.Note
+				}
+
+				edit := newIconButton("Edit session", theme.DocumentCreateIcon(), func() {
+					editSessionDialog(w, t, s, refresh)
+				})
+				trash := newIconButton("Delete session", theme.DeleteIcon(), func() {
+					guardClosedPeriods(w, t, "delete this session", []timeRange{{s.Start, s.End}}, func() {
+						err := t.DeleteSession(s.ID)
+						if err != nil {
+							showError(err, w)
+						}
+						refresh()
+					})
+				})
+				if s.Locked || s.Open() {
+					edit.Disable()
+					trash.Disable()
+				}
+				label := widget.NewLabel(text)
+				label.Alignment = leadingAlignment()
+				list.Add(newRow(nil, nil, newLine(edit, trash), label))
+			}
+		}
+	}
+	refresh()
+
+	d := dialog.NewCustom(fmt.Sprintf("%s sessions", t.Label), "Close", container.NewVScroll(list), w)
+	d.Resize(fyne.NewSize(420, 480))
+	showDialog(d)
+}
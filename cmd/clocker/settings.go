@@ -0,0 +1,419 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/autostart"
+	"github.com/gxben/clocker/pkg/export"
+	"github.com/gxben/clocker/pkg/invoice"
+	"github.com/gxben/clocker/pkg/notify"
+	"github.com/gxben/clocker/pkg/syncer"
+)
+
+func browseEntry(w fyne.Window, field *entry, extensions []string) fyne.CanvasObject {
+	browse := newIconButton("Browse", theme.FolderOpenIcon(), func() {
+		open := dialog.NewFileOpen(func(uc fyne.URIReadCloser, err error) {
+			if err != nil || uc == nil {
+				return
+			}
+			defer uc.Close()
+			field.SetText(uc.URI().Path())
+		}, w)
+		open.SetFilter(storage.NewExtensionFileFilter(extensions))
+		open.Show()
+	})
+	return newRow(nil, nil, browse, field)
+}
+
+func settingsDialog(w fyne.Window) {
+	// general settings
+	currency := newEntry()
+	currency.SetText(settings.Currency)
+	currency.SetPlaceHolder("EUR")
+
+	rates := newMultiLineEntry()
+	rates.SetText(formatExchangeRates(settings.ExchangeRates))
+	rates.SetPlaceHolder("USD = 0.92")
+
+	alerts := newEntry()
+	alerts.SetText(formatBudgetAlerts(settings.BudgetAlerts))
+
+	presets := newEntry()
+	presets.SetText(formatTimerPresets(settings.TimerPresets))
+
+	idleMinutes := newEntry()
+	idleMinutes.SetText(strconv.Itoa(settings.IdleMinutes))
+
+	menuBar := widget.NewCheck("Keep in menu bar only", nil)
+	menuBar.SetChecked(settings.MenuBar)
+
+	startHidden := widget.NewCheck("Minimized to tray", nil)
+	startHidden.SetChecked(settings.StartHidden)
+
+	startAtLogin := widget.NewCheck("Start at login", func(b bool) {
+		if b {
+			startHidden.Enable()
+		} else {
+			startHidden.Disable()
+		}
+	})
+	startAtLogin.SetChecked(autostart.Enabled())
+	if !startAtLogin.Checked {
+		startHidden.Disable()
+	}
+
+	uiScale := widget.NewSelect(scaleOptions(), nil)
+	uiScale.SetSelected(formatScale(settings.UIScale))
+
+	textScale := widget.NewSelect(scaleOptions(), nil)
+	textScale.SetSelected(formatScale(settings.TextScale))
+
+	powerPrompt := widget.NewCheck("Ask to stop trackers on critical battery", nil)
+	powerPrompt.SetChecked(settings.PowerPrompt)
+
+	backend := widget.NewSelect(storageNames(), nil)
+	backend.SetSelected(storageName())
+
+	contrast := widget.NewCheck("High contrast", nil)
+	contrast.SetChecked(settings.HighContrast)
+
+	week := widget.NewSelect(weekdayNames(), nil)
+	week.SetSelected(strings.ToLower(weekStart().String()))
+
+	rollover := widget.NewCheck("Restart counters every week", nil)
+	rollover.SetChecked(settings.Rollover)
+
+	eventLog := newEntry()
+	eventLog.SetText(settings.EventLog)
+	eventLog.SetPlaceHolder("~/clocker-events.jsonl")
+
+	control := newEntry()
+	control.SetText(settings.ControlFile)
+	control.SetPlaceHolder("~/clocker-control.jsonl")
+
+	general := widget.NewForm(
+		widget.NewFormItem("Currency", currency),
+		&widget.FormItem{Text: "Exchange rates", Widget: rates, HintText: "One currency per line"},
+		&widget.FormItem{Text: "Budget alerts", Widget: alerts, HintText: "Percentages of budget burnt"},
+		&widget.FormItem{Text: "Timer presets", Widget: presets, HintText: "Countdowns in minutes"},
+		&widget.FormItem{Text: "Idle after", Widget: idleMinutes, HintText: "Minutes without activity, 0 to disable"},
+		widget.NewFormItem("UI scale", uiScale),
+		widget.NewFormItem("Text size", textScale),
+		widget.NewFormItem("Theme", contrast),
+		widget.NewFormItem("Power", powerPrompt),
+		widget.NewFormItem("Week starts on", week),
+		&widget.FormItem{Text: "Rollover", Widget: rollover, HintText: "Past weeks are kept in the history"},
+		&widget.FormItem{Text: "Storage", Widget: backend, HintText: "Moved on save, the previous one is left as is"},
+		&widget.FormItem{Text: "Event log", Widget: eventLog, HintText: "JSON Lines file events get appended to"},
+		&widget.FormItem{Text: "Control file", Widget: control, HintText: "JSON Lines file start and stop commands get read from"},
+	)
+	if runtime.GOOS == "darwin" {
+		general.Append("Menu bar", menuBar)
+	}
+	login := container.NewVBox(startAtLogin)
+	if runtime.GOOS == "darwin" || statusNotifier() {
+		login.Add(startHidden)
+	}
+	general.Append("Login", login)
+
+	// invoice template
+	logo := newEntry()
+	logo.SetText(settings.Invoice.Logo)
+	logo.SetPlaceHolder("PNG or JPEG file")
+
+	address := newMultiLineEntry()
+	address.SetText(settings.Invoice.Address)
+	address.SetPlaceHolder("Your name and address")
+
+	terms := newMultiLineEntry()
+	terms.SetText(settings.Invoice.PaymentTerms)
+	terms.SetPlaceHolder("Payment due within 30 days")
+
+	numberFormat := newEntry()
+	numberFormat.SetText(settings.Invoice.NumberFormat)
+	numberFormat.SetPlaceHolder("INV-%04d")
+
+	nextNumber := newEntry()
+	nextNumber.SetText(strconv.Itoa(max(settings.Invoice.NextNumber, 1)))
+
+	invoicing := widget.NewForm(
+		widget.NewFormItem("Logo", browseEntry(w, logo, []string{".png", ".jpg", ".jpeg"})),
+		widget.NewFormItem("Address", address),
+		widget.NewFormItem("Payment terms", terms),
+		widget.NewFormItem("Numbering", numberFormat),
+		widget.NewFormItem("Next number", nextNumber),
+	)
+
+	// closed accounting periods
+	periods := newMultiLineEntry()
+	periods.SetText(formatPeriods(settings.ClosedPeriods))
+	periods.SetPlaceHolder("2026-01-01 to 2026-01-31")
+
+	accounting := widget.NewForm(
+		&widget.FormItem{Text: "Closed periods", Widget: periods, HintText: "One date range per line"},
+	)
+
+	// SAP CATS export
+	personnel := newEntry()
+	personnel.SetText(settings.SAP.PersonnelNumber)
+	personnel.SetPlaceHolder("00001234")
+
+	activity := newEntry()
+	activity.SetText(settings.SAP.ActivityType)
+	activity.SetPlaceHolder("Attendance/absence type")
+
+	sapDate := newEntry()
+	sapDate.SetText(settings.SAP.DateFormat)
+	sapDate.SetPlaceHolder("20060102")
+
+	sapColumns := settings.SAP.Columns
+	if len(sapColumns) == 0 {
+		sapColumns = export.DefaultSAPColumns
+	}
+	columns := newMultiLineEntry()
+	columns.SetText(export.FormatSAPColumns(sapColumns))
+
+	sap := widget.NewForm(
+		widget.NewFormItem("Personnel number", personnel),
+		widget.NewFormItem("Activity type", activity),
+		&widget.FormItem{Text: "Date format", Widget: sapDate, HintText: "Go layout of the work date"},
+		&widget.FormItem{Text: "Columns", Widget: columns, HintText: strings.Join(export.SAPFields, ", ")},
+	)
+
+	// Jira Tempo export
+	account := newEntry()
+	account.SetText(settings.Tempo.AccountID)
+	account.SetPlaceHolder("Atlassian account ID")
+
+	tempo := widget.NewForm(
+		widget.NewFormItem("Account ID", account),
+	)
+
+	// standup summaries
+	standupTemplate := newMultiLineEntry()
+	standupTemplate.SetText(settings.Standup.Template)
+	standupTemplate.SetPlaceHolder(export.DefaultStandupTemplate)
+	standupTemplate.SetMinRowsVisible(6)
+
+	standup := widget.NewForm(
+		&widget.FormItem{Text: "Template", Widget: standupTemplate, HintText: "Go template, empty for the default one"},
+	)
+
+	// alert channels
+	routes := map[string]*widget.CheckGroup{}
+	notifications := widget.NewForm()
+	for _, kind := range alertKinds {
+		channels := widget.NewCheckGroup(notify.Channels, nil)
+		channels.Horizontal = true
+		channels.SetSelected(settings.Notifications.route(kind))
+		routes[kind] = channels
+		notifications.Append(alertTitle(kind), channels)
+	}
+
+	webhook := newEntry()
+	webhook.SetText(settings.Notifications.Webhook.URL)
+	webhook.SetPlaceHolder("https://")
+
+	smtpServer := newEntry()
+	smtpServer.SetText(settings.Notifications.Email.Server)
+	smtpServer.SetPlaceHolder("smtp.example.com:587")
+
+	smtpUser := newEntry()
+	smtpUser.SetText(settings.Notifications.Email.Username)
+
+	mailFrom := newEntry()
+	mailFrom.SetText(settings.Notifications.Email.From)
+
+	mailTo := newEntry()
+	mailTo.SetText(strings.Join(settings.Notifications.Email.To, ", "))
+
+	notifications.Append("Webhook", webhook)
+	notifications.Append("SMTP server", smtpServer)
+	notifications.AppendItem(&widget.FormItem{Text: "SMTP user", Widget: smtpUser, HintText: "Password read from " + notify.PasswordEnv})
+	notifications.Append("Mail from", mailFrom)
+	notifications.AppendItem(&widget.FormItem{Text: "Mail to", Widget: mailTo, HintText: "Comma separated addresses"})
+
+	// sync
+	syncProvider := widget.NewSelect(append([]string{SyncNone}, syncProviders...), nil)
+	syncProvider.SetSelected(SyncNone)
+	if settings.Sync.Provider != "" {
+		syncProvider.SetSelected(settings.Sync.Provider)
+	}
+
+	syncURL := newEntry()
+	syncURL.SetText(settings.Sync.URL)
+	syncURL.SetPlaceHolder("https://cloud.example.com/remote.php/dav/files/me/clocker.yaml")
+
+	syncUser := newEntry()
+	syncUser.SetText(settings.Sync.Username)
+
+	syncDir := newEntry()
+	syncDir.SetText(settings.Sync.Dir)
+	syncDir.SetPlaceHolder("Clone dedicated to clocker")
+
+	syncBranch := newEntry()
+	syncBranch.SetText(settings.Sync.Branch)
+	syncBranch.SetPlaceHolder(syncer.DefaultBranch)
+
+	syncInterval := newEntry()
+	syncInterval.SetText(strconv.Itoa(settings.Sync.IntervalMinutes))
+
+	syncing := widget.NewForm(
+		&widget.FormItem{Text: "Provider", Widget: syncProvider, HintText: "Trackers are also synced on save"},
+		&widget.FormItem{Text: "URL", Widget: syncURL, HintText: "WebDAV file or HTTP API document"},
+		&widget.FormItem{Text: "Username", Widget: syncUser, HintText: "Password read from " + syncer.PasswordEnv + ", API token from " + syncer.TokenEnv},
+		widget.NewFormItem("Git repository", syncDir),
+		widget.NewFormItem("Git branch", syncBranch),
+		&widget.FormItem{Text: "Every", Widget: syncInterval, HintText: "Minutes between syncs, 0 to only sync on save"},
+	)
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("General", general),
+		container.NewTabItem("Invoice", invoicing),
+		container.NewTabItem("Accounting", accounting),
+		container.NewTabItem("SAP", sap),
+		container.NewTabItem("Tempo", tempo),
+		container.NewTabItem("Standup", standup),
+		container.NewTabItem("Notifications", notifications),
+		container.NewTabItem("Sync", syncing),
+	)
+
+	showCustomConfirm("Settings", "Save", "Cancel", tabs, func(b bool) {
+		if !b {
+			return
+		}
+		exchangeRates, err := parseExchangeRates(rates.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		budgetAlerts, err := parseBudgetAlerts(alerts.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		closedPeriods, err := parsePeriods(periods.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		sapMapping, err := export.ParseSAPColumns(columns.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		_, err = export.StandupTemplate(standupTemplate.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		timerPresets, err := parseTimerPresets(presets.Text)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		numbering := invoice.Template{NumberFormat: strings.TrimSpace(numberFormat.Text)}
+		err = numbering.CheckNumberFormat()
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		idleAfter, err := strconv.Atoi(strings.TrimSpace(idleMinutes.Text))
+		if err != nil || idleAfter < 0 {
+			showError(fmt.Errorf("invalid idle delay %q", idleMinutes.Text), w)
+			return
+		}
+		scale, err := parseScale(uiScale.Selected)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		text, err := parseScale(textScale.Selected)
+		if err != nil {
+			showError(err, w)
+			return
+		}
+		syncEvery, err := strconv.Atoi(strings.TrimSpace(syncInterval.Text))
+		if err != nil || syncEvery < 0 {
+			showError(fmt.Errorf("invalid sync interval %q", syncInterval.Text), w)
+			return
+		}
+		next, err := strconv.Atoi(strings.TrimSpace(nextNumber.Text))
+		if err != nil || next < 1 {
+			showError(fmt.Errorf("invalid invoice number %q", nextNumber.Text), w)
+			return
+		}
+		if startAtLogin.Checked != autostart.Enabled() || startHidden.Checked != settings.StartHidden {
+			err = setStartAtLogin(startAtLogin.Checked, startHidden.Checked)
+			if err != nil {
+				showError(err, w)
+				return
+			}
+		}
+		old := settings
+		settings.Currency = strings.ToUpper(strings.TrimSpace(currency.Text))
+		settings.ExchangeRates = exchangeRates
+		settings.BudgetAlerts = budgetAlerts
+		settings.TimerPresets = timerPresets
+		settings.MenuBar = menuBar.Checked
+		settings.StartHidden = startHidden.Checked
+		settings.PowerPrompt = powerPrompt.Checked
+		settings.Storage = backend.Selected
+		settings.EventLog = strings.TrimSpace(eventLog.Text)
+		settings.ControlFile = strings.TrimSpace(control.Text)
+		settings.UIScale = scale
+		settings.TextScale = text
+		settings.HighContrast = contrast.Checked
+		settings.IdleMinutes = idleAfter
+		settings.WeekStart = week.Selected
+		settings.Rollover = rollover.Checked
+		settings.Invoice.Logo = strings.TrimSpace(logo.Text)
+		settings.Invoice.Address = address.Text
+		settings.Invoice.PaymentTerms = terms.Text
+		settings.Invoice.NumberFormat = strings.TrimSpace(numberFormat.Text)
+		settings.Invoice.NextNumber = next
+		setClosedPeriods(closedPeriods)
+		settings.SAP.PersonnelNumber = strings.TrimSpace(personnel.Text)
+		settings.SAP.ActivityType = strings.TrimSpace(activity.Text)
+		settings.SAP.DateFormat = strings.TrimSpace(sapDate.Text)
+		settings.SAP.Columns = sapMapping
+		settings.Tempo.AccountID = strings.TrimSpace(account.Text)
+		settings.Standup.Template = standupTemplate.Text
+		for kind, channels := range routes {
+			settings.Notifications.setRoute(kind, sortedChannels(channels.Selected))
+		}
+		settings.Notifications.Webhook.URL = strings.TrimSpace(webhook.Text)
+		settings.Notifications.Email.Server = strings.TrimSpace(smtpServer.Text)
+		settings.Notifications.Email.Username = strings.TrimSpace(smtpUser.Text)
+		settings.Notifications.Email.From = strings.TrimSpace(mailFrom.Text)
+		settings.Notifications.Email.To = splitList(mailTo.Text)
+		syncSettings := SyncSettings{
+			URL:             strings.TrimSpace(syncURL.Text),
+			Username:        strings.TrimSpace(syncUser.Text),
+			Dir:             strings.TrimSpace(syncDir.Text),
+			Branch:          strings.TrimSpace(syncBranch.Text),
+			IntervalMinutes: syncEvery,
+		}
+		if syncProvider.Selected != SyncNone {
+			syncSettings.Provider = syncProvider.Selected
+		}
+		settings.Sync = syncSettings
+		applySettings(w, old)
+	}, w)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/export"
+)
+
+// standupSummary describes the work tracked on the given day.
+func standupSummary(day time.Time) (string, error) {
+	from := export.Day(day)
+	var out strings.Builder
+	err := export.Standup(&out, exportRequest(from, export.AddDays(from, 1)))
+	return out.String(), err
+}
+
+// standupDialog shows the summary of yesterday, or of the chosen day,
+// for it to be copied to the clipboard.
+func standupDialog(w fyne.Window) {
+	day := newEntry()
+	day.SetText(time.Now().AddDate(0, 0, -1).Format(export.DateFormat))
+	summary := newMultiLineEntry()
+	summary.SetMinRowsVisible(8)
+
+	render := func(text string) {
+		d, err := parseDate(text)
+		if err != nil {
+			return
+		}
+		s, err := standupSummary(d)
+		if err != nil {
+			s = err.Error()
+		}
+		if s == "" {
+			s = "Nothing tracked on " + d.Format(export.DateFormat)
+		}
+		summary.SetText(s)
+	}
+	day.OnChanged = render
+	render(day.Text)
+
+	content := container.NewBorder(widget.NewForm(widget.NewFormItem("Day", day)), nil, nil, nil, summary)
+	showCustomConfirm("Standup summary", "Copy", "Close", content, func(b bool) {
+		if b {
+			w.Clipboard().SetContent(summary.Text)
+		}
+	}, w)
+}
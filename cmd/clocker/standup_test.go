@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStandupSummary(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	err := acme.AddSession(day, day.Add(90*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = acme.SetSessionNote(acme.Sessions[0].ID, " Fixed the login ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := standupSummary(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(s, "• Acme: 1h30m\n    ◦ Fixed the login\n") {
+		t.Errorf("summary misses the session and its note:\n%s", s)
+	}
+	if s, _ := standupSummary(day.AddDate(0, 0, 1)); s != "" {
+		t.Errorf("summary of a day without sessions:\n%s", s)
+	}
+
+	acme.Sessions[0].Locked = true
+	if acme.SetSessionNote(acme.Sessions[0].ID, "Changed") == nil {
+		t.Error("changed the note of an approved session")
+	}
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/engine"
+)
+
+// TopTrackers is the number of trackers ranked in the statistics.
+const TopTrackers = 10
+
+type YearTotal struct {
+	Year    int
+	Elapsed time.Duration
+}
+
+type TrackerTotal struct {
+	Tracker *Tracker
+	Elapsed time.Duration
+	// First is the start of its first session.
+	First time.Time
+}
+
+// Stats sum up all sessions ever tracked, archived trackers included.
+type Stats struct {
+	Elapsed  time.Duration
+	First    time.Time
+	Years    []YearTotal
+	Trackers []TrackerTotal
+}
+
+var (
+	// dataGeneration changes on each save, so that cached statistics get
+	// computed again
+	dataGeneration int
+	statsCache     struct {
+		key   string
+		stats Stats
+	}
+)
+
+// statsKey identifies the closed sessions the statistics are computed
+// from, sessions being mostly appended to between saves.
+func statsKey() string {
+	var key strings.Builder
+	fmt.Fprintf(&key, "%d", dataGeneration)
+	for _, t := range trackers {
+		closed := t.Sessions
+		if len(closed) > 0 && closed[len(closed)-1].Open() {
+			closed = closed[:len(closed)-1]
+		}
+		fmt.Fprintf(&key, ";%s:%d", t.ID, len(closed))
+	}
+	return key.String()
+}
+
+// yearTotals adds the session to the totals of the years it spans.
+func yearTotals(years map[int]time.Duration, start, end time.Time) {
+	for start.Before(end) {
+		next := time.Date(start.Year()+1, 1, 1, 0, 0, 0, 0, start.Location())
+		if end.Before(next) {
+			next = end
+		}
+		years[start.Year()] += next.Sub(start)
+		start = next
+	}
+}
+
+// finish lists the years, most recent first, and ranks the trackers.
+func (s *Stats) finish(years map[int]time.Duration) {
+	for year, elapsed := range years {
+		s.Years = append(s.Years, YearTotal{Year: year, Elapsed: elapsed})
+	}
+	slices.SortFunc(s.Years, func(a, b YearTotal) int {
+		return cmp.Compare(b.Year, a.Year)
+	})
+	slices.SortStableFunc(s.Trackers, func(a, b TrackerTotal) int {
+		return cmp.Compare(b.Elapsed, a.Elapsed)
+	})
+}
+
+// computeStats goes through the closed sessions.
+func computeStats() Stats {
+	stats := Stats{}
+	years := map[int]time.Duration{}
+	for _, t := range trackers {
+		total := TrackerTotal{Tracker: t}
+		for _, s := range t.Sessions {
+			if s.Open() {
+				continue
+			}
+			total.Elapsed += s.Duration()
+			if total.First.IsZero() || s.Start.Before(total.First) {
+				total.First = s.Start
+			}
+			yearTotals(years, s.Start, s.End)
+		}
+		if total.Elapsed == 0 {
+			continue
+		}
+		stats.Elapsed += total.Elapsed
+		if stats.First.IsZero() || total.First.Before(stats.First) {
+			stats.First = total.First
+		}
+		stats.Trackers = append(stats.Trackers, total)
+	}
+	stats.finish(years)
+	return stats
+}
+
+// allTimeStats returns the statistics of the closed sessions, computed
+// again only when they changed, along with the running ones.
+func allTimeStats(now time.Time) Stats {
+	key := statsKey()
+	if statsCache.key != key {
+		statsCache.key, statsCache.stats = key, computeStats()
+	}
+	if !slices.ContainsFunc(trackers, (*Tracker).Running) {
+		return statsCache.stats
+	}
+	// a few running sessions are cheap enough to merge at each call
+	return mergeRunning(statsCache.stats, now)
+}
+
+// mergeRunning adds the running sessions, up to now, to a copy of the
+// cached statistics.
+func mergeRunning(cached Stats, now time.Time) Stats {
+	stats := Stats{
+		Elapsed:  cached.Elapsed,
+		First:    cached.First,
+		Trackers: slices.Clone(cached.Trackers),
+	}
+	years := map[int]time.Duration{}
+	for _, y := range cached.Years {
+		years[y.Year] = y.Elapsed
+	}
+	for _, t := range trackers {
+		if len(t.Sessions) == 0 || !t.Sessions[len(t.Sessions)-1].Open() {
+			continue
+		}
+		s := t.Sessions[len(t.Sessions)-1]
+		elapsed := now.Sub(s.Start)
+		stats.Elapsed += elapsed
+		yearTotals(years, s.Start, now)
+		idx := slices.IndexFunc(stats.Trackers, func(total TrackerTotal) bool { return total.Tracker == t })
+		if idx < 0 {
+			stats.Trackers = append(stats.Trackers, TrackerTotal{Tracker: t, First: s.Start})
+			idx = len(stats.Trackers) - 1
+		}
+		stats.Trackers[idx].Elapsed += elapsed
+		if stats.First.IsZero() || s.Start.Before(stats.First) {
+			stats.First = s.Start
+		}
+	}
+	stats.finish(years)
+	return stats
+}
+
+func statsLine(left, right string) fyne.CanvasObject {
+	label := widget.NewLabel(left)
+	label.Alignment = leadingAlignment()
+	return newRow(nil, nil, widget.NewLabel(right), label)
+}
+
+func statsHeader(text string) fyne.CanvasObject {
+	return widget.NewLabelWithStyle(text, leadingAlignment(), fyne.TextStyle{Bold: true})
+}
+
+// statsDialog shows the overview of all time tracked.
+func statsDialog(w fyne.Window) {
+	stats := allTimeStats(time.Now())
+	rows := container.NewVBox()
+	if stats.Elapsed == 0 {
+		rows.Add(widget.NewLabel("No sessions recorded yet."))
+	} else {
+		rows.Add(statsHeader(fmt.Sprintf("%s tracked since %s", shortDur(stats.Elapsed.Round(time.Minute)), stats.First.Format("02 Jan 2006"))))
+		rows.Add(statsHeader("Per year"))
+		for _, y := range stats.Years {
+			rows.Add(statsLine(fmt.Sprint(y.Year), shortDur(y.Elapsed.Round(time.Minute))))
+		}
+		rows.Add(statsHeader("Top trackers"))
+		for _, total := range stats.Trackers[:min(len(stats.Trackers), TopTrackers)] {
+			label := total.Tracker.Label
+			if total.Tracker.State == engine.Archived {
+				label += " (archived)"
+			}
+			text := fmt.Sprintf("%s  since %s", shortDur(total.Elapsed.Round(time.Minute)), total.First.Format("02 Jan 2006"))
+			rows.Add(statsLine(label, text))
+		}
+	}
+
+	d := dialog.NewCustom("Statistics", "Close", container.NewVScroll(rows), w)
+	d.Resize(fyne.NewSize(420, 480))
+	showDialog(d)
+}
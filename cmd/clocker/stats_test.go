@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/engine"
+)
+
+func TestAllTimeStats(t *testing.T) {
+	newTestWindow(t, "Acme", "Globex", "Initech")
+	acme, globex, initech := trackers[0], trackers[1], trackers[2]
+	newYear := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	acme.Sessions = []Session{
+		{ID: "a", Start: newYear.Add(-time.Hour), End: newYear.Add(2 * time.Hour)},
+		{ID: "b", Start: newYear.AddDate(0, 2, 0), End: newYear.AddDate(0, 2, 0).Add(time.Hour)},
+	}
+	globex.Sessions = []Session{{ID: "c", Start: newYear.AddDate(-1, 5, 0), End: newYear.AddDate(-1, 5, 0).Add(5 * time.Hour)}}
+	globex.State = engine.Archived
+
+	now := newYear.AddDate(0, 3, 0)
+	stats := allTimeStats(now)
+	if stats.Elapsed != 9*time.Hour || !stats.First.Equal(globex.Sessions[0].Start) {
+		t.Fatalf("stats %s since %s, expected 9h since the archived session", stats.Elapsed, stats.First)
+	}
+	if len(stats.Years) != 2 || stats.Years[0] != (YearTotal{2026, 3 * time.Hour}) || stats.Years[1] != (YearTotal{2025, 6 * time.Hour}) {
+		t.Errorf("years %v, expected the new year's eve session split", stats.Years)
+	}
+	if len(stats.Trackers) != 2 || stats.Trackers[0].Tracker != globex || stats.Trackers[1].Elapsed != 4*time.Hour {
+		t.Errorf("trackers %v, expected Globex then Acme", stats.Trackers)
+	}
+
+	// cached until sessions change, running ones being added on top
+	acme.Sessions[0].End = newYear
+	if allTimeStats(now).Elapsed != 9*time.Hour {
+		t.Error("statistics computed again without any change")
+	}
+	initech.Sessions = []Session{{ID: "d", Start: now.Add(-6 * time.Hour)}}
+	initech.State = engine.Running
+	stats = allTimeStats(now)
+	if stats.Elapsed != 15*time.Hour || stats.Trackers[0].Tracker != initech || stats.Trackers[0].Elapsed != 6*time.Hour {
+		t.Errorf("running session not counted: %s, %v", stats.Elapsed, stats.Trackers)
+	}
+	saveConfig()
+	if stats := allTimeStats(now); stats.Elapsed != 13*time.Hour {
+		t.Errorf("stats %s after save, expected the edited session taken into account", stats.Elapsed)
+	}
+}
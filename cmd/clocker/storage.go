@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	StorageYAML   = "yaml"
+	StorageJSON   = "json"
+	StorageSQLite = "sqlite"
+
+	JSONFile = ".clocker.json"
+)
+
+// Storage persists the configuration, trackers and their sessions.
+type Storage interface {
+	Name() string
+	// Load returns the stored configuration, along with the time it was
+	// last saved.
+	Load(ctx context.Context) (Config, time.Time, error)
+	Save(ctx context.Context, config Config) error
+	// AppendSession records a session of the tracker with the given ID,
+	// replacing the one with the same session ID if any.
+	AppendSession(ctx context.Context, tracker string, s Session) error
+	// ListSessions returns the sessions of a tracker overlapping [from, to).
+	ListSessions(ctx context.Context, tracker string, from, to time.Time) ([]Session, error)
+	// Snapshot writes a consistent copy of the stored data as YAML, to
+	// back it up or move it to another backend.
+	Snapshot(ctx context.Context, w io.Writer) error
+	Close() error
+}
+
+// storages open the available backends by name, the YAML one always
+// being there as it holds the configuration file.
+var storages = map[string]func() (Storage, error){
+	StorageYAML: func() (Storage, error) {
+		return newFileStorage(StorageYAML, configFile(), yaml.Marshal), nil
+	},
+	StorageJSON: func() (Storage, error) {
+		return newFileStorage(StorageJSON, homeFile(JSONFile), encodeJSON), nil
+	},
+}
+
+// store is where the configuration currently gets saved.
+var store Storage
+
+func storageNames() []string {
+	names := []string{}
+	for name := range storages {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func storageName() string {
+	if settings.Storage == "" {
+		return StorageYAML
+	}
+	return settings.Storage
+}
+
+func newStorage(name string) (Storage, error) {
+	open, ok := storages[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported storage %q", name)
+	}
+	return open()
+}
+
+// openStorage opens the backend the configuration file points to, unless
+// it holds everything by itself.
+func openStorage(ctx context.Context) (Storage, Config, time.Time, error) {
+	s, _ := newStorage(StorageYAML)
+	config, saved, err := s.Load(ctx)
+	if err != nil || config.Storage == "" || config.Storage == StorageYAML {
+		return s, config, saved, err
+	}
+
+	backend, err := newStorage(config.Storage)
+	if err != nil {
+		return s, config, saved, err
+	}
+	config, saved, err = backend.Load(ctx)
+	return backend, config, saved, err
+}
+
+// switchStorage moves to the backend chosen in the settings, which gets
+// the whole configuration on next save.
+func switchStorage() error {
+	if store != nil && store.Name() == storageName() {
+		return nil
+	}
+	s, err := newStorage(storageName())
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		_ = store.Close()
+	}
+	log.Println("Storing configuration using", s.Name())
+	store = s
+	return nil
+}
+
+// savePointer leaves the name of the backend in the configuration file,
+// so that it gets opened on next start.
+func savePointer(ctx context.Context) error {
+	if store.Name() == StorageYAML {
+		return nil
+	}
+	s, _ := newStorage(StorageYAML)
+	return s.Save(ctx, Config{Settings: Settings{Storage: store.Name()}, Trackers: []*Tracker{}})
+}
+
+// recordSession stores the session of the tracker as it starts or stops,
+// ahead of the next save.
+func recordSession(t *Tracker, s Session) {
+	if store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), StoreTimeout)
+	defer cancel()
+	err := store.AppendSession(ctx, t.ID, s)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func homeFile(name string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, name)
+}
+
+// overlaps tells whether the session is running or ran within [from, to).
+func (s *Session) overlaps(from, to time.Time) bool {
+	return s.Start.Before(to) && (s.Open() || s.End.After(from))
+}
+
+// fileStorage keeps everything in a single file, rewritten on each save.
+type fileStorage struct {
+	name   string
+	file   string
+	encode func(any) ([]byte, error)
+}
+
+func newFileStorage(name, file string, encode func(any) ([]byte, error)) *fileStorage {
+	return &fileStorage{
+		name:   name,
+		file:   file,
+		encode: encode,
+	}
+}
+
+func (s *fileStorage) Name() string {
+	return s.name
+}
+
+func (s *fileStorage) Load(ctx context.Context) (Config, time.Time, error) {
+	// YAML being a superset of JSON, the same decoder reads both
+	return loadConfig(ctx, s.file)
+}
+
+func (s *fileStorage) Save(ctx context.Context, config Config) error {
+	contents, err := s.encode(config)
+	if err != nil {
+		return err
+	}
+	return storeConfig(ctx, s.file, contents)
+}
+
+func (s *fileStorage) AppendSession(ctx context.Context, tracker string, session Session) error {
+	config, _, err := s.Load(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range config.Trackers {
+		if t.ID != tracker {
+			continue
+		}
+		if idx := t.sessionIndex(session.ID); idx >= 0 {
+			t.Sessions[idx] = session
+		} else {
+			t.Sessions = append(t.Sessions, session)
+		}
+		return s.Save(ctx, config)
+	}
+	return fmt.Errorf("no tracker %s in %s", tracker, s.file)
+}
+
+func (s *fileStorage) ListSessions(ctx context.Context, tracker string, from, to time.Time) ([]Session, error) {
+	config, _, err := s.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []Session{}
+	for _, t := range config.Trackers {
+		if t.ID != tracker {
+			continue
+		}
+		for _, session := range t.Sessions {
+			if session.overlaps(from, to) {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+	return sessions, nil
+}
+
+func (s *fileStorage) Snapshot(ctx context.Context, w io.Writer) error {
+	config, _, err := s.Load(ctx)
+	if err != nil {
+		return err
+	}
+	contents, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(contents)
+	return err
+}
+
+func (s *fileStorage) Close() error {
+	return nil
+}
+
+// encodeJSON goes through YAML first, so that JSON files get the same
+// field names and omitted fields as the YAML ones.
+func encodeJSON(v any) ([]byte, error) {
+	contents, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	err = yaml.Unmarshal(contents, &doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
@@ -0,0 +1,326 @@
+//go:build !js
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	_ "modernc.org/sqlite" // pure Go driver, no cgo toolchain needed
+)
+
+const (
+	SQLiteFile = ".clocker.db"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS config (
+	id       INTEGER PRIMARY KEY CHECK (id = 1),
+	document TEXT NOT NULL,
+	saved    INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS sessions (
+	id          TEXT PRIMARY KEY,
+	tracker     TEXT NOT NULL,
+	position    INTEGER NOT NULL,
+	start_at    INTEGER NOT NULL,
+	end_at      INTEGER,
+	locked      INTEGER NOT NULL DEFAULT 0,
+	invoice     TEXT NOT NULL DEFAULT '',
+	interrupted INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS sessions_tracker ON sessions (tracker, start_at);
+`
+
+// sqliteMigrations upgrade databases created by previous versions, the
+// user_version pragma telling how many got applied.
+var sqliteMigrations = []string{
+	`ALTER TABLE sessions ADD COLUMN note TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE sessions ADD COLUMN modified INTEGER`,
+}
+
+func init() {
+	storages[StorageSQLite] = func() (Storage, error) {
+		return openSQLite(homeFile(SQLiteFile))
+	}
+}
+
+// sqliteStorage keeps the sessions in their own table, so that recording
+// one doesn't rewrite the whole history: saves only write the sessions
+// which changed. Settings and trackers are small enough to stay a single
+// YAML document.
+type sqliteStorage struct {
+	file string
+	db   *sql.DB
+}
+
+func openSQLite(file string) (*sqliteStorage, error) {
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		return nil, err
+	}
+	// a single connection serializes writers instead of failing on a
+	// locked database
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(sqliteSchema)
+	if err == nil {
+		err = migrateSQLite(db)
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteStorage{file: file, db: db}, nil
+}
+
+func migrateSQLite(db *sql.DB) error {
+	var version int
+	err := db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return err
+	}
+	for ; version < len(sqliteMigrations); version++ {
+		_, err = db.Exec(sqliteMigrations[version])
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqliteStorage) Name() string {
+	return StorageSQLite
+}
+
+// querier is either the database or a transaction.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (s *sqliteStorage) Load(ctx context.Context) (Config, time.Time, error) {
+	return load(ctx, s.db)
+}
+
+func load(ctx context.Context, q querier) (Config, time.Time, error) {
+	var config Config
+	var document string
+	var saved int64
+	err := q.QueryRowContext(ctx, "SELECT document, saved FROM config WHERE id = 1").Scan(&document, &saved)
+	if err == sql.ErrNoRows {
+		// freshly created, the next save fills it
+		return Config{Settings: Settings{Storage: StorageSQLite}}, time.Now(), nil
+	}
+	if err != nil {
+		return config, time.Time{}, err
+	}
+	config, err = decodeConfig([]byte(document))
+	if err != nil {
+		return config, time.Time{}, err
+	}
+
+	for _, t := range config.Trackers {
+		t.Sessions, err = query(ctx, q, "SELECT "+sessionColumns+" FROM sessions WHERE tracker = ? ORDER BY position", t.ID)
+		if err != nil {
+			return config, time.Time{}, err
+		}
+	}
+	return config, time.Unix(0, saved), nil
+}
+
+func (s *sqliteStorage) Save(ctx context.Context, config Config) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// sessions go in their table, not in the document
+	doc := config
+	doc.Trackers = make([]*Tracker, len(config.Trackers))
+	for idx, t := range config.Trackers {
+		c := *t
+		c.Sessions = nil
+		doc.Trackers[idx] = &c
+	}
+	document, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT OR REPLACE INTO config (id, document, saved) VALUES (1, ?, ?)",
+		string(document), time.Now().UnixNano())
+	if err != nil {
+		return err
+	}
+
+	stored, err := storedRows(ctx, tx)
+	if err != nil {
+		return err
+	}
+	upsert, err := tx.PrepareContext(ctx, `INSERT INTO sessions (id, tracker, position, start_at, end_at, locked, invoice, interrupted, note, modified)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET tracker = excluded.tracker, position = excluded.position, start_at = excluded.start_at,
+			end_at = excluded.end_at, locked = excluded.locked, invoice = excluded.invoice, interrupted = excluded.interrupted,
+			note = excluded.note, modified = excluded.modified`)
+	if err != nil {
+		return err
+	}
+	defer upsert.Close()
+	for _, t := range config.Trackers {
+		for idx, session := range t.Sessions {
+			args := sessionArgs(t.ID, idx, session)
+			row, found := stored[session.ID]
+			delete(stored, session.ID)
+			if found && slices.Equal(row, args) {
+				continue
+			}
+			_, err = upsert.ExecContext(ctx, args...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// whatever is left got deleted
+	for id := range stored {
+		_, err = tx.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// storedRows returns the stored sessions by ID, as sessionArgs of them.
+func storedRows(ctx context.Context, q querier) (map[string][]any, error) {
+	rows, err := q.QueryContext(ctx, "SELECT id, tracker, position, start_at, end_at, locked, invoice, interrupted, note, modified FROM sessions")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stored := map[string][]any{}
+	for rows.Next() {
+		var id, tracker, invoice, note string
+		var position int
+		var start int64
+		var end, modified sql.NullInt64
+		var locked, interrupted bool
+		err = rows.Scan(&id, &tracker, &position, &start, &end, &locked, &invoice, &interrupted, &note, &modified)
+		if err != nil {
+			return nil, err
+		}
+		stored[id] = []any{id, tracker, position, start, end, locked, invoice, interrupted, note, modified}
+	}
+	return stored, rows.Err()
+}
+
+func (s *sqliteStorage) AppendSession(ctx context.Context, tracker string, session Session) error {
+	// trackers live in the document, without their sessions
+	var document string
+	err := s.db.QueryRowContext(ctx, "SELECT document FROM config WHERE id = 1").Scan(&document)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	config, err := decodeConfig([]byte(document))
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(config.Trackers, func(t *Tracker) bool { return t.ID == tracker }) {
+		return fmt.Errorf("no tracker %s in %s", tracker, s.file)
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO sessions (id, tracker, position, start_at, end_at, locked, invoice, interrupted, note, modified)
+		VALUES (?, ?, (SELECT COALESCE(MAX(position) + 1, ?) FROM sessions WHERE tracker = ?), ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET start_at = excluded.start_at, end_at = excluded.end_at, locked = excluded.locked,
+			invoice = excluded.invoice, interrupted = excluded.interrupted, note = excluded.note, modified = excluded.modified`,
+		session.ID, tracker, 0, tracker, session.Start.UnixNano(), nullTime(session.End),
+		session.Locked, session.Invoice, session.Interrupted, session.Note, nullTime(session.Modified))
+	return err
+}
+
+func (s *sqliteStorage) ListSessions(ctx context.Context, tracker string, from, to time.Time) ([]Session, error) {
+	return query(ctx, s.db, "SELECT "+sessionColumns+" FROM sessions WHERE tracker = ? AND start_at < ? AND (end_at IS NULL OR end_at > ?) ORDER BY start_at",
+		tracker, to.UnixNano(), from.UnixNano())
+}
+
+func (s *sqliteStorage) Snapshot(ctx context.Context, w io.Writer) error {
+	// a read transaction sees a single state of both tables
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	config, _, err := load(ctx, tx)
+	if err != nil {
+		return err
+	}
+	contents, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(contents)
+	return err
+}
+
+func (s *sqliteStorage) Close() error {
+	return s.db.Close()
+}
+
+const sessionColumns = "id, start_at, end_at, locked, invoice, interrupted, note, modified"
+
+func query(ctx context.Context, q querier, query string, args ...any) ([]Session, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []Session{}
+	for rows.Next() {
+		var session Session
+		var start int64
+		var end, modified sql.NullInt64
+		err = rows.Scan(&session.ID, &start, &end, &session.Locked, &session.Invoice, &session.Interrupted, &session.Note, &modified)
+		if err != nil {
+			return nil, err
+		}
+		session.Start = time.Unix(0, start)
+		if end.Valid {
+			session.End = time.Unix(0, end.Int64)
+		}
+		if modified.Valid {
+			session.Modified = time.Unix(0, modified.Int64)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func sessionArgs(tracker string, position int, s Session) []any {
+	return []any{s.ID, tracker, position, s.Start.UnixNano(), nullTime(s.End), s.Locked, s.Invoice, s.Interrupted, s.Note, nullTime(s.Modified)}
+}
+
+func nullTime(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
@@ -0,0 +1,87 @@
+//go:build !js
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSQLiteMigrations(t *testing.T) {
+	file := filepath.Join(t.TempDir(), SQLiteFile)
+	// sessions as stored before notes
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE sessions (id TEXT PRIMARY KEY, tracker TEXT NOT NULL, position INTEGER NOT NULL,
+		start_at INTEGER NOT NULL, end_at INTEGER, locked INTEGER NOT NULL DEFAULT 0, invoice TEXT NOT NULL DEFAULT '',
+		interrupted INTEGER NOT NULL DEFAULT 0);
+		INSERT INTO sessions (id, tracker, position, start_at) VALUES ('session-a', 'tracker-1', 0, 0)`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		s, err := openSQLite(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions, err := query(context.Background(), s.db, "SELECT "+sessionColumns+" FROM sessions")
+		s.Close()
+		if err != nil || len(sessions) != 1 || sessions[0].ID != "session-a" {
+			t.Fatalf("sessions %+v, %v after migration", sessions, err)
+		}
+	}
+}
+
+func TestSQLiteSaveChanges(t *testing.T) {
+	ctx := context.Background()
+	s, err := openSQLite(filepath.Join(t.TempDir(), SQLiteFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	changes := func() int {
+		var n int
+		_ = s.db.QueryRow("SELECT total_changes()").Scan(&n)
+		return n
+	}
+
+	config := testConfig()
+	err = s.Save(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the document, one edited session and one deleted
+	before := changes()
+	acme := config.Trackers[0]
+	acme.Sessions[1].Note = "Fixed the signup"
+	acme.Sessions = slices.Delete(acme.Sessions, 2, 3)
+	err = s.Save(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := changes() - before; n != 3 {
+		t.Errorf("save changed %d rows, expected 3", n)
+	}
+
+	loaded, _, err := s.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := loaded.Trackers[0].Sessions
+	if len(sessions) != 2 || sessions[1].Note != "Fixed the signup" {
+		t.Errorf("sessions %+v after save", sessions)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func testConfig() Config {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	t := &Tracker{ID: "tracker-1", Label: "Acme", Rate: 80, Since: start}
+	for i := 0; i < 3; i++ {
+		from := start.AddDate(0, 0, i)
+		t.Sessions = append(t.Sessions, Session{ID: "session-" + string(rune('a'+i)), Start: from, End: from.Add(time.Hour)})
+	}
+	t.Sessions[0].Locked = true
+	t.Sessions[0].Invoice = "INV-0001"
+	t.Sessions[1].Note = "Fixed the login"
+	return Config{Settings: Settings{Currency: "EUR"}, Trackers: []*Tracker{t}}
+}
+
+func TestStorages(t *testing.T) {
+	for _, name := range storageNames() {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			ctx := context.Background()
+			s, err := newStorage(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			config := testConfig()
+			err = s.Save(ctx, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			loaded, saved, err := s.Load(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if time.Since(saved) > time.Minute {
+				t.Errorf("saved at %s", saved)
+			}
+			if loaded.Currency != "EUR" || len(loaded.Trackers) != 1 {
+				t.Fatalf("loaded %+v", loaded)
+			}
+			tr := loaded.Trackers[0]
+			if tr.ID != "tracker-1" || tr.Label != "Acme" || tr.Rate != 80 || len(tr.Sessions) != 3 {
+				t.Fatalf("loaded tracker %+v", tr)
+			}
+			first := tr.Sessions[0]
+			if !first.Start.Equal(config.Trackers[0].Sessions[0].Start) || !first.Locked || first.Invoice != "INV-0001" {
+				t.Errorf("loaded session %+v", first)
+			}
+
+			// a new session, then an update of the same one
+			start := time.Date(2026, 3, 10, 14, 0, 0, 0, time.Local)
+			err = s.AppendSession(ctx, "tracker-1", Session{ID: "session-z", Start: start})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s.AppendSession(ctx, "tracker-1", Session{ID: "session-z", Start: start, End: start.Add(30 * time.Minute)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s.AppendSession(ctx, "tracker-2", Session{ID: "session-y", Start: start})
+			if err == nil {
+				t.Error("appended a session to a missing tracker")
+			}
+			loaded, _, _ = s.Load(ctx)
+			sessions := loaded.Trackers[0].Sessions
+			if len(sessions) != 4 || sessions[3].ID != "session-z" || sessions[3].Duration() != 30*time.Minute {
+				t.Errorf("sessions after append %+v", sessions)
+			}
+
+			// the range catches sessions overlapping its bounds
+			from := time.Date(2026, 3, 3, 9, 30, 0, 0, time.Local)
+			to := time.Date(2026, 3, 10, 14, 10, 0, 0, time.Local)
+			listed, err := s.ListSessions(ctx, "tracker-1", from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, session := range listed {
+				ids = append(ids, session.ID)
+			}
+			if len(ids) != 3 || ids[0] != "session-b" || ids[1] != "session-c" || ids[2] != "session-z" {
+				t.Errorf("listed %v, expected session-b, session-c, session-z", ids)
+			}
+
+			var snapshot bytes.Buffer
+			err = s.Snapshot(ctx, &snapshot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			restored, err := decodeConfig(snapshot.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(restored.Trackers) != 1 || len(restored.Trackers[0].Sessions) != 4 {
+				t.Errorf("snapshot holds %+v", restored)
+			}
+		})
+	}
+}
+
+func TestSwitchStorage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store = nil
+	trackers = []*Tracker{}
+	settings = Settings{Storage: StorageJSON}
+	NewTracker("Acme", time.Hour)
+	saveConfig()
+
+	trackers = []*Tracker{}
+	settings = Settings{}
+	readConfig()
+	if store.Name() != StorageJSON {
+		t.Fatalf("opened %s, expected the storage saved in the configuration file", store.Name())
+	}
+	if len(trackers) != 1 || trackers[0].Label != "Acme" || trackers[0].Elapsed != time.Hour {
+		t.Fatalf("trackers not read back from %s", StorageJSON)
+	}
+
+	// going back moves everything to the configuration file
+	settings.Storage = StorageYAML
+	saveConfig()
+	trackers = []*Tracker{}
+	settings = Settings{}
+	readConfig()
+	if store.Name() != StorageYAML || len(trackers) != 1 {
+		t.Errorf("opened %s with %d trackers, expected yaml with 1", store.Name(), len(trackers))
+	}
+}
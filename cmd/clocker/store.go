@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// StoreTimeout bounds loading and saving the configuration, so that a
+	// stale network home directory can't hang startup or shutdown.
+	StoreTimeout = 10 * time.Second
+
+	// writeChunk is how much gets written at once, an abandoned write
+	// stopping in between.
+	writeChunk = 64 << 10
+)
+
+var (
+	// appCtx is cancelled on shutdown, stopping the background work.
+	appCtx, cancelApp = context.WithCancel(context.Background())
+)
+
+func configFile() string {
+	return homeFile(ConfigFile)
+}
+
+// loadConfig reads and decodes the configuration file, along with the time
+// it was last saved.
+func loadConfig(ctx context.Context, file string) (Config, time.Time, error) {
+	contents, err := readFile(ctx, file)
+	if err != nil {
+		return Config{}, time.Time{}, err
+	}
+	saved := time.Now()
+	if info, err := os.Stat(file); err == nil {
+		saved = info.ModTime()
+	}
+	config, err := decodeConfig(contents)
+	if err != nil {
+		return Config{}, saved, err
+	}
+	return config, saved, ctx.Err()
+}
+
+// storeConfig writes the configuration next to the file first and swaps
+// it in place, so that a cancelled or failed save never leaves a truncated
+// file behind.
+func storeConfig(ctx context.Context, file string, contents []byte) error {
+	tmp := file + ".tmp"
+	err := writeFile(ctx, tmp, contents)
+	if err != nil {
+		return err
+	}
+	if err = ctx.Err(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+type result struct {
+	contents []byte
+	err      error
+}
+
+// readFile gives up waiting on the file system once ctx is done.
+func readFile(ctx context.Context, file string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	done := make(chan result, 1)
+	go func() {
+		contents, err := os.ReadFile(filepath.Clean(file))
+		done <- result{contents, err}
+	}()
+	select {
+	case r := <-done:
+		return r.contents, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// writeFile gives up waiting on the file system once ctx is done. The
+// writer then stops at the next chunk and removes the file once closed, so
+// that a failed or abandoned write leaves nothing behind.
+func writeFile(ctx context.Context, file string, contents []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// unbuffered, so that the writer knows whether its result got through
+	done := make(chan error)
+	go func() {
+		err := writeChunks(ctx, file, contents)
+		if err != nil {
+			_ = os.Remove(file)
+		}
+		select {
+		case done <- err:
+		case <-ctx.Done():
+			if err == nil {
+				_ = os.Remove(file)
+			}
+		}
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// writeChunks writes the file until done or ctx is, closing it either way.
+func writeChunks(ctx context.Context, file string, contents []byte) error {
+	f, err := os.OpenFile(filepath.Clean(file), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	for len(contents) > 0 && err == nil {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		n := min(len(contents), writeChunk)
+		_, err = f.Write(contents[:n])
+		contents = contents[n:]
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,447 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+	"gopkg.in/yaml.v3"
+
+	"github.com/gxben/clocker/pkg/engine"
+	"github.com/gxben/clocker/pkg/syncer"
+)
+
+const (
+	SyncNone   = "None"
+	SyncGit    = "git"
+	SyncWebDAV = "webdav"
+	SyncHTTP   = "http"
+)
+
+var syncProviders = []string{
+	SyncGit,
+	SyncWebDAV,
+	SyncHTTP,
+}
+
+type SyncSettings struct {
+	Provider string `yaml:"provider,omitempty"`
+	// URL of the WebDAV file or HTTP API document.
+	URL      string `yaml:"url,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	// Dir is the git clone dedicated to clocker.
+	Dir             string `yaml:"dir,omitempty"`
+	Branch          string `yaml:"branch,omitempty"`
+	IntervalMinutes int    `yaml:"interval_minutes,omitempty"`
+}
+
+var (
+	syncScheduler *syncer.Scheduler
+	cancelSync    context.CancelFunc
+	syncStatus    = binding.NewString()
+)
+
+func newSyncProvider(s SyncSettings) (syncer.Provider, error) {
+	switch s.Provider {
+	case SyncGit:
+		return syncer.NewGit(syncer.GitOptions{Dir: s.Dir, Branch: s.Branch}, mergeDocuments), nil
+	case SyncWebDAV:
+		return syncer.NewWebDAV(s.URL, s.Username, mergeDocuments), nil
+	case SyncHTTP:
+		return syncer.NewHTTP(s.URL, mergeDocuments), nil
+	}
+	return nil, fmt.Errorf("unsupported sync provider %q", s.Provider)
+}
+
+// startSync (re)starts syncing trackers with the configured provider, if
+// any.
+func startSync(w fyne.Window) {
+	if cancelSync != nil {
+		cancelSync()
+		cancelSync = nil
+	}
+	syncScheduler = nil
+	if settings.Sync.Provider == "" {
+		return
+	}
+
+	p, err := newSyncProvider(settings.Sync)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Println("Syncing trackers using", p.Name())
+	interval := time.Duration(settings.Sync.IntervalMinutes) * time.Minute
+	s := syncer.NewScheduler(p, interval, syncDocument, func(data []byte) error {
+		return applySync(w, data)
+	})
+	s.OnStatus = func(status syncer.Status) {
+		_ = syncStatus.Set(status.String())
+		if status.Err != nil {
+			log.Println(status.Err)
+		}
+	}
+	_ = syncStatus.Set(s.Status().String())
+
+	ctx, cancel := context.WithCancel(appCtx)
+	syncScheduler, cancelSync = s, cancel
+	go s.Run(ctx)
+}
+
+// triggerSync has the changes synced shortly, e.g. after a save.
+func triggerSync() {
+	if syncScheduler != nil {
+		syncScheduler.Trigger()
+	}
+}
+
+func syncNow() {
+	s := syncScheduler
+	if s == nil {
+		return
+	}
+	go func() {
+		_ = s.Sync(appCtx)
+	}()
+}
+
+func makeSyncStatus() fyne.CanvasObject {
+	if syncScheduler == nil {
+		return nil
+	}
+	label := widget.NewLabelWithData(syncStatus)
+	label.Truncation = fyne.TextTruncateEllipsis
+	label.Alignment = leadingAlignment()
+	return label
+}
+
+// syncDocument holds the trackers only, settings being specific to each
+// computer.
+func syncDocument() ([]byte, error) {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	return yaml.Marshal(Config{Trackers: trackers, Deleted: deletedTrackers})
+}
+
+// Tombstone records the deletion of a tracker or session, which wins over
+// the copies synced from elsewhere not modified since.
+type Tombstone struct {
+	ID string    `yaml:"id"`
+	At time.Time `yaml:"at"`
+}
+
+// deletedTrackers are the tombstones of the trackers.
+var deletedTrackers []Tombstone
+
+// mergeTombstones merges two lists, keeping the latest deletion of each ID,
+// sorted by ID so that merges are stable.
+func mergeTombstones(a, b []Tombstone) []Tombstone {
+	latest := map[string]time.Time{}
+	for _, d := range slices.Concat(a, b) {
+		if at, ok := latest[d.ID]; !ok || d.At.After(at) {
+			latest[d.ID] = d.At
+		}
+	}
+	if len(latest) == 0 {
+		return nil
+	}
+	merged := []Tombstone{}
+	for id, at := range latest {
+		merged = append(merged, Tombstone{ID: id, At: at})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ID < merged[j].ID
+	})
+	return merged
+}
+
+// deletedSince tells whether id got deleted after it was last modified.
+func deletedSince(deleted []Tombstone, id string, modified time.Time) bool {
+	for _, d := range deleted {
+		if d.ID == id {
+			return !d.At.Before(modified)
+		}
+	}
+	return false
+}
+
+// syncedFields are the tracker fields synced along with its sessions.
+type syncedFields struct {
+	Label    string
+	Rate     float64
+	Rates    []RateChange
+	Currency string
+	Receiver string
+	Issue    string
+	Expenses []Expense
+	Budget   float64
+	Alarm    *Alarm
+	Goal     time.Duration
+	Archived bool
+}
+
+func (t *Tracker) syncedFields() syncedFields {
+	return syncedFields{
+		Label:    t.Label,
+		Rate:     t.Rate,
+		Rates:    t.Rates,
+		Currency: t.Currency,
+		Receiver: t.Receiver,
+		Issue:    t.Issue,
+		Expenses: t.Expenses,
+		Budget:   t.Budget,
+		Alarm:    t.Alarm,
+		Goal:     t.Goal,
+		Archived: t.State == engine.Archived,
+	}
+}
+
+// applyFields takes the synced fields of the other copy.
+func (t *Tracker) applyFields(other *Tracker) {
+	f := other.syncedFields()
+	t.Label, t.Rate, t.Rates, t.Currency, t.Receiver, t.Issue = f.Label, f.Rate, f.Rates, f.Currency, f.Receiver, f.Issue
+	t.Expenses, t.Budget, t.Alarm, t.Goal = f.Expenses, f.Budget, f.Alarm, f.Goal
+	t.Modified = other.Modified
+	// a tracker running here stays so
+	switch {
+	case f.Archived && t.State != engine.Archived:
+		_ = t.Fire(engine.Archive)
+	case !f.Archived && t.State == engine.Archived:
+		_ = t.Fire(engine.Restore)
+	}
+}
+
+// lastModified is when the tracker or any of its sessions last changed.
+func (t *Tracker) lastModified() time.Time {
+	last := t.Modified
+	for _, s := range t.Sessions {
+		last = later(last, s.Modified)
+	}
+	return last
+}
+
+// savedTracker is a tracker as last saved or synced.
+type savedTracker struct {
+	// fields as YAML, a deep copy being needed to tell changes
+	fields   string
+	sessions map[string]Session
+}
+
+// saved holds the trackers as last saved or synced by ID, telling what got
+// modified or deleted since.
+var saved map[string]savedTracker
+
+func (t *Tracker) saved() savedTracker {
+	fields, _ := yaml.Marshal(t.syncedFields())
+	st := savedTracker{fields: string(fields), sessions: map[string]Session{}}
+	for _, s := range t.Sessions {
+		st.sessions[s.ID] = s
+	}
+	return st
+}
+
+// rememberTrackers records the trackers as saved.
+func rememberTrackers() {
+	saved = map[string]savedTracker{}
+	for _, t := range trackers {
+		saved[t.ID] = t.saved()
+	}
+}
+
+// sameSession compares sessions but for their modification time.
+func sameSession(a, b Session) bool {
+	if !a.Start.Equal(b.Start) || !a.End.Equal(b.End) {
+		return false
+	}
+	a.Start, a.End, a.Modified = time.Time{}, time.Time{}, time.Time{}
+	b.Start, b.End, b.Modified = time.Time{}, time.Time{}, time.Time{}
+	return a == b
+}
+
+// stampChanges dates the trackers and sessions changed since last saved,
+// and leaves tombstones for the deleted ones, for syncs to merge them.
+func stampChanges(now time.Time) {
+	if saved == nil {
+		rememberTrackers()
+		return
+	}
+	for _, t := range trackers {
+		st, ok := saved[t.ID]
+		current := t.saved()
+		if !ok || current.fields != st.fields {
+			t.Modified = now
+		}
+		for i := range t.Sessions {
+			s := &t.Sessions[i]
+			if prev, ok := st.sessions[s.ID]; !ok || !sameSession(prev, *s) {
+				s.Modified = now
+			}
+		}
+		for id := range st.sessions {
+			if _, ok := current.sessions[id]; !ok {
+				t.Deleted = mergeTombstones(t.Deleted, []Tombstone{{ID: id, At: now}})
+			}
+		}
+	}
+	for id := range saved {
+		if findTracker(id) == nil {
+			deletedTrackers = mergeTombstones(deletedTrackers, []Tombstone{{ID: id, At: now}})
+		}
+	}
+	rememberTrackers()
+}
+
+// mergeDocuments merges trackers by ID, the last modified fields and
+// sessions winning, deletions included.
+func mergeDocuments(local, remote []byte) ([]byte, error) {
+	l, err := decodeConfig(local)
+	if err != nil {
+		return nil, err
+	}
+	r, err := decodeConfig(remote)
+	if err != nil {
+		return nil, err
+	}
+	deleted := mergeTombstones(l.Deleted, r.Deleted)
+	for _, rt := range r.Trackers {
+		lt := findIn(l.Trackers, rt.ID)
+		if lt == nil {
+			l.Trackers = append(l.Trackers, rt.withoutOpenSessions())
+			continue
+		}
+		lt.mergeFrom(rt)
+	}
+	l.Trackers = slices.DeleteFunc(l.Trackers, func(t *Tracker) bool {
+		return deletedSince(deleted, t.ID, t.lastModified())
+	})
+	return yaml.Marshal(Config{Trackers: l.Trackers, Deleted: deleted})
+}
+
+func findIn(list []*Tracker, id string) *Tracker {
+	for _, t := range list {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// mergeFrom merges the copy of the tracker synced from elsewhere, the last
+// modified fields and sessions winning, the ones still running elsewhere
+// waiting for the next sync once ended. Tells whether anything changed.
+func (t *Tracker) mergeFrom(other *Tracker) bool {
+	changed := false
+	if other.Modified.After(t.Modified) {
+		t.applyFields(other)
+		changed = true
+	}
+	t.Deleted = mergeTombstones(t.Deleted, other.Deleted)
+
+	for _, s := range other.Sessions {
+		if s.Open() {
+			continue
+		}
+		idx := t.sessionIndex(s.ID)
+		switch {
+		case idx < 0:
+			t.Sessions = append(t.Sessions, s)
+		case s.Modified.After(t.Sessions[idx].Modified) && !t.Sessions[idx].Open():
+			t.uncount(&t.Sessions[idx], t.Sessions[idx].Duration())
+			t.Sessions[idx] = s
+		default:
+			continue
+		}
+		if t.inCounter(&s) {
+			t.Elapsed += s.Duration()
+		}
+		changed = true
+	}
+
+	// deleted on either side since last modified
+	t.Sessions = slices.DeleteFunc(t.Sessions, func(s Session) bool {
+		if s.Open() || !deletedSince(t.Deleted, s.ID, s.Modified) {
+			return false
+		}
+		t.uncount(&s, s.Duration())
+		changed = true
+		return true
+	})
+	if changed {
+		t.sortSessions()
+	}
+	return changed
+}
+
+func (t *Tracker) withoutOpenSessions() *Tracker {
+	sessions := []Session{}
+	for _, s := range t.Sessions {
+		if !s.Open() {
+			sessions = append(sessions, s)
+		}
+	}
+	t.Sessions = sessions
+	// running elsewhere doesn't make it run here
+	t.Recover()
+	return t
+}
+
+// applySync merges the synced document into the trackers, remembering
+// the merged ones as saved: they aren't changes to sync back.
+func applySync(w fyne.Window, data []byte) error {
+	config, err := decodeConfig(data)
+	if err != nil {
+		return err
+	}
+	if mergeSync(config) && w != nil {
+		update(w)
+	}
+	return nil
+}
+
+// mergeSync merges the synced trackers, telling whether any changed.
+func mergeSync(config Config) bool {
+	stateLock.Lock()
+	defer stateLock.Unlock()
+	changed := false
+	for _, rt := range config.Trackers {
+		t := findTracker(rt.ID)
+		switch {
+		case t == nil && deletedSince(deletedTrackers, rt.ID, rt.lastModified()):
+			continue
+		case t == nil:
+			t = rt.withoutOpenSessions()
+			addTracker(t)
+		case t.mergeFrom(rt):
+			_ = t.LabelStr.Set(t.Label)
+			t.refreshElapsed()
+		default:
+			continue
+		}
+		if saved != nil {
+			saved[t.ID] = t.saved()
+		}
+		changed = true
+	}
+
+	deletedTrackers = mergeTombstones(deletedTrackers, config.Deleted)
+	for _, t := range slices.Clone(trackers) {
+		if deletedSince(deletedTrackers, t.ID, t.lastModified()) {
+			DeleteTracker(t.ID)
+			delete(saved, t.ID)
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gxben/clocker/pkg/engine"
+)
+
+func syncTrackers(t *testing.T, list ...*Tracker) []byte {
+	t.Helper()
+	data, err := yaml.Marshal(Config{Trackers: list})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestMergeDocuments(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	shared := Session{ID: "session-a", Start: start, End: start.Add(time.Hour)}
+	local := &Tracker{ID: "tracker-1", Label: "Acme", Elapsed: time.Hour, Sessions: []Session{shared}}
+	remote := &Tracker{ID: "tracker-1", Label: "Acme renamed elsewhere", Elapsed: 3 * time.Hour, Sessions: []Session{
+		// added before the shared one, on another computer
+		{ID: "session-b", Start: start.Add(-2 * time.Hour), End: start.Add(-time.Hour)},
+		shared,
+		{ID: "session-c", Start: start.Add(2 * time.Hour)},
+	}}
+	other := &Tracker{ID: "tracker-2", Label: "Globex", Sessions: []Session{{ID: "session-d", Start: start}}}
+	other.State = engine.Running
+
+	merged, err := mergeDocuments(syncTrackers(t, local), syncTrackers(t, remote, other))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := decodeConfig(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Trackers) != 2 {
+		t.Fatalf("merged %d trackers, expected 2", len(config.Trackers))
+	}
+	acme := config.Trackers[0]
+	if acme.Label != "Acme" {
+		t.Errorf("label = %q, expected the local one", acme.Label)
+	}
+	if len(acme.Sessions) != 2 || acme.Sessions[0].ID != "session-b" || acme.Sessions[1].ID != "session-a" {
+		t.Errorf("sessions = %+v, expected the remote closed one merged first", acme.Sessions)
+	}
+	if acme.Elapsed != 2*time.Hour {
+		t.Errorf("elapsed = %s, expected the merged session counted", acme.Elapsed)
+	}
+	globex := config.Trackers[1]
+	if globex.ID != "tracker-2" || len(globex.Sessions) != 0 || globex.Running() {
+		t.Errorf("new tracker %+v, expected added without its running session", globex)
+	}
+
+	// merging is idempotent, so resyncing converges
+	again, err := mergeDocuments(merged, syncTrackers(t, remote, other))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(merged) {
+		t.Errorf("merging again changed the document:\n%s\n%s", merged, again)
+	}
+}
+
+func TestApplySync(t *testing.T) {
+	newTestWindow(t, "Acme")
+	acme := trackers[0]
+	start := time.Now().Add(-time.Hour)
+	remote := &Tracker{ID: acme.ID, Label: "Acme", Sessions: []Session{{ID: "remote", Start: start, End: start.Add(30 * time.Minute)}}}
+	added := &Tracker{ID: "tracker-2", Label: "Globex"}
+
+	err := applySync(nil, syncTrackers(t, remote, added))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(acme.Sessions) != 1 || acme.Elapsed != 30*time.Minute {
+		t.Errorf("sessions = %+v, elapsed = %s", acme.Sessions, acme.Elapsed)
+	}
+	if s, _ := acme.ElapsedStr.Get(); s == shortDur(0) {
+		t.Error("counter not refreshed")
+	}
+	if len(trackers) != 2 || findTracker("tracker-2") == nil {
+		t.Fatalf("trackers = %d, expected Globex added", len(trackers))
+	}
+
+	local, err := syncDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := mergeDocuments(local, syncTrackers(t, remote, added))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(merged) != string(local) {
+		t.Errorf("applied document still differs from the merged one:\n%s\n%s", local, merged)
+	}
+}
+
+func TestSyncChanges(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	synced, edited := start.Add(24*time.Hour), start.Add(48*time.Hour)
+	tests := []struct {
+		name  string
+		edit  func(acme *Tracker)
+		check func(list []*Tracker) bool
+	}{
+		{"bounds", func(acme *Tracker) { acme.Sessions[0].End = start.Add(90 * time.Minute) }, func(list []*Tracker) bool {
+			return list[0].Sessions[0].End.Equal(start.Add(90 * time.Minute))
+		}},
+		{"note", func(acme *Tracker) { acme.Sessions[0].Note = "review" }, func(list []*Tracker) bool {
+			return list[0].Sessions[0].Note == "review"
+		}},
+		{"lock", func(acme *Tracker) { acme.Sessions[0].Locked = true }, func(list []*Tracker) bool {
+			return list[0].Sessions[0].Locked
+		}},
+		{"rename", func(acme *Tracker) { acme.Label = "Acme Corp" }, func(list []*Tracker) bool {
+			return list[0].Label == "Acme Corp"
+		}},
+		{"rate", func(acme *Tracker) { acme.Rate = 120 }, func(list []*Tracker) bool {
+			return list[0].Rate == 120
+		}},
+		{"archive", func(acme *Tracker) { _ = acme.Fire(engine.Archive) }, func(list []*Tracker) bool {
+			return list[0].State == engine.Archived
+		}},
+		{"delete session", func(acme *Tracker) { acme.Sessions = nil }, func(list []*Tracker) bool {
+			return len(list[0].Sessions) == 0
+		}},
+		{"delete tracker", func(acme *Tracker) { DeleteTracker(acme.ID) }, func(list []*Tracker) bool {
+			return len(list) == 0
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newTestWindow(t, "Acme")
+			trackers[0].Sessions = []Session{{ID: "session-a", Start: start, End: start.Add(time.Hour)}}
+			stampChanges(synced)
+			base, err := syncDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// edited on another computer
+			test.edit(trackers[0])
+			stampChanges(edited)
+			remote, err := syncDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			merged, err := mergeDocuments(base, remote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, err := decodeConfig(merged)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !test.check(config.Trackers) {
+				t.Errorf("merged document lost the change:\n%s", merged)
+			}
+
+			// back to the synced state here
+			config, err = decodeConfig(base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			trackers, deletedTrackers = nil, nil
+			for _, tracker := range config.Trackers {
+				addTracker(tracker)
+			}
+			rememberTrackers()
+			err = applySync(nil, remote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !test.check(trackers) {
+				t.Errorf("change not applied: %+v", trackers)
+			}
+		})
+	}
+}
+
+func TestSyncLastModifiedWins(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	delete := Tombstone{ID: "session-a", At: start.Add(2 * time.Hour)}
+	local := &Tracker{ID: "tracker-1", Label: "Acme Corp", Modified: start.Add(3 * time.Hour), Sessions: []Session{
+		{ID: "session-b", Start: start, End: start.Add(time.Hour), Note: "local", Modified: start.Add(time.Hour)},
+	}, Deleted: []Tombstone{delete}}
+	remote := &Tracker{ID: "tracker-1", Label: "Acme renamed elsewhere", Modified: start.Add(time.Hour), Sessions: []Session{
+		// edited elsewhere after deleted here
+		{ID: "session-a", Start: start.Add(-time.Hour), End: start, Note: "kept", Modified: start.Add(4 * time.Hour)},
+		{ID: "session-b", Start: start, End: start.Add(time.Hour), Note: "remote"},
+	}}
+
+	merged, err := mergeDocuments(syncTrackers(t, local), syncTrackers(t, remote))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := decodeConfig(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acme := config.Trackers[0]
+	if acme.Label != "Acme Corp" {
+		t.Errorf("label = %q, expected the later local one", acme.Label)
+	}
+	if len(acme.Sessions) != 2 || acme.Sessions[0].Note != "kept" || acme.Sessions[1].Note != "local" {
+		t.Errorf("sessions = %+v, expected the later edits", acme.Sessions)
+	}
+
+	// an older copy doesn't bring a deleted tracker back
+	data, err := yaml.Marshal(Config{Deleted: []Tombstone{{ID: "tracker-1", At: start.Add(5 * time.Hour)}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err = mergeDocuments(data, merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config, err = decodeConfig(merged); err != nil || len(config.Trackers) != 0 || len(config.Deleted) != 1 {
+		t.Errorf("merged %+v %v, expected the tracker deleted", config, err)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"log"
+	"runtime"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver"
+
+	"github.com/gxben/clocker/pkg/taskbar"
+)
+
+var (
+	taskbarButton  taskbar.Taskbar
+	taskbarFailed  bool
+	taskbarState   = taskbar.NoProgress
+	taskbarPercent = 0
+)
+
+// attachTaskbar looks for the native window handle, which only exists once
+// the window got shown.
+func attachTaskbar(w fyne.Window) {
+	if runtime.GOOS != "windows" || taskbarButton != nil || taskbarFailed {
+		return
+	}
+	nw, ok := w.(driver.NativeWindow)
+	if !ok {
+		return
+	}
+
+	nw.RunNative(func(ctx any) {
+		wc, ok := ctx.(driver.WindowsWindowContext)
+		if !ok || wc.HWND == 0 {
+			return
+		}
+		tb, err := taskbar.New(wc.HWND)
+		if err != nil {
+			log.Println(err)
+			taskbarFailed = true
+			return
+		}
+		taskbarButton = tb
+	})
+}
+
+// refreshTaskbar shows running trackers on the taskbar button: their budget
+// burn when they have one, a busy indicator otherwise.
+func refreshTaskbar() {
+	if taskbarButton == nil {
+		return
+	}
+
+	state := taskbar.NoProgress
+	percent := 0
+	for _, t := range trackers {
+		if !t.Running() {
+			continue
+		}
+		if t.Budget == 0 {
+			if state == taskbar.NoProgress {
+				state = taskbar.Indeterminate
+			}
+			continue
+		}
+
+		burn := min(int(t.Burn()*100), 100)
+		if burn >= percent {
+			percent = burn
+			state = taskbar.Normal
+			if burn == 100 {
+				state = taskbar.Error
+			}
+		}
+	}
+
+	if state == taskbarState && percent == taskbarPercent {
+		return
+	}
+	taskbarState = state
+	taskbarPercent = percent
+
+	err := taskbarButton.SetProgress(state, uint64(percent), 100)
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// ScaleChoices are the percentages offered for the UI and text scales.
+var ScaleChoices = []int{75, 90, 100, 110, 125, 150, 175, 200}
+
+// clockerTheme scales the default theme, on top of the FYNE_SCALE factor
+// the driver already applies for the monitor.
+type clockerTheme struct {
+	fyne.Theme
+	scale    float32
+	text     float32
+	contrast bool
+}
+
+// highContrast palettes only use pure black and white, with a single
+// accent color, for low vision users.
+var highContrast = map[fyne.ThemeVariant]map[fyne.ThemeColorName]color.Color{
+	theme.VariantDark: {
+		theme.ColorNameBackground:        color.Black,
+		theme.ColorNameForeground:        color.White,
+		theme.ColorNameButton:            color.Black,
+		theme.ColorNameDisabled:          color.Gray{Y: 0xc0},
+		theme.ColorNameDisabledButton:    color.Black,
+		theme.ColorNameInputBackground:   color.Black,
+		theme.ColorNameInputBorder:       color.White,
+		theme.ColorNameMenuBackground:    color.Black,
+		theme.ColorNameOverlayBackground: color.Black,
+		theme.ColorNamePlaceHolder:       color.Gray{Y: 0xe0},
+		theme.ColorNamePrimary:           color.RGBA{R: 0xff, G: 0xd6, A: 0xff},
+		theme.ColorNameFocus:             color.RGBA{R: 0xff, G: 0xd6, A: 0xff},
+		theme.ColorNameHover:             color.Gray{Y: 0x40},
+		theme.ColorNamePressed:           color.Gray{Y: 0x60},
+		theme.ColorNameSelection:         color.RGBA{R: 0x80, G: 0x6b, A: 0xff},
+		theme.ColorNameSeparator:         color.White,
+		theme.ColorNameShadow:            color.Transparent,
+	},
+	theme.VariantLight: {
+		theme.ColorNameBackground:        color.White,
+		theme.ColorNameForeground:        color.Black,
+		theme.ColorNameButton:            color.White,
+		theme.ColorNameDisabled:          color.Gray{Y: 0x40},
+		theme.ColorNameDisabledButton:    color.White,
+		theme.ColorNameInputBackground:   color.White,
+		theme.ColorNameInputBorder:       color.Black,
+		theme.ColorNameMenuBackground:    color.White,
+		theme.ColorNameOverlayBackground: color.White,
+		theme.ColorNamePlaceHolder:       color.Gray{Y: 0x20},
+		theme.ColorNamePrimary:           color.RGBA{B: 0xc0, A: 0xff},
+		theme.ColorNameFocus:             color.RGBA{B: 0xc0, A: 0xff},
+		theme.ColorNameHover:             color.Gray{Y: 0xd0},
+		theme.ColorNamePressed:           color.Gray{Y: 0xb0},
+		theme.ColorNameSelection:         color.RGBA{R: 0xb0, G: 0xc8, B: 0xff, A: 0xff},
+		theme.ColorNameSeparator:         color.Black,
+		theme.ColorNameShadow:            color.Transparent,
+	},
+}
+
+func (t clockerTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if t.contrast {
+		if c, ok := highContrast[variant][name]; ok {
+			return c
+		}
+	}
+	return t.Theme.Color(name, variant)
+}
+
+func (t clockerTheme) Size(name fyne.ThemeSizeName) float32 {
+	size := t.Theme.Size(name) * t.scale
+	switch name {
+	case theme.SizeNameText, theme.SizeNameHeadingText, theme.SizeNameSubHeadingText, theme.SizeNameCaptionText:
+		size *= t.text
+	}
+	return size
+}
+
+func scaleFactor(percent int) float32 {
+	if percent <= 0 {
+		return 1
+	}
+	return float32(percent) / 100
+}
+
+func formatScale(percent int) string {
+	if percent <= 0 {
+		percent = 100
+	}
+	return fmt.Sprintf("%d%%", percent)
+}
+
+func parseScale(s string) (int, error) {
+	percent, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	if err != nil || percent < ScaleChoices[0] || percent > ScaleChoices[len(ScaleChoices)-1] {
+		return 0, fmt.Errorf("invalid scale %q", s)
+	}
+	return percent, nil
+}
+
+func scaleOptions() []string {
+	options := []string{}
+	for _, c := range ScaleChoices {
+		options = append(options, formatScale(c))
+	}
+	return options
+}
+
+func applyTheme() {
+	fyne.CurrentApp().Settings().SetTheme(clockerTheme{
+		Theme:    theme.DefaultTheme(),
+		scale:    scaleFactor(settings.UIScale),
+		text:     scaleFactor(settings.TextScale),
+		contrast: settings.HighContrast,
+	})
+}
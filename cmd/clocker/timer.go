@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+func formatTimerPresets(presets []int) string {
+	values := []string{}
+	for _, p := range presets {
+		values = append(values, strconv.Itoa(p))
+	}
+	return strings.Join(values, ", ")
+}
+
+func parseTimerPresets(s string) ([]int, error) {
+	presets := []int{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "m"))
+		if v == "" {
+			continue
+		}
+		p, err := strconv.Atoi(v)
+		if err != nil || p <= 0 {
+			return nil, fmt.Errorf("invalid timer preset %q", v)
+		}
+		presets = append(presets, p)
+	}
+	sort.Ints(presets)
+	return presets, nil
+}
+
+// remaining returns the countdown time left, if any.
+func (t *Tracker) remaining(now time.Time) (time.Duration, bool) {
+	if t.deadline.IsZero() || !t.Running() {
+		return 0, false
+	}
+	return max(t.deadline.Sub(now), 0), true
+}
+
+// startTimer runs the tracker for d, then pauses it. A running tracker
+// gets its countdown restarted.
+func startTimer(w fyne.Window, t *Tracker, d time.Duration) {
+	if t.Running() {
+		t.deadline = time.Now().Add(d)
+		t.refreshElapsed()
+		wakeClock()
+		return
+	}
+	guardClosedPeriods(w, t, "start tracking", []timeRange{{From: time.Now()}}, func() {
+		t.Start()
+		t.deadline = time.Now().Add(d)
+		t.refreshElapsed()
+		refreshTray(w)
+		attachTaskbar(w)
+		refreshTaskbar()
+	})
+}
+
+// nextDeadline returns how long until the first countdown ends.
+func nextDeadline(now time.Time) (time.Duration, bool) {
+	next, found := time.Duration(0), false
+	for _, t := range trackers {
+		if left, ok := t.remaining(now); ok && (!found || left < next) {
+			next, found = left, true
+		}
+	}
+	return next, found
+}
+
+// expireTimers pauses the trackers whose countdown is over, their session
+// ending right on time, telling whether any was.
+func expireTimers(now time.Time) bool {
+	expired := false
+	for _, t := range trackers {
+		if left, ok := t.remaining(now); !ok || left > 0 {
+			continue
+		}
+		t.stop(t.deadline)
+		log.Println("Timer of", t.Label, "is over")
+		notifyUser(AlertTimer, "Time's up", fmt.Sprintf("%s has been paused.", t.Label))
+		expired = true
+	}
+	return expired
+}
+
+// makeTimerButton offers the countdown presets next to the play button.
+func makeTimerButton(w fyne.Window, t *Tracker) *iconButton {
+	var button *iconButton
+	button = newIconButton("Timer for "+t.Label, theme.MenuDropDownIcon(), func() {
+		items := []*fyne.MenuItem{}
+		for _, minutes := range settings.TimerPresets {
+			d := time.Duration(minutes) * time.Minute
+			items = append(items, fyne.NewMenuItem(shortDur(d), func() {
+				startTimer(w, t, d)
+			}))
+		}
+		widget.ShowPopUpMenuAtRelativePosition(fyne.NewMenu("", items...), w.Canvas(), fyne.NewPos(0, button.Size().Height), button)
+	})
+	return button
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock/clocktest"
+)
+
+func TestTimer(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex")
+	acme, globex := trackers[0], trackers[1]
+
+	startTimer(w, acme, 25*time.Minute)
+	startTimer(w, globex, 50*time.Minute)
+	if !acme.Running() {
+		t.Fatal("timer didn't start the tracker")
+	}
+	if s, _ := acme.ElapsedStr.Get(); !strings.HasSuffix(s, "(25m left)") && !strings.HasSuffix(s, "(24m59s left)") {
+		t.Errorf("counter shows %q, expected the time left", s)
+	}
+	clk := clocktest.NewFake(time.Now())
+	if left, ok := nextDeadline(clk.Now()); !ok || left > 25*time.Minute {
+		t.Errorf("next deadline in %s", left)
+	}
+	if expireTimers(clk.Now()) {
+		t.Fatal("expired a running countdown")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		runClock(ctx, w, clk)
+		close(done)
+	}()
+	acmeDeadline, globexDeadline := acme.deadline, globex.deadline
+
+	// the clock waits for the next tick, then for the globex countdown
+	clk.BlockUntil(1)
+	clk.Advance(26 * time.Minute)
+	clk.BlockUntil(1)
+	if acme.Running() || !acme.Sessions[0].End.Equal(acmeDeadline) {
+		t.Errorf("session %+v, expected paused on time", acme.Sessions[0])
+	}
+	if _, ok := acme.remaining(clk.Now()); ok {
+		t.Error("paused tracker still counting down")
+	}
+	if !globex.Running() {
+		t.Error("globex paused before its countdown is over")
+	}
+
+	// nothing runs anymore, leaving the clock asleep
+	clk.Advance(25 * time.Minute)
+	cancel()
+	<-done
+	if globex.Running() || !globex.Sessions[0].End.Equal(globexDeadline) {
+		t.Errorf("session %+v, expected paused on time", globex.Sessions[0])
+	}
+}
+
+func TestTimerPausedByHand(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+	acme := trackers[0]
+
+	startTimer(w, acme, 50*time.Minute)
+	tapIcon(t, w, "Pause Acme")
+	if _, ok := nextDeadline(time.Now()); ok {
+		t.Error("countdown left after pausing")
+	}
+	if _, ok := iconButtons(w)["Timer for Acme"]; !ok {
+		t.Error("no timer button next to play")
+	}
+}
+
+func TestParseTimerPresets(t *testing.T) {
+	presets, err := parseTimerPresets("90, 25m,50")
+	if err != nil || !slices.Equal(presets, []int{25, 50, 90}) {
+		t.Errorf("parseTimerPresets() = %v, %v", presets, err)
+	}
+	if _, err := parseTimerPresets("25, soon"); err == nil {
+		t.Error("parsed an invalid preset")
+	}
+}
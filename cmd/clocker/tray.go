@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"runtime"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/lang"
+
+	"github.com/gxben/clocker/pkg/tray"
+)
+
+var (
+	trayTitle   = ""
+	trayTooltip = ""
+	trayIcon    = ""
+)
+
+var (
+	trayRunningColor = color.NRGBA{R: 0x2e, G: 0x7d, B: 0x32, A: 0xff}
+	trayOverColor    = color.NRGBA{R: 0xc6, G: 0x28, B: 0x28, A: 0xff}
+)
+
+// menuBarMode keeps clocker in the macOS menu bar only, the main window
+// being opened on demand.
+func menuBarMode() bool {
+	return runtime.GOOS == "darwin" && settings.MenuBar
+}
+
+// statusNotifier tells whether the tray is exposed as a StatusNotifierItem,
+// which is how the Fyne systray integrates with XDG desktops. Panels
+// supporting it (KDE, GNOME AppIndicator extension) display its title.
+func statusNotifier() bool {
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return true
+	}
+	return false
+}
+
+func trayEnabled() bool {
+	return menuBarMode() || statusNotifier()
+}
+
+func refreshTray(w fyne.Window) {
+	if !trayEnabled() {
+		return
+	}
+	desk, ok := fyne.CurrentApp().(desktop.App)
+	if !ok {
+		return
+	}
+
+	items := []*fyne.MenuItem{}
+	for _, t := range visibleTrackers() {
+		item := fyne.NewMenuItem(t.Label, func() {
+			toggleTracker(w, t)
+		})
+		item.Checked = t.Running()
+		items = append(items, item)
+	}
+	items = append(items,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(lang.L("Open Clocker"), func() {
+			showWindow(w)
+		}),
+	)
+	desk.SetSystemTrayMenu(fyne.NewMenu("Clocker", items...))
+	refreshTrayTitle()
+}
+
+// refreshTrayTitle shows the running tracker and its elapsed time in the
+// menu bar or panel, with all running trackers listed in the tooltip.
+func refreshTrayTitle() {
+	if !trayEnabled() {
+		return
+	}
+
+	title := ""
+	running := []string{}
+	for _, t := range trackers {
+		if !t.Running() {
+			continue
+		}
+		// refreshed every minute only when the window is hidden
+		precision := ClockFrequency
+		if hidden() {
+			precision = time.Minute
+		}
+		line := fmt.Sprintf("%s %s", t.Label, shortDur(t.Current().Truncate(precision)))
+		if len(running) == 0 {
+			title = line
+		}
+		running = append(running, line)
+	}
+	if len(running) > 1 {
+		title = fmt.Sprintf("%s +%d", title, len(running)-1)
+	}
+
+	// some XDG trays crash when the title is empty
+	if title == "" && statusNotifier() {
+		title = "Clocker"
+	}
+	tooltip := strings.Join(running, "\n")
+
+	if title != trayTitle {
+		trayTitle = title
+		tray.SetTitle(title)
+	}
+	if tooltip != trayTooltip {
+		trayTooltip = tooltip
+		tray.SetTooltip(tooltip)
+	}
+	refreshTrayIcon()
+}
+
+// refreshTrayIcon draws the first running tracker progress as a ring: its
+// budget burn when it has one, the current hour otherwise. It only gets
+// rendered again when the ring moves, about once a minute.
+func refreshTrayIcon() {
+	desk, ok := fyne.CurrentApp().(desktop.App)
+	if !ok {
+		return
+	}
+
+	var running *Tracker
+	for _, t := range trackers {
+		if t.Running() {
+			running = t
+			break
+		}
+	}
+
+	if running == nil {
+		if trayIcon != "idle" {
+			trayIcon = "idle"
+			if r := resource("icons/tray-idle.svg"); r != nil {
+				desk.SetSystemTrayIcon(r)
+			}
+		}
+		return
+	}
+
+	progress := float64(running.Current()%time.Hour) / float64(time.Hour)
+	fill := trayRunningColor
+	if running.Budget > 0 {
+		progress = running.Burn()
+		if progress >= 1 {
+			fill = trayOverColor
+		}
+	}
+	// one step per minute of the hour
+	step := int(progress * 60)
+	key := fmt.Sprintf("%p/%d/%v", running, step, fill)
+	if key == trayIcon {
+		return
+	}
+	trayIcon = key
+	desk.SetSystemTrayIcon(fyne.NewStaticResource("clocker-tray.png", tray.Icon(float64(step)/60, fill)))
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/gxben/clocker/pkg/engine"
+)
+
+// newTestWindow resets the global state and returns a window showing the
+// given trackers, keeping the configuration away from the user's one.
+func newTestWindow(t *testing.T, labels ...string) fyne.Window {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	test.NewTempApp(t)
+
+	store = nil
+	trackers = []*Tracker{}
+	saved, deletedTrackers = nil, nil
+	settings = Settings{}
+	dialogs = dialogs[:0]
+	selected, navigating = 0, false
+	filterQuery = ""
+	for _, label := range labels {
+		NewTracker(label, 0)
+	}
+
+	w := test.NewWindow(nil)
+	t.Cleanup(w.Close)
+	update(w)
+	return w
+}
+
+func topDialog(t *testing.T, w fyne.Window) fyne.CanvasObject {
+	t.Helper()
+	top := w.Canvas().Overlays().Top()
+	if top == nil {
+		t.Fatal("no dialog shown")
+	}
+	return top
+}
+
+// tapButton taps the dialog button with the given text.
+func tapButton(t *testing.T, w fyne.Window, text string) {
+	t.Helper()
+	for _, o := range test.LaidOutObjects(topDialog(t, w)) {
+		if b, ok := o.(*widget.Button); ok && b.Text == text {
+			test.Tap(b)
+			return
+		}
+	}
+	t.Fatalf("no %q button in dialog", text)
+}
+
+// iconButtons returns the named icon buttons shown in the window.
+func iconButtons(w fyne.Window) map[string]*iconButton {
+	buttons := map[string]*iconButton{}
+	for _, o := range test.LaidOutObjects(w.Content()) {
+		if b, ok := o.(*iconButton); ok {
+			buttons[b.Name] = b
+		}
+	}
+	return buttons
+}
+
+func tapIcon(t *testing.T, w fyne.Window, name string) {
+	t.Helper()
+	b, ok := iconButtons(w)[name]
+	if !ok {
+		t.Fatalf("no %q button in window", name)
+	}
+	test.Tap(b)
+}
+
+func typeFocused(t *testing.T, w fyne.Window, text string) {
+	t.Helper()
+	focused := w.Canvas().Focused()
+	if focused == nil {
+		t.Fatal("no field focused")
+	}
+	e, ok := focused.(*entry)
+	if !ok {
+		t.Fatalf("focused %T, expected an entry", focused)
+	}
+	e.SetText("")
+	test.Type(e, text)
+}
+
+func TestAddTrackerDialog(t *testing.T) {
+	w := newTestWindow(t)
+
+	tapIcon(t, w, "Add tracker")
+	typeFocused(t, w, "Acme")
+	tapButton(t, w, "Add")
+
+	if len(trackers) != 1 || trackers[0].Label != "Acme" {
+		t.Fatalf("trackers = %v, expected Acme", trackers)
+	}
+	if len(dialogs) != 0 {
+		t.Errorf("%d dialogs left open", len(dialogs))
+	}
+	if _, ok := iconButtons(w)["Start Acme"]; !ok {
+		t.Error("list not refreshed with the new tracker")
+	}
+}
+
+func TestAddTrackerDialogCancel(t *testing.T) {
+	w := newTestWindow(t)
+
+	tapIcon(t, w, "Add tracker")
+	typeFocused(t, w, "Acme")
+	tapButton(t, w, "Cancel")
+
+	if len(trackers) != 0 {
+		t.Fatalf("cancelled dialog added %d trackers", len(trackers))
+	}
+}
+
+func TestEditTrackerDialog(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex")
+
+	tapIcon(t, w, "Edit Globex")
+	typeFocused(t, w, "Initech")
+	tapButton(t, w, "Update")
+
+	if trackers[0].Label != "Acme" {
+		t.Errorf("edited the wrong tracker, first one is now %q", trackers[0].Label)
+	}
+	if trackers[1].Label != "Initech" {
+		t.Errorf("label = %q, expected Initech", trackers[1].Label)
+	}
+	if s, _ := trackers[1].LabelStr.Get(); s != "Initech" {
+		t.Errorf("bound label = %q, expected Initech", s)
+	}
+	buttons := iconButtons(w)
+	if _, ok := buttons["Edit Initech"]; !ok {
+		t.Error("list not refreshed with the new label")
+	}
+	if _, ok := buttons["Edit Globex"]; ok {
+		t.Error("list still shows the old label")
+	}
+}
+
+func TestEditTrackerDialogInvalid(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+
+	tapIcon(t, w, "Edit Acme")
+	typeFocused(t, w, "Globex")
+	test.FocusNext(w.Canvas())
+	typeFocused(t, w, "not a rate")
+	tapButton(t, w, "Update")
+
+	if trackers[0].Label != "Acme" {
+		t.Errorf("invalid rate still renamed the tracker to %q", trackers[0].Label)
+	}
+	if len(dialogs) != 1 {
+		t.Errorf("%d dialogs open, expected the error", len(dialogs))
+	}
+}
+
+func TestDeleteTrackerDialog(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex", "Initech")
+
+	tapIcon(t, w, "Delete Globex")
+	tapButton(t, w, "Yes")
+
+	if len(trackers) != 2 || trackers[0].Label != "Acme" || trackers[1].Label != "Initech" {
+		t.Fatalf("trackers = %s, %s, expected Acme, Initech", trackers[0].Label, trackers[1].Label)
+	}
+	buttons := iconButtons(w)
+	if _, ok := buttons["Delete Globex"]; ok {
+		t.Error("list still shows the deleted tracker")
+	}
+	if _, ok := buttons["Delete Initech"]; !ok {
+		t.Error("list lost a remaining tracker")
+	}
+}
+
+func TestDeleteTrackerDialogCancel(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+
+	tapIcon(t, w, "Delete Acme")
+	tapButton(t, w, "No")
+
+	if len(trackers) != 1 {
+		t.Fatalf("cancelled dialog left %d trackers", len(trackers))
+	}
+}
+
+func TestDeleteTrackerDialogLocked(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+	now := time.Now()
+	trackers[0].Sessions = []Session{{Start: now.Add(-time.Hour), End: now, Locked: true}}
+
+	tapIcon(t, w, "Delete Acme")
+	tapButton(t, w, "Yes")
+
+	if len(trackers) != 1 {
+		t.Fatal("deleted a tracker with approved sessions")
+	}
+	if trackers[0].State != engine.Archived {
+		t.Errorf("state = %s, expected archived", trackers[0].State)
+	}
+	if _, ok := iconButtons(w)["Start Acme"]; ok {
+		t.Error("archived tracker still listed")
+	}
+}
+
+func TestPlayPause(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex")
+	acme, globex := trackers[0], trackers[1]
+
+	tapIcon(t, w, "Start Globex")
+	if !globex.Running() || acme.Running() {
+		t.Fatalf("running acme=%v globex=%v, expected only globex", acme.Running(), globex.Running())
+	}
+	if globex.PlayButton.Name != "Pause Globex" {
+		t.Errorf("button name = %q, expected Pause Globex", globex.PlayButton.Name)
+	}
+	if len(globex.Sessions) != 1 || !globex.Sessions[0].Open() {
+		t.Fatalf("sessions = %v, expected one open session", globex.Sessions)
+	}
+
+	tapIcon(t, w, "Start Acme")
+	if !acme.Running() || !globex.Running() {
+		t.Fatalf("running acme=%v globex=%v, expected both", acme.Running(), globex.Running())
+	}
+
+	tapIcon(t, w, "Pause Globex")
+	if globex.Running() || !acme.Running() {
+		t.Fatalf("running acme=%v globex=%v, expected only acme", acme.Running(), globex.Running())
+	}
+	if globex.Sessions[0].Open() {
+		t.Error("pausing left the session open")
+	}
+	if globex.PlayButton.Name != "Start Globex" {
+		t.Errorf("button name = %q, expected Start Globex", globex.PlayButton.Name)
+	}
+}
+
+func TestPlayPauseAfterRefresh(t *testing.T) {
+	w := newTestWindow(t, "Acme", "Globex")
+	acme := trackers[0]
+
+	tapIcon(t, w, "Start Acme")
+	update(w)
+
+	if _, ok := iconButtons(w)["Pause Acme"]; !ok {
+		t.Fatal("refreshed list lost the running state")
+	}
+	tapIcon(t, w, "Pause Acme")
+	if acme.Running() {
+		t.Error("button of the refreshed list doesn't drive the tracker")
+	}
+}
+
+func TestListRefresh(t *testing.T) {
+	w := newTestWindow(t, "Acme")
+
+	NewTracker("Globex", 0)
+	if _, ok := iconButtons(w)["Start Globex"]; ok {
+		t.Fatal("list refreshed before update")
+	}
+
+	update(w)
+	buttons := iconButtons(w)
+	for _, name := range []string{"Start Acme", "Start Globex"} {
+		if _, ok := buttons[name]; !ok {
+			t.Errorf("no %q button after refresh", name)
+		}
+	}
+	if len(highlights) != 2 {
+		t.Errorf("%d highlighted rows, expected 2", len(highlights))
+	}
+}
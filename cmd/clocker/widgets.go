@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// hintStr describes the control under the mouse or holding the keyboard
+// focus, shown at the bottom of the window.
+var hintStr = binding.NewString()
+
+// iconButton is a button without text, carrying the name it is announced
+// with when hovered or focused.
+type iconButton struct {
+	widget.Button
+	Name string
+}
+
+func newIconButton(name string, icon fyne.Resource, tapped func()) *iconButton {
+	b := &iconButton{Name: name}
+	b.Icon = icon
+	b.OnTapped = tapped
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+// SetName updates the announced name, e.g. when the button toggles.
+func (b *iconButton) SetName(name string) {
+	showing := b.Name != "" && hint() == b.Name
+	b.Name = name
+	if showing {
+		_ = hintStr.Set(name)
+	}
+}
+
+func (b *iconButton) MouseIn(e *desktop.MouseEvent) {
+	b.Button.MouseIn(e)
+	_ = hintStr.Set(b.Name)
+}
+
+func (b *iconButton) MouseOut() {
+	b.Button.MouseOut()
+	b.clearHint()
+}
+
+func (b *iconButton) FocusGained() {
+	b.Button.FocusGained()
+	_ = hintStr.Set(b.Name)
+}
+
+func (b *iconButton) FocusLost() {
+	b.Button.FocusLost()
+	b.clearHint()
+}
+
+func (b *iconButton) clearHint() {
+	if hint() == b.Name {
+		_ = hintStr.Set("")
+	}
+}
+
+func hint() string {
+	s, _ := hintStr.Get()
+	return s
+}
+
+func makeHint() fyne.CanvasObject {
+	label := widget.NewLabelWithData(hintStr)
+	label.Truncation = fyne.TextTruncateEllipsis
+	label.Alignment = leadingAlignment()
+	return label
+}
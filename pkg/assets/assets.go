@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package assets bundles the icons, sounds, message catalogs and report
+// templates in the binary. Any of them can be replaced by dropping a file
+// with the same path in an override directory.
+package assets
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+//go:embed icons sounds templates translations
+var embedded embed.FS
+
+// overlay looks files up in the override directory first, then in the
+// embedded assets.
+type overlay struct {
+	override fs.FS
+}
+
+// New returns the assets, files from dir taking precedence over the
+// embedded ones. An empty or missing dir leaves the embedded assets only.
+func New(dir string) fs.FS {
+	o := overlay{}
+	if dir != "" {
+		o.override = os.DirFS(dir)
+	}
+	return o
+}
+
+func (o overlay) Open(name string) (fs.File, error) {
+	if o.override != nil {
+		f, err := o.override.Open(name)
+		if err == nil {
+			return f, nil
+		}
+	}
+	return embedded.Open(name)
+}
+
+// ReadDir merges the entries of both directories, so extra files such as
+// new translations can be added next to the embedded ones.
+func (o overlay) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(embedded, name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if o.override == nil {
+		return entries, err
+	}
+
+	extra, oerr := fs.ReadDir(o.override, name)
+	if oerr != nil {
+		return entries, err
+	}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		seen[e.Name()] = true
+	}
+	for _, e := range extra {
+		if !seen[e.Name()] {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
@@ -0,0 +1,20 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package autostart registers the application to be launched when the user
+// logs into the desktop session.
+package autostart
+
+import (
+	"errors"
+)
+
+const (
+	AppName = "Clocker"
+	AppID   = "io.github.gxben.clocker"
+)
+
+var ErrUnsupported = errors.New("start at login not supported on this platform")
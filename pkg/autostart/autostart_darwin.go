@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package autostart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// launchAgent is the per-user launchd job, loaded at the next login.
+func launchAgent() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", AppID+".plist"), nil
+}
+
+func escape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func Enable(exe string, args ...string) error {
+	file, err := launchAgent()
+	if err != nil {
+		return err
+	}
+
+	var program bytes.Buffer
+	for _, a := range append([]string{exe}, args...) {
+		fmt.Fprintf(&program, "\t\t<string>%s</string>\n", escape(a))
+	}
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>ProcessType</key>
+	<string>Interactive</string>
+</dict>
+</plist>
+`, AppID, program.String())
+
+	err = os.MkdirAll(filepath.Dir(file), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(plist), 0o644)
+}
+
+func Disable() error {
+	file, err := launchAgent()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func Enabled() bool {
+	file, err := launchAgent()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(file)
+	return err == nil
+}
@@ -0,0 +1,21 @@
+//go:build !linux && !freebsd && !openbsd && !netbsd && !darwin && !windows
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package autostart
+
+func Enable(exe string, args ...string) error {
+	return ErrUnsupported
+}
+
+func Disable() error {
+	return nil
+}
+
+func Enabled() bool {
+	return false
+}
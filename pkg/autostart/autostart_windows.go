@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package autostart
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// runKey lists the programs started when the user logs in.
+const runKey = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+func Enable(exe string, args ...string) error {
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, runKey, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+
+	cmd := []string{windows.EscapeArg(exe)}
+	for _, a := range args {
+		cmd = append(cmd, windows.EscapeArg(a))
+	}
+	return k.SetStringValue(AppName, strings.Join(cmd, " "))
+}
+
+func Disable() error {
+	k, err := registry.OpenKey(registry.CURRENT_USER, runKey, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+
+	err = k.DeleteValue(AppName)
+	if errors.Is(err, registry.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func Enabled() bool {
+	k, err := registry.OpenKey(registry.CURRENT_USER, runKey, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer k.Close()
+
+	_, _, err = k.GetStringValue(AppName)
+	return err == nil
+}
@@ -0,0 +1,79 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package autostart
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// desktopFile follows the XDG autostart specification, entries being
+// regular desktop files dropped in the user autostart directory.
+func desktopFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "autostart", AppID+".desktop"), nil
+}
+
+// quoteExec quotes an argument of the Exec key, as defined by the desktop
+// entry specification.
+func quoteExec(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\><~|&;$*?#()`") {
+		return arg
+	}
+	r := strings.NewReplacer(`"`, `\"`, "`", "\\`", `$`, `\$`, `\`, `\\`)
+	return `"` + r.Replace(arg) + `"`
+}
+
+func Enable(exe string, args ...string) error {
+	file, err := desktopFile()
+	if err != nil {
+		return err
+	}
+	cmd := []string{quoteExec(exe)}
+	for _, a := range args {
+		cmd = append(cmd, quoteExec(a))
+	}
+	// the Exec value is itself a string, backslashes get escaped once more
+	exec := strings.ReplaceAll(strings.Join(cmd, " "), `\`, `\\`)
+
+	entry := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=%s\nExec=%s\nTerminal=false\nX-GNOME-Autostart-enabled=true\n", AppName, exec)
+	err = os.MkdirAll(filepath.Dir(file), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(entry), 0o644)
+}
+
+func Disable() error {
+	file, err := desktopFile()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func Enabled() bool {
+	file, err := desktopFile()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(file)
+	return err == nil
+}
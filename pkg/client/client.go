@@ -0,0 +1,368 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package client lets other Go tools, such as status bars or bots, follow
+// clocker through the event log it appends to once its event_log setting
+// is set, start and stop trackers through the control file it reads once
+// its control_file setting is set, and get reports by running clocker
+// export. Clocker runs no server, both files being JSON Lines.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPoll is how often a streamed event log gets checked for new
+	// events.
+	DefaultPoll = time.Second
+	// DefaultCommand is the clocker command run for reports, looked up in
+	// the PATH.
+	DefaultCommand = "clocker"
+	DateFormat     = "2006-01-02"
+)
+
+// Event is a line of the event log: a tracker lifecycle event, e.g. start
+// or pause, or a change such as an edited session.
+type Event struct {
+	Event     string
+	At        time.Time
+	TrackerID string
+	Tracker   string
+	Issue     string
+	Receiver  string
+	// State is the one of the tracker after the event.
+	State string
+	// the session the event started or ended, if any
+	SessionID string
+	Start     time.Time
+	End       time.Time
+	Duration  time.Duration
+	// Elapsed is the time on the tracker counter at the event.
+	Elapsed time.Duration
+	Detail  string
+}
+
+// Record is a line of the event log as clocker writes it, durations being
+// in seconds to be easily summed up by analytics tools.
+type Record struct {
+	Event     string     `json:"event"`
+	At        time.Time  `json:"at"`
+	TrackerID string     `json:"tracker_id,omitempty"`
+	Tracker   string     `json:"tracker,omitempty"`
+	Issue     string     `json:"issue,omitempty"`
+	Receiver  string     `json:"receiver,omitempty"`
+	State     string     `json:"state,omitempty"`
+	SessionID string     `json:"session_id,omitempty"`
+	Start     *time.Time `json:"start,omitempty"`
+	End       *time.Time `json:"end,omitempty"`
+	Seconds   float64    `json:"seconds,omitempty"`
+	Elapsed   float64    `json:"elapsed_seconds"`
+	Detail    string     `json:"detail,omitempty"`
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var r Record
+	err := json.Unmarshal(data, &r)
+	if err != nil {
+		return err
+	}
+	*e = Event{
+		Event:     r.Event,
+		At:        r.At,
+		TrackerID: r.TrackerID,
+		Tracker:   r.Tracker,
+		Issue:     r.Issue,
+		Receiver:  r.Receiver,
+		State:     r.State,
+		SessionID: r.SessionID,
+		Duration:  seconds(r.Seconds),
+		Elapsed:   seconds(r.Elapsed),
+		Detail:    r.Detail,
+	}
+	if r.Start != nil {
+		e.Start = *r.Start
+	}
+	if r.End != nil {
+		e.End = *r.End
+	}
+	return nil
+}
+
+// Actions of the control file commands.
+const (
+	ActionStart = "start"
+	ActionStop  = "stop"
+)
+
+// Command is a line of the control file, asking the running app to start
+// or stop a tracker. Commands appended while it isn't running are ignored.
+type Command struct {
+	Action string `json:"action"`
+	// Tracker is the ID or the label of the tracker.
+	Tracker string `json:"tracker"`
+}
+
+// Tracker is the last known state of a tracker, from its latest event.
+type Tracker struct {
+	ID       string
+	Label    string
+	Issue    string
+	Receiver string
+	State    string
+	Elapsed  time.Duration
+	// Updated is when the tracker last had an event.
+	Updated time.Time
+}
+
+func (t Tracker) Running() bool {
+	return t.State == "running"
+}
+
+// Current is the time on the counter at now, running trackers counting
+// since their latest event.
+func (t Tracker) Current(now time.Time) time.Duration {
+	if t.Running() && now.After(t.Updated) {
+		return t.Elapsed + now.Sub(t.Updated)
+	}
+	return t.Elapsed
+}
+
+// Client reads the event log of clocker.
+type Client struct {
+	file string
+	// Poll is how often StreamEvents checks for new events.
+	Poll time.Duration
+	// Command is the clocker command Report runs.
+	Command string
+	// Control is the control file of clocker Start and Stop append to,
+	// disabled when empty.
+	Control string
+}
+
+// New returns a client of the event log written to file.
+func New(file string) *Client {
+	return &Client{file: file, Poll: DefaultPoll, Command: DefaultCommand}
+}
+
+// readEvents decodes the complete lines of r, returning how many bytes
+// they took.
+func readEvents(r io.Reader, fn func(Event) error) (int64, error) {
+	return readLines(r, func(line []byte) error {
+		var e Event
+		err := json.Unmarshal(line, &e)
+		if err != nil {
+			return fmt.Errorf("invalid event %q: %w", line, err)
+		}
+		return fn(e)
+	})
+}
+
+// readLines calls fn with the complete non-empty lines of r, returning how
+// many bytes they took.
+func readLines(r io.Reader, fn func(line []byte) error) (int64, error) {
+	read := int64(0)
+	lines := bufio.NewReader(r)
+	for {
+		line, err := lines.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// a partial line is still being written
+			return read, nil
+		}
+		if err != nil {
+			return read, err
+		}
+		read += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		err = fn(line)
+		if err != nil {
+			return read, err
+		}
+	}
+}
+
+// Events returns all events logged so far, oldest first.
+func (c *Client) Events() ([]Event, error) {
+	f, err := os.Open(c.file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	events := []Event{}
+	_, err = readEvents(f, func(e Event) error {
+		events = append(events, e)
+		return nil
+	})
+	return events, err
+}
+
+// ListTrackers returns the trackers of the logged events, in the order
+// they first appeared, with their latest state.
+func (c *Client) ListTrackers() ([]Tracker, error) {
+	events, err := c.Events()
+	if err != nil {
+		return nil, err
+	}
+	trackers := []Tracker{}
+	index := map[string]int{}
+	for _, e := range events {
+		if e.TrackerID == "" {
+			continue
+		}
+		idx, ok := index[e.TrackerID]
+		if !ok {
+			idx = len(trackers)
+			index[e.TrackerID] = idx
+			trackers = append(trackers, Tracker{ID: e.TrackerID})
+		}
+		trackers[idx] = Tracker{
+			ID:       e.TrackerID,
+			Label:    e.Tracker,
+			Issue:    e.Issue,
+			Receiver: e.Receiver,
+			State:    e.State,
+			Elapsed:  e.Elapsed,
+			Updated:  e.At,
+		}
+	}
+	return trackers, nil
+}
+
+// StreamEvents calls fn with each event logged from now on, until ctx is
+// done or fn fails. The log may not exist yet, and may get truncated, in
+// which case it is read again from its beginning.
+func (c *Client) StreamEvents(ctx context.Context, fn func(Event) error) error {
+	return stream(ctx, c.file, c.Poll, func(r io.Reader) (int64, error) {
+		return readEvents(r, fn)
+	})
+}
+
+// StreamCommands calls fn with each command appended to the control file
+// from now on, checking it every poll, until ctx is done or fn fails.
+func StreamCommands(ctx context.Context, file string, poll time.Duration, fn func(Command) error) error {
+	return stream(ctx, file, poll, func(r io.Reader) (int64, error) {
+		return readLines(r, func(line []byte) error {
+			var c Command
+			err := json.Unmarshal(line, &c)
+			if err != nil {
+				return fmt.Errorf("invalid command %q: %w", line, err)
+			}
+			return fn(c)
+		})
+	})
+}
+
+// stream reads what gets appended to file from now on, until ctx is done
+// or read fails.
+func stream(ctx context.Context, file string, poll time.Duration, read func(io.Reader) (int64, error)) error {
+	offset := int64(0)
+	if info, err := os.Stat(file); err == nil {
+		offset = info.Size()
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		info, err := os.Stat(file)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			offset = 0
+		case err != nil:
+			return err
+		case info.Size() < offset:
+			offset = 0
+			fallthrough
+		case info.Size() > offset:
+			n, err := readFrom(file, offset, read)
+			offset += n
+			if err != nil {
+				return err
+			}
+		}
+		timer.Reset(poll)
+	}
+}
+
+func readFrom(file string, offset int64, read func(io.Reader) (int64, error)) (int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+	return read(f)
+}
+
+// Start asks the running app to start the tracker, given by ID or label.
+func (c *Client) Start(tracker string) error {
+	return c.send(Command{Action: ActionStart, Tracker: tracker})
+}
+
+// Stop asks the running app to stop the tracker, given by ID or label.
+func (c *Client) Stop(tracker string) error {
+	return c.send(Command{Action: ActionStop, Tracker: tracker})
+}
+
+// send appends the command to the control file, as a single write so that
+// commands of concurrent clients don't get mixed up.
+func (c *Client) send(cmd Command) error {
+	if c.Control == "" {
+		return errors.New("no control file, set the control_file setting of clocker")
+	}
+	line, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(c.Control, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Report writes the report of the days from first to last, included, in
+// an export format of clocker, e.g. csv. It runs clocker export, which
+// reads the saved trackers.
+func (c *Client) Report(ctx context.Context, w io.Writer, format string, first, last time.Time) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Command, "export", "--format", format,
+		"--from", first.Format(DateFormat), "--to", last.Format(DateFormat))
+	cmd.Stdout, cmd.Stderr = w, &stderr
+	err := cmd.Run()
+	if msg := strings.TrimSpace(stderr.String()); err != nil && msg != "" {
+		return fmt.Errorf("%s export: %w: %s", c.Command, err, msg)
+	}
+	return err
+}
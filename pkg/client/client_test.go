@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lines as clocker writes them
+const (
+	startLine = `{"event":"start","at":"2026-03-02T09:00:00Z","tracker_id":"t1","tracker":"Acme","issue":"ACME-1","state":"running","session_id":"s1","start":"2026-03-02T09:00:00Z","elapsed_seconds":3600}` + "\n"
+	pauseLine = `{"event":"pause","at":"2026-03-02T10:30:00Z","tracker_id":"t1","tracker":"Acme","issue":"ACME-1","state":"paused","session_id":"s1","start":"2026-03-02T09:00:00Z","end":"2026-03-02T10:30:00Z","seconds":5400,"elapsed_seconds":9000}` + "\n"
+	otherLine = `{"event":"start","at":"2026-03-02T10:30:00Z","tracker_id":"t2","tracker":"Globex","state":"running","session_id":"s2","start":"2026-03-02T10:30:00Z","elapsed_seconds":0}` + "\n"
+	closeLine = `{"event":"close","at":"2026-03-02T11:00:00Z","elapsed_seconds":0,"detail":"period=2026-02-01 to 2026-02-28"}` + "\n"
+)
+
+func TestMain(m *testing.M) {
+	// the test binary stands for clocker export, printing its arguments
+	if os.Getenv("CLIENT_TEST_EXPORT") != "" {
+		args := strings.Join(os.Args[1:], " ")
+		if strings.Contains(args, "bogus") {
+			fmt.Fprintln(os.Stderr, `unknown format "bogus"`)
+			os.Exit(1)
+		}
+		fmt.Println(args)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func writeLog(t *testing.T, file string, lines ...string) {
+	t.Helper()
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, line := range lines {
+		_, err = f.WriteString(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.jsonl")
+	writeLog(t, file, startLine, pauseLine, otherLine, closeLine)
+	c := New(file)
+
+	events, err := c.Events()
+	if err != nil || len(events) != 4 {
+		t.Fatalf("events = %+v, %v", events, err)
+	}
+	pause := events[1]
+	end := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	if pause.Event != "pause" || pause.SessionID != "s1" || !pause.End.Equal(end) || pause.Duration != 90*time.Minute || pause.Elapsed != 150*time.Minute {
+		t.Errorf("pause read as %+v", pause)
+	}
+	if !events[0].End.IsZero() || events[3].Detail == "" {
+		t.Errorf("events read as %+v", events)
+	}
+
+	trackers, err := c.ListTrackers()
+	if err != nil || len(trackers) != 2 {
+		t.Fatalf("trackers = %+v, %v", trackers, err)
+	}
+	acme, globex := trackers[0], trackers[1]
+	if acme.Label != "Acme" || acme.Issue != "ACME-1" || acme.Running() || acme.Current(end.Add(time.Hour)) != 150*time.Minute {
+		t.Errorf("Acme = %+v", acme)
+	}
+	if !globex.Running() || globex.Current(end.Add(time.Hour)) != time.Hour {
+		t.Errorf("Globex = %+v", globex)
+	}
+
+	_, err = New(filepath.Join(t.TempDir(), "missing.jsonl")).Events()
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("read a missing log: %v", err)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.jsonl")
+	// events logged before streaming are left out
+	writeLog(t, file, startLine)
+	c := New(file)
+	c.Poll = 5 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	received := make(chan Event)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.StreamEvents(ctx, func(e Event) error {
+			received <- e
+			return nil
+		})
+	}()
+	next := func() Event {
+		t.Helper()
+		select {
+		case e := <-received:
+			return e
+		case err := <-done:
+			t.Fatalf("streaming stopped: %v", err)
+		case <-ctx.Done():
+			t.Fatal("no event streamed")
+		}
+		return Event{}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	// a line being written waits for its end
+	writeLog(t, file, pauseLine[:40])
+	time.Sleep(20 * time.Millisecond)
+	writeLog(t, file, pauseLine[40:], otherLine)
+	if e := next(); e.Event != "pause" {
+		t.Errorf("streamed %+v, expected the pause", e)
+	}
+	if e := next(); e.Tracker != "Globex" {
+		t.Errorf("streamed %+v, expected Globex", e)
+	}
+
+	// a truncated log is read again from its beginning
+	err := os.WriteFile(file, []byte(closeLine), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := next(); e.Event != "close" {
+		t.Errorf("streamed %+v, expected the close", e)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("streaming ended with %v", err)
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Setenv("CLIENT_TEST_EXPORT", "1")
+	c := New("")
+	c.Command = os.Args[0]
+	first := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+
+	var out bytes.Buffer
+	err := c.Report(context.Background(), &out, "csv", first, first.AddDate(0, 0, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "export --format csv --from 2026-03-02 --to 2026-03-08\n" {
+		t.Errorf("ran %q", out.String())
+	}
+
+	err = c.Report(context.Background(), &out, "bogus", first, first)
+	if err == nil || !strings.Contains(err.Error(), `unknown format "bogus"`) {
+		t.Errorf("Report() = %v, expected the export error", err)
+	}
+}
+
+func TestControl(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "control.jsonl")
+	c := New("")
+	if err := c.Start("Acme"); err == nil {
+		t.Error("started without a control file")
+	}
+	c.Control = file
+	// sent before streaming, so left out
+	if err := c.Stop("Acme"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	received := make(chan Command)
+	go func() {
+		_ = StreamCommands(ctx, file, 5*time.Millisecond, func(cmd Command) error {
+			received <- cmd
+			return nil
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if err := c.Start("t1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Stop("Globex"); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []Command{{ActionStart, "t1"}, {ActionStop, "Globex"}} {
+		select {
+		case cmd := <-received:
+			if cmd != expected {
+				t.Errorf("streamed %+v, expected %+v", cmd, expected)
+			}
+		case <-ctx.Done():
+			t.Fatalf("%+v not streamed", expected)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package clocktest provides a simulated clock and an event recorder, to
+// test time dependent logic deterministically by advancing time.
+package clocktest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock"
+)
+
+// Fake is a clock only moving when told to. Timers and tickers fire, in
+// order, as time gets advanced past their deadline.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+	changed chan struct{}
+}
+
+// waiter is a pending timer, or a ticker when period is set.
+type waiter struct {
+	clock    *Fake
+	deadline time.Time
+	period   time.Duration
+	c        chan time.Time
+}
+
+var _ clock.Clock = (*Fake)(nil)
+
+// NewFake returns a fake clock set at start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{
+		now:     start,
+		changed: make(chan struct{}),
+	}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *Fake) NewTimer(d time.Duration) clock.Timer {
+	return timer{f.add(d, 0)}
+}
+
+func (f *Fake) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return ticker{f.add(d, d)}
+}
+
+func (f *Fake) add(d, period time.Duration) *waiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &waiter{
+		clock:    f,
+		deadline: f.now.Add(d),
+		period:   period,
+		c:        make(chan time.Time, 1),
+	}
+	f.schedule(w)
+	if d <= 0 {
+		f.fire()
+	}
+	return w
+}
+
+// schedule registers a waiter, the lock being held.
+func (f *Fake) schedule(w *waiter) {
+	f.waiters = append(f.waiters, w)
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+	})
+	f.notify()
+}
+
+// unschedule removes a waiter, the lock being held.
+func (f *Fake) unschedule(w *waiter) bool {
+	for i, o := range f.waiters {
+		if o == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.notify()
+			return true
+		}
+	}
+	return false
+}
+
+// notify wakes up BlockUntil callers, the lock being held.
+func (f *Fake) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// fire delivers the waiters due at the current time, the lock being held.
+func (f *Fake) fire() {
+	for len(f.waiters) > 0 && !f.waiters[0].deadline.After(f.now) {
+		w := f.waiters[0]
+		f.waiters = f.waiters[1:]
+		// like the time package, a tick is dropped when the previous
+		// one hasn't been consumed
+		select {
+		case w.c <- w.deadline:
+		default:
+		}
+		if w.period > 0 {
+			w.deadline = w.deadline.Add(w.period)
+			f.schedule(w)
+		}
+	}
+	f.notify()
+}
+
+// Advance moves the clock forward, firing the timers and tickers due in
+// between at their own deadline.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	end := f.now.Add(d)
+	for len(f.waiters) > 0 && !f.waiters[0].deadline.After(end) {
+		if f.waiters[0].deadline.After(f.now) {
+			f.now = f.waiters[0].deadline
+		}
+		f.fire()
+	}
+	if end.After(f.now) {
+		f.now = end
+	}
+}
+
+// Set moves the clock forward to t, see Advance.
+func (f *Fake) Set(t time.Time) {
+	f.Advance(t.Sub(f.Now()))
+}
+
+// Waiters returns the number of pending timers and tickers.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil waits for n timers and tickers to be pending, so that time
+// only gets advanced once the goroutines under test wait on the clock.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		count, changed := len(f.waiters), f.changed
+		f.mu.Unlock()
+		if count == n {
+			return
+		}
+		<-changed
+	}
+}
+
+func (w *waiter) stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.unschedule(w)
+}
+
+func (w *waiter) reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	active := w.clock.unschedule(w)
+	if w.period > 0 {
+		w.period = d
+	}
+	w.deadline = w.clock.now.Add(d)
+	w.clock.schedule(w)
+	if d <= 0 {
+		w.clock.fire()
+	}
+	return active
+}
+
+type timer struct {
+	w *waiter
+}
+
+func (t timer) C() <-chan time.Time {
+	return t.w.c
+}
+
+func (t timer) Stop() bool {
+	return t.w.stop()
+}
+
+func (t timer) Reset(d time.Duration) bool {
+	return t.w.reset(d)
+}
+
+type ticker struct {
+	w *waiter
+}
+
+func (t ticker) C() <-chan time.Time {
+	return t.w.c
+}
+
+func (t ticker) Stop() {
+	t.w.stop()
+}
+
+func (t ticker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.w.reset(d)
+}
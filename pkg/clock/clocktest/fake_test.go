@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package clocktest
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case at := <-c:
+		return at, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestAdvanceMovesTime(t *testing.T) {
+	f := NewFake(epoch)
+	f.Advance(90 * time.Minute)
+	if got, want := f.Now(), epoch.Add(90*time.Minute); !got.Equal(want) {
+		t.Fatalf("Now() = %v, want %v", got, want)
+	}
+	if got := f.Since(epoch); got != 90*time.Minute {
+		t.Fatalf("Since() = %v, want 90m", got)
+	}
+	f.Set(epoch.Add(2 * time.Hour))
+	if got, want := f.Now(), epoch.Add(2*time.Hour); !got.Equal(want) {
+		t.Fatalf("Set() moved to %v, want %v", got, want)
+	}
+}
+
+func TestTimerFiresAtDeadline(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(time.Minute)
+
+	f.Advance(59 * time.Second)
+	if _, ok := received(timer.C()); ok {
+		t.Fatal("timer fired before its deadline")
+	}
+
+	f.Advance(time.Hour)
+	at, ok := received(timer.C())
+	if !ok {
+		t.Fatal("timer didn't fire")
+	}
+	if want := epoch.Add(time.Minute); !at.Equal(want) {
+		t.Fatalf("timer fired at %v, want %v", at, want)
+	}
+	if f.Waiters() != 0 {
+		t.Fatalf("fired timer still pending")
+	}
+}
+
+func TestTimerStopAndReset(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(time.Minute)
+	if !timer.Stop() {
+		t.Fatal("Stop() of a pending timer returned false")
+	}
+	f.Advance(time.Hour)
+	if _, ok := received(timer.C()); ok {
+		t.Fatal("stopped timer fired")
+	}
+	if timer.Stop() {
+		t.Fatal("Stop() of a stopped timer returned true")
+	}
+
+	if timer.Reset(time.Minute) {
+		t.Fatal("Reset() of a stopped timer returned true")
+	}
+	f.Advance(time.Minute)
+	if at, ok := received(timer.C()); !ok || !at.Equal(epoch.Add(61*time.Minute)) {
+		t.Fatalf("reset timer fired at %v (%v)", at, ok)
+	}
+}
+
+func TestTickerFiresEachPeriod(t *testing.T) {
+	f := NewFake(epoch)
+	ticker := f.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for i := 1; i <= 3; i++ {
+		f.Advance(time.Minute)
+		at, ok := received(ticker.C())
+		if !ok {
+			t.Fatalf("tick %d missing", i)
+		}
+		if want := epoch.Add(time.Duration(i) * time.Minute); !at.Equal(want) {
+			t.Fatalf("tick %d at %v, want %v", i, at, want)
+		}
+	}
+
+	// unread ticks are dropped, as with the time package
+	f.Advance(10 * time.Minute)
+	if _, ok := received(ticker.C()); !ok {
+		t.Fatal("no tick after a long advance")
+	}
+	if _, ok := received(ticker.C()); ok {
+		t.Fatal("ticks queued up instead of being dropped")
+	}
+
+	ticker.Reset(time.Hour)
+	f.Advance(59 * time.Minute)
+	if _, ok := received(ticker.C()); ok {
+		t.Fatal("ticker ignored its new period")
+	}
+	f.Advance(time.Minute)
+	if _, ok := received(ticker.C()); !ok {
+		t.Fatal("ticker didn't fire with its new period")
+	}
+}
+
+func TestWaitersFireInOrder(t *testing.T) {
+	f := NewFake(epoch)
+	rec := NewRecorder[string]()
+	late := f.NewTimer(2 * time.Minute)
+	early := f.NewTimer(time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 2 {
+			select {
+			case <-early.C():
+				rec.Record("early")
+			case <-late.C():
+				rec.Record("late")
+			}
+		}
+	}()
+
+	f.Advance(time.Minute)
+	if _, ok := rec.Wait(1, time.Second); !ok {
+		t.Fatal("early timer not delivered")
+	}
+	f.Advance(time.Minute)
+	events, ok := rec.Wait(2, time.Second)
+	if !ok {
+		t.Fatal("late timer not delivered")
+	}
+	<-done
+	if events[0] != "early" || events[1] != "late" {
+		t.Fatalf("timers fired as %v", events)
+	}
+}
+
+func TestBlockUntil(t *testing.T) {
+	f := NewFake(epoch)
+	rec := NewRecorder[time.Time]()
+
+	go func() {
+		// a goroutine waiting on the clock, as the code under test would
+		rec.Record(<-f.After(time.Hour))
+	}()
+
+	f.BlockUntil(1)
+	f.Advance(time.Hour)
+	events, ok := rec.Wait(1, time.Second)
+	if !ok {
+		t.Fatal("After() channel never fired")
+	}
+	if want := epoch.Add(time.Hour); !events[0].Equal(want) {
+		t.Fatalf("After() fired at %v, want %v", events[0], want)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder[int]()
+	rec.Record(1)
+	rec.Record(2)
+	if rec.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", rec.Len())
+	}
+	events := rec.Events()
+	events[0] = 42
+	if rec.Events()[0] != 1 {
+		t.Fatal("Events() doesn't return a copy")
+	}
+	rec.Reset()
+	if rec.Len() != 0 {
+		t.Fatal("Reset() kept events")
+	}
+	if _, ok := rec.Wait(1, 10*time.Millisecond); ok {
+		t.Fatal("Wait() succeeded without events")
+	}
+}
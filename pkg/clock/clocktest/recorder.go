@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package clocktest
+
+import (
+	"sync"
+	"time"
+)
+
+// Recorder collects the events emitted by the code under test, possibly
+// from other goroutines.
+type Recorder[T any] struct {
+	mu      sync.Mutex
+	events  []T
+	changed chan struct{}
+}
+
+func NewRecorder[T any]() *Recorder[T] {
+	return &Recorder[T]{
+		changed: make(chan struct{}),
+	}
+}
+
+// Record appends an event, it fits as a callback or observer.
+func (r *Recorder[T]) Record(e T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// Events returns a copy of the events recorded so far.
+func (r *Recorder[T]) Events() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]T{}, r.events...)
+}
+
+func (r *Recorder[T]) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+// Reset forgets the events recorded so far.
+func (r *Recorder[T]) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// Wait blocks until at least n events got recorded, or the timeout
+// expires. The timeout is real time, guarding tests against deadlocks.
+func (r *Recorder[T]) Wait(n int, timeout time.Duration) ([]T, bool) {
+	deadline := time.After(timeout)
+	for {
+		r.mu.Lock()
+		count, changed := len(r.events), r.changed
+		r.mu.Unlock()
+		if count >= n {
+			return r.Events(), true
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			return r.Events(), false
+		}
+	}
+}
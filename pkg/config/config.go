@@ -0,0 +1,337 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package config layers settings from defaults, the configuration file,
+// environment variables and command line flags, each one overriding the
+// previous ones. Settings are addressed by the dotted path of their YAML
+// keys, e.g. sync.interval_minutes.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Setting documents a key settable from the environment and flags.
+type Setting struct {
+	Key string
+	// Default is applied when the setting is missing from the file.
+	Default string
+	Usage   string
+}
+
+type Schema []Setting
+
+func (s Schema) Lookup(key string) (Setting, bool) {
+	for _, setting := range s {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
+
+// Validator is implemented by settings checking their own consistency.
+type Validator interface {
+	Validate() error
+}
+
+// field returns the value at key in the struct v points to.
+func field(v any, key string) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("settings must be a pointer to a struct, not %T", v)
+	}
+	value = value.Elem()
+	for _, name := range strings.Split(key, ".") {
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown setting %q", key)
+		}
+		found := false
+		for idx := range value.NumField() {
+			if yamlName(value.Type().Field(idx)) == name {
+				value = value.Field(idx)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, fmt.Errorf("unknown setting %q", key)
+		}
+	}
+	return value, nil
+}
+
+func yamlName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
+
+// Set parses value into the setting at key, lists being comma separated.
+func Set(v any, key, value string) error {
+	f, err := field(v, key)
+	if err != nil {
+		return err
+	}
+	err = parse(f, strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return nil
+}
+
+func parse(f reflect.Value, value string) error {
+	if f.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return errors.Unwrap(err)
+		}
+		f.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, f.Type().Bits())
+		if err != nil {
+			return errors.Unwrap(err)
+		}
+		f.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(value, f.Type().Bits())
+		if err != nil {
+			return errors.Unwrap(err)
+		}
+		f.SetFloat(x)
+	case reflect.Slice:
+		items := reflect.MakeSlice(f.Type(), 0, 0)
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			elem := reflect.New(f.Type().Elem()).Elem()
+			if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Struct {
+				return errors.New("unsupported list")
+			}
+			err := parse(elem, item)
+			if err != nil {
+				return err
+			}
+			items = reflect.Append(items, elem)
+		}
+		f.Set(items)
+	default:
+		return fmt.Errorf("unsupported %s setting", f.Kind())
+	}
+	return nil
+}
+
+// Get formats the setting at key the way Set parses it.
+func Get(v any, key string) (string, error) {
+	f, err := field(v, key)
+	if err != nil {
+		return "", err
+	}
+	return format(f), nil
+}
+
+func format(f reflect.Value) string {
+	if f.Type() == durationType {
+		return time.Duration(f.Int()).String()
+	}
+	if f.Kind() == reflect.Slice {
+		items := []string{}
+		for idx := range f.Len() {
+			items = append(items, format(f.Index(idx)))
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(f.Interface())
+}
+
+// Copy sets the given keys of dst to their value in src, both being
+// pointers to the same struct type.
+func Copy(dst, src any, keys ...string) error {
+	for _, key := range keys {
+		to, err := field(dst, key)
+		if err != nil {
+			return err
+		}
+		from, err := field(src, key)
+		if err != nil {
+			return err
+		}
+		to.Set(from)
+	}
+	return nil
+}
+
+// Keys lists the dotted paths of the keys set in a YAML document, nested
+// ones included, e.g. sync and sync.interval_minutes.
+func Keys(doc []byte) ([]string, error) {
+	var root yaml.Node
+	err := yaml.Unmarshal(doc, &root)
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{}
+	var walk func(n *yaml.Node, prefix string)
+	walk = func(n *yaml.Node, prefix string) {
+		if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+			n = n.Content[0]
+		}
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for idx := 0; idx+1 < len(n.Content); idx += 2 {
+			key := prefix + n.Content[idx].Value
+			keys = append(keys, key)
+			walk(n.Content[idx+1], key+".")
+		}
+	}
+	walk(&root, "")
+	return keys, nil
+}
+
+// EnvName returns the environment variable overriding key, e.g.
+// CLOCKER_SYNC_INTERVAL_MINUTES for sync.interval_minutes.
+func EnvName(prefix, key string) string {
+	return prefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Loader applies defaults and overrides to settings read from the file.
+type Loader struct {
+	Schema Schema
+	// Prefix of the environment variables, e.g. CLOCKER_.
+	Prefix string
+	// Env defaults to the process environment.
+	Env []string
+	// Flags are key=value overrides given on the command line.
+	Flags Overrides
+	// Present are the keys set in the file, as listed by Keys. Defaults
+	// only apply to the others, so that settings can be emptied.
+	Present []string
+}
+
+// Apply fills v, decoded from the file, with the defaults of the settings
+// missing from it, then overrides them with environment variables and
+// flags, before validating the result. It returns the overridden keys, as
+// their values shouldn't be saved back to the file.
+func (l *Loader) Apply(v any) ([]string, error) {
+	errs := []error{}
+	for _, s := range l.Schema {
+		_, err := field(v, s.Key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if s.Default != "" && !slices.Contains(l.Present, s.Key) {
+			errs = append(errs, Set(v, s.Key, s.Default))
+		}
+	}
+
+	env := l.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	values := map[string]string{}
+	for _, e := range env {
+		name, value, _ := strings.Cut(e, "=")
+		values[name] = value
+	}
+	overridden := []string{}
+	for _, s := range l.Schema {
+		value, ok := values[EnvName(l.Prefix, s.Key)]
+		if !ok {
+			continue
+		}
+		err := Set(v, s.Key, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvName(l.Prefix, s.Key), err))
+			continue
+		}
+		overridden = append(overridden, s.Key)
+	}
+
+	for _, o := range l.Flags {
+		if _, ok := l.Schema.Lookup(o.Key); !ok {
+			errs = append(errs, fmt.Errorf("unknown setting %q", o.Key))
+			continue
+		}
+		err := Set(v, o.Key, o.Value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !slices.Contains(overridden, o.Key) {
+			overridden = append(overridden, o.Key)
+		}
+	}
+
+	if validator, ok := v.(Validator); ok {
+		errs = append(errs, validator.Validate())
+	}
+	return overridden, errors.Join(errs...)
+}
+
+type Override struct {
+	Key   string
+	Value string
+}
+
+// Overrides is a flag.Value gathering repeated key=value flags.
+type Overrides []Override
+
+func (o *Overrides) String() string {
+	settings := []string{}
+	for _, override := range *o {
+		settings = append(settings, override.Key+"="+override.Value)
+	}
+	return strings.Join(settings, " ")
+}
+
+func (o *Overrides) Set(s string) error {
+	key, value, found := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return fmt.Errorf("%q is not key=value", s)
+	}
+	*o = append(*o, Override{Key: key, Value: value})
+	return nil
+}
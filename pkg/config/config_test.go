@@ -0,0 +1,238 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock/clocktest"
+)
+
+type syncSettings struct {
+	Provider string        `yaml:"provider,omitempty"`
+	Every    time.Duration `yaml:"every,omitempty"`
+}
+
+type settings struct {
+	Currency string       `yaml:"currency,omitempty"`
+	Alerts   []int        `yaml:"budget_alerts,omitempty"`
+	Idle     int          `yaml:"idle_minutes,omitempty"`
+	Contrast bool         `yaml:"high_contrast,omitempty"`
+	Rate     float64      `yaml:"rate,omitempty"`
+	Sync     syncSettings `yaml:"sync,omitempty"`
+	Rates    map[string]float64
+	hidden   string
+}
+
+func (s *settings) Validate() error {
+	if s.Idle < 0 {
+		return errors.New("idle delay can't be negative")
+	}
+	return nil
+}
+
+var schema = Schema{
+	{Key: "currency", Default: "EUR"},
+	{Key: "budget_alerts", Default: "80,100"},
+	{Key: "idle_minutes"},
+	{Key: "high_contrast"},
+	{Key: "sync.provider"},
+	{Key: "sync.every", Default: "15m"},
+}
+
+func TestSetGet(t *testing.T) {
+	s := settings{}
+	for key, value := range map[string]string{
+		"currency":      "USD",
+		"budget_alerts": "50, 90,100",
+		"idle_minutes":  "5",
+		"high_contrast": "true",
+		"rate":          "80.5",
+		"sync.provider": "git",
+		"sync.every":    "1h30m",
+	} {
+		err := Set(&s, key, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := settings{Currency: "USD", Alerts: []int{50, 90, 100}, Idle: 5, Contrast: true, Rate: 80.5, Sync: syncSettings{"git", 90 * time.Minute}}
+	if s.Currency != expected.Currency || !slices.Equal(s.Alerts, expected.Alerts) || s.Idle != 5 || !s.Contrast || s.Rate != 80.5 || s.Sync != expected.Sync {
+		t.Errorf("settings = %+v", s)
+	}
+	if v, _ := Get(&s, "budget_alerts"); v != "50,90,100" {
+		t.Errorf("budget_alerts = %q", v)
+	}
+	if v, _ := Get(&s, "sync.every"); v != "1h30m0s" {
+		t.Errorf("sync.every = %q", v)
+	}
+
+	for key, value := range map[string]string{
+		"idle_minutes":   "five",
+		"high_contrast":  "maybe",
+		"unknown":        "1",
+		"currency.code":  "EUR",
+		"sync":           "git",
+		"rates":          "USD=1",
+		"hidden":         "x",
+		"sync.every":     "soon",
+		"budget_alerts.": "1",
+	} {
+		if err := Set(&s, key, value); err == nil {
+			t.Errorf("set %s to %q", key, value)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	s := settings{Idle: 10, Sync: syncSettings{Provider: "webdav"}}
+	var flags Overrides
+	fs := flag.NewFlagSet("clocker", flag.ContinueOnError)
+	fs.Var(&flags, "set", "")
+	err := fs.Parse([]string{"--set", "idle_minutes=3", "--set", "high_contrast=true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := Loader{
+		Schema: schema,
+		Prefix: "CLOCKER_",
+		Env:    []string{"CLOCKER_IDLE_MINUTES=20", "CLOCKER_SYNC_PROVIDER=git", "OTHER=1"},
+		Flags:  flags,
+		// emptied in the file
+		Present: []string{"budget_alerts", "idle_minutes", "sync", "sync.provider"},
+	}
+	overridden, err := l.Apply(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Currency != "EUR" || s.Sync.Every != 15*time.Minute {
+		t.Errorf("defaults not applied: %+v", s)
+	}
+	if len(s.Alerts) != 0 {
+		t.Errorf("budget alerts = %v, expected left empty as in the file", s.Alerts)
+	}
+	if s.Idle != 3 || s.Sync.Provider != "git" || !s.Contrast {
+		t.Errorf("flags should override the environment, overriding the file: %+v", s)
+	}
+	slices.Sort(overridden)
+	if strings.Join(overridden, " ") != "high_contrast idle_minutes sync.provider" {
+		t.Errorf("overridden = %v", overridden)
+	}
+
+	// the file values come back before saving
+	file := settings{Idle: 10, Sync: syncSettings{Provider: "webdav"}}
+	err = Copy(&s, &file, overridden...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Idle != 10 || s.Sync.Provider != "webdav" || s.Contrast || s.Currency != "EUR" {
+		t.Errorf("restored %+v", s)
+	}
+
+	l = Loader{Schema: schema, Env: []string{}, Flags: Overrides{{Key: "idle_minutes", Value: "-1"}, {Key: "colour", Value: "blue"}}}
+	_, err = l.Apply(&settings{})
+	if err == nil || !strings.Contains(err.Error(), "negative") || !strings.Contains(err.Error(), "colour") {
+		t.Errorf("Apply() = %v, expected both problems", err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys, err := Keys([]byte("currency: EUR\nbudget_alerts: []\nsync:\n  provider: git\ntrackers:\n  - id: a\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(keys, " ") != "currency budget_alerts sync sync.provider trackers" {
+		t.Errorf("keys = %v", keys)
+	}
+	if keys, err = Keys(nil); err != nil || len(keys) != 0 {
+		t.Errorf("empty document keys = %v, %v", keys, err)
+	}
+	if _, err = Keys([]byte("currency: [")); err == nil {
+		t.Error("listed the keys of an invalid document")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	var o Overrides
+	if err := o.Set("currency= USD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Set("currency"); err == nil {
+		t.Error("set an override without value")
+	}
+	if o.String() != "currency= USD" {
+		t.Errorf("overrides = %s", o.String())
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	file, other := filepath.Join(dir, "config"), filepath.Join(dir, "storage")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lock sync.Mutex
+	watched := []string{file}
+	files := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return slices.Clone(watched)
+	}
+	clk := clocktest.NewFake(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	changes := make(chan string, 10)
+	done := make(chan struct{})
+	go func() {
+		Watch(ctx, clk, WatchInterval, func(f string) { changes <- f }, files)
+		close(done)
+	}()
+	clk.BlockUntil(1)
+
+	expect := func(what, expected string) {
+		t.Helper()
+		clk.Advance(WatchInterval)
+		select {
+		case f := <-changes:
+			if f != expected {
+				t.Errorf("changed %s, expected %s", f, expected)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s not noticed", what)
+		}
+	}
+	_ = os.WriteFile(file, []byte("a: 1\n"), 0o600)
+	expect("creation", file)
+	_ = os.WriteFile(file+".tmp", []byte("a: 22\n"), 0o600)
+	_ = os.Rename(file+".tmp", file)
+	expect("replacement", file)
+
+	// settings moved to another file
+	lock.Lock()
+	watched = append(watched, other)
+	lock.Unlock()
+	_ = os.Remove(file)
+	expect("removal", file)
+	_ = os.WriteFile(other, []byte("a: 3\n"), 0o600)
+	expect("change of a file listed since", other)
+
+	clk.Advance(WatchInterval)
+	select {
+	case <-changes:
+		t.Error("noticed a change that didn't happen")
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock"
+)
+
+const (
+	WatchInterval = 2 * time.Second
+)
+
+type fileState struct {
+	modified time.Time
+	size     int64
+	exists   bool
+}
+
+func stat(file string) fileState {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modified: info.ModTime(), size: info.Size(), exists: true}
+}
+
+// Watch calls changed whenever one of the files gets written, created or
+// removed, until ctx is done. Files are polled, as a configuration file
+// replaced by a dotfile manager is often a new file or a symbolic link
+// that file system notifications would lose track of. They are listed
+// again after each change, which may have moved the settings elsewhere.
+func Watch(ctx context.Context, clk clock.Clock, interval time.Duration, changed func(file string), files func() []string) {
+	list := files()
+	states := watchStates(list, nil)
+	ticker := clk.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+		noticed := false
+		for _, file := range list {
+			state := stat(file)
+			if state != states[file] {
+				states[file] = state
+				changed(file)
+				noticed = true
+			}
+		}
+		if noticed {
+			list = files()
+			states = watchStates(list, states)
+		}
+	}
+}
+
+// watchStates are the states of the listed files, the known ones being
+// kept so that their changes are still noticed.
+func watchStates(files []string, known map[string]fileState) map[string]fileState {
+	states := map[string]fileState{}
+	for _, file := range files {
+		state, ok := known[file]
+		if !ok {
+			state = stat(file)
+		}
+		states[file] = state
+	}
+	return states
+}
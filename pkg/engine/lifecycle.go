@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package engine holds the tracker logic independent of any user
+// interface, starting with its lifecycle.
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock"
+)
+
+// State is a step of the tracker lifecycle. Idle and paused trackers start
+// running, running ones pause, all but archived ones reset to idle, and
+// idle or paused ones get archived until restored as paused.
+type State int
+
+const (
+	// Idle trackers have never run since created or reset.
+	Idle State = iota
+	Running
+	Paused
+	// Archived trackers are kept for the records, but can't run anymore.
+	Archived
+)
+
+var stateNames = []string{"idle", "running", "paused", "archived"}
+
+func (s State) String() string {
+	if s < 0 || int(s) >= len(stateNames) {
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+	return stateNames[s]
+}
+
+func (s State) MarshalText() ([]byte, error) {
+	if s < 0 || int(s) >= len(stateNames) {
+		return nil, fmt.Errorf("invalid tracker state %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *State) UnmarshalText(text []byte) error {
+	for i, name := range stateNames {
+		if name == string(text) {
+			*s = State(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid tracker state %q", text)
+}
+
+// Event drives the tracker from one state to another.
+type Event int
+
+const (
+	Start Event = iota
+	Pause
+	Reset
+	Archive
+	Restore
+)
+
+var eventNames = []string{"start", "pause", "reset", "archive", "restore"}
+
+func (e Event) String() string {
+	if e < 0 || int(e) >= len(eventNames) {
+		return fmt.Sprintf("Event(%d)", int(e))
+	}
+	return eventNames[e]
+}
+
+// States and Events list all values, in order.
+var (
+	States = []State{Idle, Running, Paused, Archived}
+	Events = []Event{Start, Pause, Reset, Archive, Restore}
+)
+
+var transitions = map[State]map[Event]State{
+	Idle: {
+		Start:   Running,
+		Reset:   Idle,
+		Archive: Archived,
+	},
+	Running: {
+		Pause: Paused,
+		Reset: Idle,
+	},
+	Paused: {
+		Start:   Running,
+		Reset:   Idle,
+		Archive: Archived,
+	},
+	Archived: {
+		Restore: Paused,
+	},
+}
+
+// TransitionError reports an event the current state doesn't accept.
+type TransitionError struct {
+	From  State
+	Event Event
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("can't %s a %s tracker", e.Event, e.From)
+}
+
+// Next returns the state reached when e happens in from.
+func Next(from State, e Event) (State, error) {
+	to, ok := transitions[from][e]
+	if !ok {
+		return from, &TransitionError{From: from, Event: e}
+	}
+	return to, nil
+}
+
+// Transition is a change of state, as notified to observers.
+type Transition struct {
+	From  State
+	To    State
+	Event Event
+	At    time.Time
+}
+
+// Lifecycle is the state machine of a tracker. The zero value is an idle
+// tracker following the system clock.
+type Lifecycle struct {
+	State   State     `yaml:"state,omitempty"`
+	Changed time.Time `yaml:"state_changed,omitempty"`
+
+	// Clock defaults to the system clock.
+	Clock clock.Clock `yaml:"-"`
+	// OnTransition is called after each successful transition.
+	OnTransition func(Transition) `yaml:"-"`
+}
+
+func (l *Lifecycle) now() time.Time {
+	if l.Clock == nil {
+		return clock.Real.Now()
+	}
+	return l.Clock.Now()
+}
+
+// Can tells whether e is accepted in the current state.
+func (l *Lifecycle) Can(e Event) bool {
+	_, err := Next(l.State, e)
+	return err == nil
+}
+
+// Fire applies e, leaving the state untouched when it isn't accepted.
+func (l *Lifecycle) Fire(e Event) error {
+	to, err := Next(l.State, e)
+	if err != nil {
+		return err
+	}
+	tr := Transition{From: l.State, To: to, Event: e, At: l.now()}
+	l.State = to
+	l.Changed = tr.At
+	if l.OnTransition != nil {
+		l.OnTransition(tr)
+	}
+	return nil
+}
+
+// Recover brings back a lifecycle loaded from storage after an
+// unexpected exit: a tracker can't still be running then.
+func (l *Lifecycle) Recover() {
+	if l.State == Running {
+		l.State = Paused
+	}
+	if l.State < Idle || l.State > Archived {
+		l.State = Idle
+	}
+}
+
+func (l *Lifecycle) Running() bool {
+	return l.State == Running
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gxben/clocker/pkg/clock/clocktest"
+)
+
+var epoch = time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+// expected lists every accepted transition, anything else must fail.
+var expected = map[State]map[Event]State{
+	Idle:     {Start: Running, Reset: Idle, Archive: Archived},
+	Running:  {Pause: Paused, Reset: Idle},
+	Paused:   {Start: Running, Reset: Idle, Archive: Archived},
+	Archived: {Restore: Paused},
+}
+
+func TestNextIsExhaustive(t *testing.T) {
+	for _, from := range States {
+		for _, e := range Events {
+			to, err := Next(from, e)
+			want, ok := expected[from][e]
+			switch {
+			case ok && err != nil:
+				t.Errorf("%s on %s: unexpected error %v", e, from, err)
+			case ok && to != want:
+				t.Errorf("%s on %s = %s, want %s", e, from, to, want)
+			case !ok && err == nil:
+				t.Errorf("%s on %s = %s, want an error", e, from, to)
+			case !ok && to != from:
+				t.Errorf("rejected %s on %s moved to %s", e, from, to)
+			}
+		}
+	}
+}
+
+func TestTransitionError(t *testing.T) {
+	_, err := Next(Running, Archive)
+	var terr *TransitionError
+	if !errors.As(err, &terr) {
+		t.Fatalf("Next() error = %v, want a TransitionError", err)
+	}
+	if terr.From != Running || terr.Event != Archive {
+		t.Fatalf("error = %+v", terr)
+	}
+	if got, want := err.Error(), "can't archive a running tracker"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestZeroLifecycleIsIdle(t *testing.T) {
+	var l Lifecycle
+	if l.State != Idle || l.Running() {
+		t.Fatalf("zero lifecycle is %s", l.State)
+	}
+	if !l.Can(Start) || l.Can(Pause) {
+		t.Fatal("zero lifecycle accepts the wrong events")
+	}
+}
+
+func TestFireNotifiesTransitions(t *testing.T) {
+	f := clocktest.NewFake(epoch)
+	rec := clocktest.NewRecorder[Transition]()
+	l := Lifecycle{Clock: f, OnTransition: rec.Record}
+
+	steps := []struct {
+		event Event
+		after time.Duration
+		to    State
+	}{
+		{Start, 0, Running},
+		{Pause, 25 * time.Minute, Paused},
+		{Start, 5 * time.Minute, Running},
+		{Reset, time.Hour, Idle},
+		{Archive, time.Minute, Archived},
+		{Restore, 24 * time.Hour, Paused},
+	}
+	for _, step := range steps {
+		f.Advance(step.after)
+		if err := l.Fire(step.event); err != nil {
+			t.Fatalf("Fire(%s): %v", step.event, err)
+		}
+		if l.State != step.to {
+			t.Fatalf("Fire(%s) reached %s, want %s", step.event, l.State, step.to)
+		}
+		if !l.Changed.Equal(f.Now()) {
+			t.Fatalf("Fire(%s) changed at %v, want %v", step.event, l.Changed, f.Now())
+		}
+	}
+
+	events := rec.Events()
+	if len(events) != len(steps) {
+		t.Fatalf("%d transitions notified, want %d", len(events), len(steps))
+	}
+	from := Idle
+	for i, tr := range events {
+		if tr.From != from || tr.To != steps[i].to || tr.Event != steps[i].event {
+			t.Errorf("transition %d = %+v", i, tr)
+		}
+		from = tr.To
+	}
+}
+
+func TestRejectedEventKeepsState(t *testing.T) {
+	f := clocktest.NewFake(epoch)
+	rec := clocktest.NewRecorder[Transition]()
+	l := Lifecycle{Clock: f, OnTransition: rec.Record}
+
+	// pausing a tracker which never started used to block, it must
+	// simply be refused
+	if err := l.Fire(Pause); err == nil {
+		t.Fatal("Pause on an idle tracker accepted")
+	}
+	if l.State != Idle || !l.Changed.IsZero() || rec.Len() != 0 {
+		t.Fatalf("rejected event changed the lifecycle: %+v", l)
+	}
+
+	_ = l.Fire(Start)
+	if err := l.Fire(Start); err == nil {
+		t.Fatal("Start on a running tracker accepted")
+	}
+	if err := l.Fire(Archive); err == nil {
+		t.Fatal("Archive on a running tracker accepted")
+	}
+	if l.State != Running || rec.Len() != 1 {
+		t.Fatalf("rejected events changed the lifecycle: %+v", l)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	for _, tc := range []struct {
+		from, want State
+	}{
+		{Idle, Idle},
+		{Running, Paused},
+		{Paused, Paused},
+		{Archived, Archived},
+		{State(42), Idle},
+	} {
+		l := Lifecycle{State: tc.from}
+		l.Recover()
+		if l.State != tc.want {
+			t.Errorf("Recover() from %s = %s, want %s", tc.from, l.State, tc.want)
+		}
+	}
+}
+
+func TestStateYAML(t *testing.T) {
+	type doc struct {
+		Lifecycle `yaml:",inline"`
+	}
+	for _, s := range States {
+		out, err := yaml.Marshal(doc{Lifecycle{State: s}})
+		if err != nil {
+			t.Fatalf("Marshal(%s): %v", s, err)
+		}
+		var in doc
+		if err := yaml.Unmarshal(out, &in); err != nil {
+			t.Fatalf("Unmarshal(%q): %v", out, err)
+		}
+		if in.State != s {
+			t.Errorf("%s round-tripped as %s through %q", s, in.State, out)
+		}
+	}
+
+	var in doc
+	if err := yaml.Unmarshal([]byte("state: sleeping\n"), &in); err == nil {
+		t.Fatal("unknown state accepted")
+	}
+}
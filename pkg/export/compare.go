@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// SteadyShare is the change, in percents of the base time, below which a
+// trend is considered steady.
+const SteadyShare = 5
+
+// Change compares the time spent on a tracker, or a client, over a period
+// with the one spent over a base period.
+type Change struct {
+	Name    string
+	Current time.Duration
+	Base    time.Duration
+}
+
+func (c Change) Delta() time.Duration {
+	return c.Current - c.Base
+}
+
+// Percent is the delta in percents of the base time, unknown without any.
+func (c Change) Percent() (float64, bool) {
+	if c.Base == 0 {
+		return 0, false
+	}
+	return 100 * float64(c.Delta()) / float64(c.Base), true
+}
+
+// Trend is an arrow telling whether the time went up, down, or stayed
+// about the same.
+func (c Change) Trend() string {
+	percent, ok := c.Percent()
+	switch {
+	case ok && percent > -SteadyShare && percent < SteadyShare, c.Delta() == 0:
+		return "→"
+	case c.Delta() > 0:
+		return "↑"
+	default:
+		return "↓"
+	}
+}
+
+// Compare sums up the entries of [from, to) and of [baseFrom, baseTo) per
+// group, e.g. the tracker, most time spent first.
+func Compare(entries []Entry, from, to, baseFrom, baseTo time.Time, group func(Entry) string) []Change {
+	changes := map[string]*Change{}
+	sum := func(from, to time.Time, current bool) {
+		for _, e := range Split(entries, from, to) {
+			name := group(e)
+			c, ok := changes[name]
+			if !ok {
+				c = &Change{Name: name}
+				changes[name] = c
+			}
+			if current {
+				c.Current += e.End.Sub(e.Start)
+			} else {
+				c.Base += e.End.Sub(e.Start)
+			}
+		}
+	}
+	sum(from, to, true)
+	sum(baseFrom, baseTo, false)
+
+	list := []Change{}
+	for _, c := range changes {
+		list = append(list, *c)
+	}
+	slices.SortFunc(list, func(a, b Change) int {
+		return cmp.Or(cmp.Compare(b.Current, a.Current), cmp.Compare(b.Base, a.Base), cmp.Compare(a.Name, b.Name))
+	})
+	return list
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package export writes tracked time in formats accepted by other tools.
+package export
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DateFormat = "2006-01-02"
+)
+
+// Entry is a span of time tracked against a tracker.
+type Entry struct {
+	Tracker  string
+	Receiver string
+	Issue    string
+	Start    time.Time
+	End      time.Time
+	Note     string
+}
+
+// Request describes what to export: the tracked entries within [From, To),
+// along with format-specific options.
+type Request struct {
+	Entries []Entry
+	From    time.Time
+	To      time.Time
+	SAP     SAPMapping
+	Tempo   TempoOptions
+	Standup StandupOptions
+}
+
+// Day truncates the time to the beginning of its calendar day, which is
+// not midnight where clocks go forward at that time.
+func Day(t time.Time) time.Time {
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	if _, _, d := midnight.Date(); d != day {
+		// midnight got normalized to the day before, the day begins with
+		// the change
+		midnight, _ = t.ZoneBounds()
+	}
+	return midnight
+}
+
+// AddDays returns the beginning of the calendar day n days after the one of
+// t. Unlike adding 24 hours per day, it stays on day boundaries across
+// daylight saving time changes, the days of which last 23 or 25 hours.
+func AddDays(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	return Day(time.Date(year, month, day+n, 12, 0, 0, 0, t.Location()))
+}
+
+// Days lists the calendar days in [from, to).
+func Days(from, to time.Time) []time.Time {
+	days := []time.Time{}
+	for d := Day(from); d.Before(to); d = AddDays(d, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+// Split cuts the entries at midnight so that each one of them belongs to a
+// single calendar day, and clips them to [from, to). Days are the ones of
+// the location of from.
+func Split(entries []Entry, from, to time.Time) []Entry {
+	split := []Entry{}
+	loc := from.Location()
+	for _, e := range entries {
+		start, end := e.Start.In(loc), e.End.In(loc)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		for start.Before(end) {
+			midnight := AddDays(start, 1)
+			stop := end
+			if midnight.Before(stop) {
+				stop = midnight
+			}
+			part := e
+			part.Start, part.End = start, stop
+			split = append(split, part)
+			start = stop
+		}
+	}
+	return split
+}
+
+// Daily sums up the time tracked per day and per tracker.
+func Daily(entries []Entry, from, to time.Time) map[time.Time]map[string]time.Duration {
+	daily := map[time.Time]map[string]time.Duration{}
+	for _, e := range Split(entries, from, to) {
+		day := Day(e.Start)
+		if daily[day] == nil {
+			daily[day] = map[string]time.Duration{}
+		}
+		daily[day][e.Tracker] += e.End.Sub(e.Start)
+	}
+	return daily
+}
+
+// sortedEntries returns a copy of the entries in chronological order.
+func sortedEntries(entries []Entry) []Entry {
+	sorted := slices.Clone(entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+	return sorted
+}
+
+// describe joins the distinct notes of the entries, falling back to their
+// distinct tracker labels when none has one.
+func describe(entries []Entry) string {
+	notes, labels := []string{}, []string{}
+	for _, e := range entries {
+		if note := strings.TrimSpace(e.Note); note != "" && !slices.Contains(notes, note) {
+			notes = append(notes, note)
+		}
+		if !slices.Contains(labels, e.Tracker) {
+			labels = append(labels, e.Tracker)
+		}
+	}
+	if len(notes) == 0 {
+		return strings.Join(labels, "; ")
+	}
+	return strings.Join(notes, "; ")
+}
+
+// Trackers returns the sorted list of trackers used by the entries.
+func Trackers(entries []Entry) []string {
+	seen := map[string]bool{}
+	trackers := []string{}
+	for _, e := range entries {
+		if !seen[e.Tracker] {
+			seen[e.Tracker] = true
+			trackers = append(trackers, e.Tracker)
+		}
+	}
+	sort.Strings(trackers)
+	return trackers
+}
@@ -0,0 +1,541 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	// daylight saving time rules, missing from some systems
+	_ "time/tzdata"
+
+	"github.com/gxben/clocker/pkg/assets"
+)
+
+// benchRequest covers a year of daily sessions on a dozen trackers.
+func benchRequest() Request {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	r := Request{From: from, To: from.AddDate(1, 0, 0)}
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 1000; j++ {
+			start := from.Add(time.Duration(j)*8*time.Hour + time.Duration(i)*10*time.Minute)
+			r.Entries = append(r.Entries, Entry{
+				Tracker:  fmt.Sprintf("Project %d", i),
+				Receiver: fmt.Sprintf("WBS-%04d", i),
+				Issue:    fmt.Sprintf("PROJ-%d", i),
+				Start:    start,
+				End:      start.Add(2 * time.Hour),
+			})
+		}
+	}
+	return r
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		file   string
+		format string
+	}{
+		{"timesheet-2026-W10.csv", "grid"},
+		{"TIMESHEET.CSV", "grid"},
+		{"timesheet.daily.csv", "daily"},
+		{"upload.cats.csv", "sap"},
+		{"upload.sap.csv", "sap"},
+		{"worklogs.tempo.csv", "tempo-csv"},
+		{"worklogs.tempo.json", "tempo-json"},
+		{"worklogs.json", "tempo-json"},
+		{"yesterday.standup.txt", "standup"},
+		{"worklog.md", "markdown"},
+		{"timesheet.pdf", ""},
+	}
+	for _, tt := range tests {
+		f, ok := Detect(tt.file)
+		if ok != (tt.format != "") || f.Name != tt.format {
+			t.Errorf("Detect(%q) = %q, %v, expected %q", tt.file, f.Name, ok, tt.format)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, f := range Formats() {
+		if found, ok := Lookup(f.Name); !ok || found.Title != f.Title {
+			t.Errorf("Lookup(%q) = %q, %v", f.Name, found.Title, ok)
+		}
+		if found, ok := Lookup(f.Title); !ok || found.Name != f.Name {
+			t.Errorf("Lookup(%q) = %q, %v", f.Title, found.Name, ok)
+		}
+		if detected, ok := Detect("file" + f.Extension()); !ok || detected.Name != f.Name {
+			t.Errorf("exported %s files detected as %q", f.Name, detected.Name)
+		}
+	}
+	if _, ok := Lookup("pdf"); ok {
+		t.Error("found an unregistered format")
+	}
+}
+
+func BenchmarkFormats(b *testing.B) {
+	r := benchRequest()
+	for _, f := range Formats() {
+		b.Run(f.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err := f.Export(io.Discard, r)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSplit(b *testing.B) {
+	r := benchRequest()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Split(r.Entries, r.From, r.To)
+	}
+}
+
+func TestStandup(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	r := Request{From: day, To: day.AddDate(0, 0, 1), Entries: []Entry{
+		{Tracker: "Globex", Start: at(8, 0), End: at(8, 45)},
+		{Tracker: "Acme", Issue: "ACME-1", Start: at(9, 0), End: at(11, 0), Note: "Fixed the login"},
+		{Tracker: "Acme", Issue: "ACME-1", Start: at(14, 0), End: at(14, 30), Note: "Reviewed PRs"},
+		// the day before is left out
+		{Tracker: "Initech", Start: at(-2, 0), End: at(-1, 0)},
+	}}
+
+	var out bytes.Buffer
+	err := Standup(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "*Monday 02 Mar* (3h15m)\n" +
+		"• Acme [ACME-1]: 2h30m\n" +
+		"    ◦ Fixed the login\n" +
+		"    ◦ Reviewed PRs\n" +
+		"• Globex: 45m\n"
+	if out.String() != expected {
+		t.Errorf("Standup() =\n%s\nexpected\n%s", out.String(), expected)
+	}
+
+	r.Standup.Template = "{{range .Days}}{{range .Trackers}}{{.Tracker}} {{duration .Duration}};{{end}}{{end}}"
+	out.Reset()
+	err = Standup(&out, r)
+	if err != nil || out.String() != "Acme 2h30m;Globex 45m;" {
+		t.Errorf("Standup() with template = %q, %v", out.String(), err)
+	}
+
+	r.Standup.Template = "{{.Nope"
+	if err := Standup(io.Discard, r); err == nil {
+		t.Error("accepted an invalid template")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	r := Request{From: day, To: day.AddDate(0, 0, 7), Entries: []Entry{
+		{Tracker: "Acme", Issue: "ACME-1", Start: at(9, 0), End: at(11, 30), Note: "Login | signup"},
+		{Tracker: "Globex", Start: at(32, 0), End: at(32, 45)},
+	}}
+
+	var out bytes.Buffer
+	err := Markdown(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# Time report 2026-03-02 to 2026-03-08",
+		"| Acme | ACME-1 | 2h30m | 2.50 |",
+		"| Globex |  | 45m | 0.75 |",
+		"| **Total** |  | **3h15m** | **3.25** |",
+		"| Tue 2026-03-03 | 45m | 0.75 |",
+		`| 2026-03-02 | Acme | 09:00 | 11:30 | 2h30m | Login \| signup |`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("report misses %q:\n%s", line, out.String())
+		}
+	}
+
+	out.Reset()
+	r.Entries = nil
+	_ = Markdown(&out, r)
+	if !strings.Contains(out.String(), "Nothing tracked.") {
+		t.Errorf("empty report:\n%s", out.String())
+	}
+}
+
+func TestHTML(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	r := Request{From: day, To: day.AddDate(0, 0, 7), Entries: []Entry{
+		{Tracker: "Acme <Corp>", Start: day.Add(9 * time.Hour), End: day.Add(12 * time.Hour)},
+		{Tracker: "Globex", Start: day.Add(33 * time.Hour), End: day.Add(34 * time.Hour), Note: "Review"},
+	}}
+
+	var out bytes.Buffer
+	err := HTML(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := out.String()
+	for _, s := range []string{
+		"<title>Time report 2026-03-02 to 2026-03-08</title>",
+		"<td>Acme &lt;Corp&gt;</td>",
+		`<td class="num">75%</td>`,
+		`width="400" height="16"`,
+		`y="4" width="32" height="120"`,
+		"<td>Review</td>",
+	} {
+		if !strings.Contains(report, s) {
+			t.Errorf("report misses %q:\n%s", s, report)
+		}
+	}
+	if strings.Contains(report, "<Corp>") {
+		t.Error("tracker label not escaped")
+	}
+
+	out.Reset()
+	r.Entries = nil
+	_ = HTML(&out, r)
+	if !strings.Contains(out.String(), "Nothing tracked.") {
+		t.Errorf("empty report:\n%s", out.String())
+	}
+}
+
+func timesheetRequest() Request {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	return Request{From: day, To: day.AddDate(0, 0, 7), Entries: []Entry{
+		{Tracker: "Acme", Start: day.Add(9 * time.Hour), End: day.Add(11*time.Hour + 30*time.Minute)},
+		{Tracker: "Globex", Start: day.Add(32 * time.Hour), End: day.Add(32*time.Hour + 45*time.Minute)},
+		{Tracker: "Acme", Start: day.Add(58 * time.Hour), End: day.Add(59 * time.Hour)},
+	}}
+}
+
+func TestWeeklyGrid(t *testing.T) {
+	var out bytes.Buffer
+	err := WeeklyGrid(&out, timesheetRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Tracker,Mon 2026-03-02,Tue 2026-03-03,Wed 2026-03-04,Thu 2026-03-05,Fri 2026-03-06,Sat 2026-03-07,Sun 2026-03-08,Total
+Acme,2.50,0.00,1.00,0.00,0.00,0.00,0.00,3.50
+Globex,0.00,0.75,0.00,0.00,0.00,0.00,0.00,0.75
+Total,2.50,0.75,1.00,0.00,0.00,0.00,0.00,4.25
+`
+	if out.String() != expected {
+		t.Errorf("grid:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestDailyHours(t *testing.T) {
+	var out bytes.Buffer
+	err := DailyHours(&out, timesheetRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Date,Tracker,Hours
+2026-03-02,Acme,2.50
+2026-03-03,Globex,0.75
+2026-03-04,Acme,1.00
+`
+	if out.String() != expected {
+		t.Errorf("daily hours:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestTimesheetDaylightSaving(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 4 hours over the night clocks go forward
+	monday := time.Date(2026, 3, 23, 0, 0, 0, 0, paris)
+	r := Request{From: monday, To: AddDays(monday, 7), Entries: []Entry{
+		{Tracker: "Acme", Start: time.Date(2026, 3, 28, 23, 0, 0, 0, paris), End: time.Date(2026, 3, 29, 4, 0, 0, 0, paris)},
+	}}
+
+	var out bytes.Buffer
+	err = WeeklyGrid(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Tracker,Mon 2026-03-23,Tue 2026-03-24,Wed 2026-03-25,Thu 2026-03-26,Fri 2026-03-27,Sat 2026-03-28,Sun 2026-03-29,Total
+Acme,0.00,0.00,0.00,0.00,0.00,1.00,3.00,4.00
+Total,0.00,0.00,0.00,0.00,0.00,1.00,3.00,4.00
+`
+	if out.String() != expected {
+		t.Errorf("grid:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	out.Reset()
+	err = DailyHours(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `Date,Tracker,Hours
+2026-03-28,Acme,1.00
+2026-03-29,Acme,3.00
+`
+	if out.String() != expected {
+		t.Errorf("daily hours:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestSAPCATS(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hours float64) time.Time {
+		return day.Add(time.Duration(hours * float64(time.Hour)))
+	}
+	r := Request{From: day, To: day.AddDate(0, 0, 7), SAP: SAPMapping{PersonnelNumber: "00001234"}, Entries: []Entry{
+		{Tracker: "Acme", Receiver: "WBS-1", Start: at(14), End: at(15), Note: "Review"},
+		{Tracker: "Acme", Receiver: "WBS-1", Start: at(9), End: at(11), Note: "Design"},
+		// another tracker with the same label
+		{Tracker: "Acme", Receiver: "WBS-2", Start: at(11), End: at(11.5)},
+		{Tracker: "Globex", Receiver: "WBS-3", Start: at(33), End: at(34)},
+	}}
+
+	var out bytes.Buffer
+	err := SAPCATS(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `PERNR,WORKDATE,RPROJ,CATSHOURS,LTXA1
+00001234,20260302,WBS-1,3.00,Design; Review
+00001234,20260302,WBS-2,0.50,Acme
+00001234,20260303,WBS-3,1.00,Globex
+`
+	if out.String() != expected {
+		t.Errorf("CATS file:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	r.SAP = SAPMapping{ActivityType: "1000", DateFormat: "02.01.2006", Columns: []SAPColumn{
+		{Header: "WORKDATE", Field: SAPFieldDate},
+		{Header: "CATSHOURS", Field: SAPFieldHours},
+		{Header: "LSTAR", Field: SAPFieldActivity},
+		{Header: "RAUFNR", Field: SAPFieldReceiver},
+	}}
+	out.Reset()
+	err = SAPCATS(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(out.String(), "\n"); lines[0] != "WORKDATE,CATSHOURS,LSTAR,RAUFNR" || lines[1] != "02.03.2026,3.00,1000,WBS-1" {
+		t.Errorf("mapped CATS file:\n%s", out.String())
+	}
+}
+
+func TestTempoCSV(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(hours float64) time.Time {
+		return day.Add(time.Duration(hours * float64(time.Hour)))
+	}
+	r := Request{From: day, To: day.AddDate(0, 0, 7), Tempo: TempoOptions{AccountID: "5b10ac8d"}, Entries: []Entry{
+		{Tracker: "Acme", Issue: "ACME-1", Start: at(14), End: at(15), Note: "Review"},
+		{Tracker: "Acme", Issue: "ACME-1", Start: at(9), End: at(10.5), Note: "Login form"},
+		{Tracker: "Acme", Issue: "ACME-1", Start: at(16), End: at(16.25), Note: "Review"},
+		// split at midnight
+		{Tracker: "Globex", Issue: "GLX-7", Start: at(23), End: at(25.5)},
+		{Tracker: "Initech", Start: at(9), End: at(10)},
+	}}
+
+	var out bytes.Buffer
+	err := TempoCSV(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Issue Key,Start Date,Start Time,Time Spent (seconds),Description,Account ID
+ACME-1,2026-03-02,09:00:00,9900,Login form; Review,5b10ac8d
+GLX-7,2026-03-02,23:00:00,3600,Globex,5b10ac8d
+GLX-7,2026-03-03,00:00:00,5400,Globex,5b10ac8d
+`
+	if out.String() != expected {
+		t.Errorf("worklogs:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestTemplateOverride(t *testing.T) {
+	dir := t.TempDir()
+	err := os.Mkdir(filepath.Join(dir, "templates"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "templates", "report.md"), []byte("Total {{duration .Total}}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(fsys fs.FS) { Assets = fsys }(Assets)
+	Assets = assets.New(dir)
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	r := Request{From: day, To: day.AddDate(0, 0, 7), Entries: []Entry{
+		{Tracker: "Acme", Start: day.Add(9 * time.Hour), End: day.Add(11 * time.Hour)},
+	}}
+	var out bytes.Buffer
+	if err = Markdown(&out, r); err != nil || out.String() != "Total 2h\n" {
+		t.Errorf("overridden report %q: %v", out.String(), err)
+	}
+	// the other templates are still the bundled ones
+	out.Reset()
+	if err = HTML(&out, r); err != nil || !strings.Contains(out.String(), "<h1>Time report</h1>") {
+		t.Errorf("bundled report %q: %v", out.String(), err)
+	}
+}
+
+func TestYearReview(t *testing.T) {
+	year := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	day := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.Local)
+	}
+	r := Request{From: year, To: year.AddDate(1, 0, 0), Entries: []Entry{
+		{Tracker: "Acme", Issue: "ACME-1", Start: day(3, 2, 9), End: day(3, 2, 12)},
+		{Tracker: "Acme", Start: day(3, 3, 9), End: day(3, 3, 10)},
+		{Tracker: "Globex", Start: day(3, 4, 9), End: day(3, 4, 17)},
+		{Tracker: "Acme", Start: day(7, 14, 9), End: day(7, 14, 13)},
+		// out of the year
+		{Tracker: "Initech", Start: day(1, 1, 0).Add(-time.Hour), End: day(1, 1, 0)},
+	}}
+
+	review := YearReview(r)
+	if review.Title() != "Year in review 2026" || review.Total != 16*time.Hour || review.Days != 4 {
+		t.Fatalf("review %q: %s over %d days, expected 16h over 4 days", review.Title(), review.Total, review.Days)
+	}
+	if len(review.Trackers) != 2 || review.Trackers[0].Tracker != "Acme" || review.Trackers[0].Issue != "ACME-1" || review.Trackers[1].Share != 50 {
+		t.Errorf("trackers %+v", review.Trackers)
+	}
+	if len(review.Months) != 12 || review.Months[2].Duration != 12*time.Hour || review.Months[6].Duration != 4*time.Hour || review.Months[0].Duration != 0 {
+		t.Errorf("months %+v", review.Months)
+	}
+	if !review.BusiestDay.Equal(day(3, 4, 0)) || review.BusiestTotal != 8*time.Hour {
+		t.Errorf("busiest day %s with %s", review.BusiestDay, review.BusiestTotal)
+	}
+	if review.Streak != 3 || !review.StreakStart.Equal(day(3, 2, 0)) {
+		t.Errorf("streak of %d days from %s, expected 3 from 2026-03-02", review.Streak, review.StreakStart)
+	}
+
+	var out bytes.Buffer
+	err := ReviewHTML(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"<title>Year in review 2026</title>", "<b>16.00</b>hours tracked", "<b>3</b>days in a row from 02 March", "<td>Globex</td>"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("HTML review misses %q:\n%s", s, out.String())
+		}
+	}
+	out.Reset()
+	err = ReviewPDF(&out, r)
+	if err != nil || !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Errorf("PDF review not written: %v", err)
+	}
+
+	r.From = day(3, 1, 0)
+	r.To = day(4, 1, 0)
+	if review := YearReview(r); review.Title() != "Review 2026-03-01 to 2026-03-31" || len(review.Months) != 1 {
+		t.Errorf("review of a month %q over %d months", review.Title(), len(review.Months))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	week := time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)
+	base := week.AddDate(0, 0, -7)
+	at := func(day time.Time, hours int) (time.Time, time.Time) {
+		return day.Add(9 * time.Hour), day.Add(time.Duration(9+hours) * time.Hour)
+	}
+	entries := []Entry{}
+	add := func(tracker string, day time.Time, hours int) {
+		start, end := at(day, hours)
+		entries = append(entries, Entry{Tracker: tracker, Start: start, End: end})
+	}
+	add("Acme", week, 5)
+	add("Acme", base, 4)
+	add("Globex", week, 2)
+	add("Globex", base, 2)
+	add("Initech", base, 3)
+
+	changes := Compare(entries, week, week.AddDate(0, 0, 7), base, week, func(e Entry) string { return e.Tracker })
+	if len(changes) != 3 || changes[0].Name != "Acme" || changes[1].Name != "Globex" || changes[2].Name != "Initech" {
+		t.Fatalf("changes %+v, expected Acme, Globex, Initech", changes)
+	}
+	acme, globex, initech := changes[0], changes[1], changes[2]
+	if percent, ok := acme.Percent(); acme.Delta() != time.Hour || !ok || percent != 25 || acme.Trend() != "↑" {
+		t.Errorf("Acme changed by %s (%.0f%%) %s, expected +1h +25%% ↑", acme.Delta(), percent, acme.Trend())
+	}
+	if globex.Trend() != "→" || initech.Trend() != "↓" || initech.Current != 0 {
+		t.Errorf("trends Globex %s, Initech %s", globex.Trend(), initech.Trend())
+	}
+	if _, ok := (Change{Current: time.Hour}).Percent(); ok {
+		t.Error("percentage of a change from nothing")
+	}
+}
+
+func TestDaylightSaving(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 5 hours on the wall clock, 4 hours spring forward, 6 hours fall back
+	spring := Entry{Tracker: "Acme", Start: time.Date(2026, 3, 28, 23, 0, 0, 0, paris), End: time.Date(2026, 3, 29, 4, 0, 0, 0, paris)}
+	fall := Entry{Tracker: "Acme", Start: time.Date(2026, 10, 24, 23, 0, 0, 0, paris), End: time.Date(2026, 10, 25, 4, 0, 0, 0, paris)}
+	for _, c := range []struct {
+		entry      Entry
+		day, after time.Duration
+	}{
+		{spring, time.Hour, 3 * time.Hour},
+		{fall, time.Hour, 5 * time.Hour},
+	} {
+		from := Day(c.entry.Start)
+		to := AddDays(from, 7)
+		daily := Daily([]Entry{c.entry}, from, to)
+		next := AddDays(from, 1)
+		if next.Hour() != 0 || daily[from]["Acme"] != c.day || daily[next]["Acme"] != c.after {
+			t.Errorf("%s split as %+v", c.entry.Start, daily)
+		}
+		if days := Days(from, to); len(days) != 7 || !days[1].Equal(next) {
+			t.Errorf("days from %s: %v", from, days)
+		}
+	}
+	long := Day(fall.End)
+	if AddDays(long, 1).Sub(long) != 25*time.Hour {
+		t.Errorf("fall back day lasts %s", AddDays(long, 1).Sub(long))
+	}
+
+	// clocks go forward at midnight, the day starts at 01:00
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saturday := time.Date(2026, 9, 5, 0, 0, 0, 0, santiago)
+	sunday := AddDays(saturday, 1)
+	if y, m, d := sunday.Date(); y != 2026 || m != time.September || d != 6 || sunday.Hour() != 1 || sunday.Sub(saturday) != 24*time.Hour {
+		t.Fatalf("day after %s is %s", saturday, sunday)
+	}
+	if !Day(sunday.Add(10 * time.Hour)).Equal(sunday) {
+		t.Errorf("sunday starts at %s", Day(sunday.Add(10*time.Hour)))
+	}
+	e := Entry{Tracker: "Acme", Start: saturday.Add(22 * time.Hour), End: sunday.Add(2 * time.Hour)}
+	daily := Daily([]Entry{e}, saturday, AddDays(saturday, 2))
+	if daily[saturday]["Acme"] != 2*time.Hour || daily[sunday]["Acme"] != 2*time.Hour {
+		t.Errorf("session over the change split as %+v", daily)
+	}
+	if days := Days(saturday, AddDays(saturday, 3)); len(days) != 3 || days[1].Format(DateFormat) != "2026-09-06" {
+		t.Errorf("days %v", days)
+	}
+}
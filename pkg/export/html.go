@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+func init() {
+	Register(Format{
+		Name:       "html",
+		Title:      "Report (HTML)",
+		Extensions: []string{".html", ".htm"},
+		Exporter:   ExporterFunc(HTML),
+	})
+}
+
+// chartHeight is the height in pixels of the tallest bar of the charts.
+const chartHeight = 120
+
+// htmlStyle is shared by the HTML reports.
+const htmlStyle = `body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #222; max-width: 60em; margin: 2em auto; padding: 0 1em; }
+h1 { font-weight: 500; margin-bottom: 0; }
+.total { color: #666; margin-top: .3em; }
+h2 { font-weight: 500; border-bottom: 1px solid #ddd; padding-bottom: .2em; margin-top: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: .35em .6em; text-align: left; border-bottom: 1px solid #eee; }
+th { background: #f6f6f6; font-weight: 600; }
+td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+tfoot td { font-weight: 600; border-top: 2px solid #ccc; }
+svg { display: block; margin: 1em 0; }
+svg text { font-size: 12px; fill: #444; }
+.bar { fill: #3f7fbf; }
+`
+
+// htmlBar is a row of the report, drawn as a bar whose size is relative to
+// the largest one of its chart.
+type htmlBar struct {
+	Label    string
+	Issue    string
+	Day      time.Time
+	Duration time.Duration
+	Share    float64
+	Size     int
+}
+
+type htmlReport struct {
+	From     time.Time
+	Last     time.Time
+	Total    time.Duration
+	Trackers []htmlBar
+	Days     []htmlBar
+	Sessions []Entry
+}
+
+// bars sets the size of each bar, out of size pixels, and its share of the
+// total.
+func bars(list []htmlBar, total time.Duration, size int) {
+	longest := time.Duration(0)
+	for _, b := range list {
+		longest = max(longest, b.Duration)
+	}
+	for i := range list {
+		list[i].Share = 100 * float64(list[i].Duration) / float64(total)
+		list[i].Size = int(int64(size) * int64(list[i].Duration) / int64(longest))
+	}
+}
+
+// htmlFuncs are the functions of the HTML report templates.
+var htmlFuncs = template.FuncMap{
+	"style":    func() template.CSS { return template.CSS(htmlStyle) },
+	"duration": duration,
+	"hours":    hours,
+	"date":     func(t time.Time) string { return t.Format(DateFormat) },
+	"clock":    func(t time.Time) string { return t.Format("15:04") },
+	"elapsed":  func(from, to time.Time) time.Duration { return to.Sub(from) },
+	"add":      func(a, b int) int { return a + b },
+	"sub":      func(a, b int) int { return a - b },
+	"mul":      func(a, b int) int { return a * b },
+	"height":   func() int { return chartHeight },
+}
+
+// newReport sums the entries of the period per tracker and per day.
+func newReport(r Request) htmlReport {
+	entries := Split(r.Entries, r.From, r.To)
+	report := htmlReport{From: r.From, Last: r.To.AddDate(0, 0, -1), Sessions: sortedEntries(entries)}
+
+	perTracker := map[string]time.Duration{}
+	issues := map[string]string{}
+	for _, e := range entries {
+		perTracker[e.Tracker] += e.End.Sub(e.Start)
+		if e.Issue != "" {
+			issues[e.Tracker] = e.Issue
+		}
+		report.Total += e.End.Sub(e.Start)
+	}
+	if report.Total == 0 {
+		return report
+	}
+	for _, tracker := range Trackers(entries) {
+		report.Trackers = append(report.Trackers, htmlBar{Label: tracker, Issue: issues[tracker], Duration: perTracker[tracker]})
+	}
+
+	daily := Daily(entries, r.From, r.To)
+	for _, day := range Days(r.From, r.To) {
+		sum := time.Duration(0)
+		for _, d := range daily[day] {
+			sum += d
+		}
+		report.Days = append(report.Days, htmlBar{Label: day.Format("Mon " + DateFormat), Day: day, Duration: sum})
+	}
+	return report
+}
+
+// HTML writes a standalone report of the period, styled and with its charts
+// drawn inline, so that it can be mailed as a single file.
+func HTML(w io.Writer, r Request) error {
+	tmpl, err := htmlTemplate("report.html")
+	if err != nil {
+		return err
+	}
+	report := newReport(r)
+	if report.Total > 0 {
+		bars(report.Trackers, report.Total, 400)
+		bars(report.Days, report.Total, chartHeight)
+	}
+	return tmpl.Execute(w, report)
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"io"
+	"strings"
+	textTemplate "text/template"
+	"time"
+)
+
+func init() {
+	Register(Format{
+		Name:       "markdown",
+		Title:      "Report (Markdown)",
+		Extensions: []string{".md", ".markdown"},
+		Exporter:   ExporterFunc(Markdown),
+	})
+}
+
+var cellReplacer = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+// cell escapes text for a table cell.
+func cell(s string) string {
+	return cellReplacer.Replace(s)
+}
+
+// markdownFuncs are the functions of the Markdown report template.
+var markdownFuncs = textTemplate.FuncMap{
+	"cell":     cell,
+	"duration": duration,
+	"hours":    hours,
+	"date":     func(t time.Time) string { return t.Format(DateFormat) },
+	"clock":    func(t time.Time) string { return t.Format("15:04") },
+	"elapsed":  func(from, to time.Time) time.Duration { return to.Sub(from) },
+}
+
+// Markdown writes a report of the period fit for wikis and notes: totals
+// per tracker and per day, then the sessions.
+func Markdown(w io.Writer, r Request) error {
+	tmpl, err := textTemplate.New("report.md").Funcs(markdownFuncs).ParseFS(Assets, "templates/report.md")
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, newReport(r))
+}
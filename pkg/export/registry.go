@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"io"
+
+	"github.com/gxben/clocker/pkg/registry"
+)
+
+// Exporter writes the requested entries in a given format.
+type Exporter interface {
+	Export(w io.Writer, r Request) error
+}
+
+// ExporterFunc turns a plain function into an Exporter.
+type ExporterFunc func(w io.Writer, r Request) error
+
+func (f ExporterFunc) Export(w io.Writer, r Request) error {
+	return f(w, r)
+}
+
+// Format is a registered export format.
+type Format struct {
+	// Name selects the format on the command line, e.g. tempo-json.
+	Name string
+	// Title is shown in the export dialog.
+	Title string
+	// Extensions recognize the format from a file name, most specific
+	// first, the first one naming exported files.
+	Extensions []string
+	Exporter
+}
+
+// Extension is the one exported files are named with.
+func (f Format) Extension() string {
+	return f.Extensions[0]
+}
+
+func (f Format) info() registry.Info {
+	return registry.Info{Name: f.Name, Title: f.Title, Extensions: f.Extensions}
+}
+
+var formats = registry.New("export", Format.info)
+
+// Register makes a format available, each format file registering its own
+// from init.
+func Register(f Format) {
+	formats.Register(f)
+}
+
+// Formats lists the registered formats, sorted by title.
+func Formats() []Format {
+	return formats.List()
+}
+
+// Titles lists the titles of the registered formats, sorted.
+func Titles() []string {
+	titles := []string{}
+	for _, f := range Formats() {
+		titles = append(titles, f.Title)
+	}
+	return titles
+}
+
+// Lookup finds a format by name or title.
+func Lookup(name string) (Format, bool) {
+	return formats.Lookup(name)
+}
+
+// Detect finds the format of a file from its name, the longest matching
+// extension winning, so that week.tempo.json is told apart from week.json.
+func Detect(file string) (Format, bool) {
+	return formats.Detect(file)
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+func init() {
+	Register(Format{
+		Name:       "review-html",
+		Title:      "Year in review (HTML)",
+		Extensions: []string{".review.html"},
+		Exporter:   ExporterFunc(ReviewHTML),
+	})
+	Register(Format{
+		Name:       "review-pdf",
+		Title:      "Year in review (PDF)",
+		Extensions: []string{".review.pdf"},
+		Exporter:   ExporterFunc(ReviewPDF),
+	})
+}
+
+// ReviewItem is the time spent on a tracker over the reviewed period.
+type ReviewItem struct {
+	Tracker  string
+	Issue    string
+	Duration time.Duration
+	// Share is the percentage of the total.
+	Share float64
+}
+
+type ReviewMonth struct {
+	Month    time.Time
+	Duration time.Duration
+}
+
+// Review sums up the tracked time of [From, To), usually a year.
+type Review struct {
+	From  time.Time
+	To    time.Time
+	Total time.Duration
+	// Days is the number of days with tracked time.
+	Days int
+	// Trackers are sorted by time spent.
+	Trackers []ReviewItem
+	// Months lists every month of the period, even empty ones.
+	Months       []ReviewMonth
+	BusiestDay   time.Time
+	BusiestTotal time.Duration
+	// Streak is the longest run of consecutive days with tracked time,
+	// starting on StreakStart.
+	Streak      int
+	StreakStart time.Time
+}
+
+// Title names the review after its year, or its days when not a
+// calendar year.
+func (r Review) Title() string {
+	year := time.Date(r.From.Year(), 1, 1, 0, 0, 0, 0, r.From.Location())
+	if r.From.Equal(year) && r.To.Equal(year.AddDate(1, 0, 0)) {
+		return fmt.Sprintf("Year in review %d", r.From.Year())
+	}
+	return fmt.Sprintf("Review %s to %s", r.From.Format(DateFormat), r.To.AddDate(0, 0, -1).Format(DateFormat))
+}
+
+// YearReview computes the review of the requested period.
+func YearReview(r Request) Review {
+	review := Review{From: r.From, To: r.To}
+	entries := Split(r.Entries, r.From, r.To)
+
+	perTracker := map[string]*ReviewItem{}
+	for _, e := range entries {
+		item, ok := perTracker[e.Tracker]
+		if !ok {
+			item = &ReviewItem{Tracker: e.Tracker}
+			perTracker[e.Tracker] = item
+		}
+		if e.Issue != "" {
+			item.Issue = e.Issue
+		}
+		item.Duration += e.End.Sub(e.Start)
+		review.Total += e.End.Sub(e.Start)
+	}
+	for _, tracker := range Trackers(entries) {
+		item := *perTracker[tracker]
+		item.Share = 100 * float64(item.Duration) / float64(review.Total)
+		review.Trackers = append(review.Trackers, item)
+	}
+	slices.SortStableFunc(review.Trackers, func(a, b ReviewItem) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+
+	for month := time.Date(r.From.Year(), r.From.Month(), 1, 0, 0, 0, 0, r.From.Location()); month.Before(r.To); month = month.AddDate(0, 1, 0) {
+		review.Months = append(review.Months, ReviewMonth{Month: month})
+	}
+	daily := Daily(entries, r.From, r.To)
+	streak, start := 0, time.Time{}
+	for _, day := range Days(r.From, r.To) {
+		sum := time.Duration(0)
+		for _, d := range daily[day] {
+			sum += d
+		}
+		if sum == 0 {
+			streak = 0
+			continue
+		}
+		review.Days++
+		idx := (day.Year()-review.Months[0].Month.Year())*12 + int(day.Month()-review.Months[0].Month.Month())
+		review.Months[idx].Duration += sum
+		if sum > review.BusiestTotal {
+			review.BusiestDay, review.BusiestTotal = day, sum
+		}
+		if streak == 0 {
+			start = day
+		}
+		streak++
+		if streak > review.Streak {
+			review.Streak, review.StreakStart = streak, start
+		}
+	}
+	return review
+}
+
+type reviewReport struct {
+	Review
+	Trackers []htmlBar
+	Months   []htmlBar
+}
+
+// ReviewHTML writes the review as a standalone page, with its charts
+// drawn inline.
+func ReviewHTML(w io.Writer, r Request) error {
+	tmpl, err := htmlTemplate("review.html")
+	if err != nil {
+		return err
+	}
+	report := reviewReport{Review: YearReview(r)}
+	if report.Total == 0 {
+		return tmpl.Execute(w, report)
+	}
+	for _, item := range report.Review.Trackers {
+		report.Trackers = append(report.Trackers, htmlBar{Label: item.Tracker, Issue: item.Issue, Duration: item.Duration})
+	}
+	bars(report.Trackers, report.Total, 200)
+	for _, m := range report.Review.Months {
+		report.Months = append(report.Months, htmlBar{Label: m.Month.Format("January 2006"), Day: m.Month, Duration: m.Duration})
+	}
+	bars(report.Months, report.Total, chartHeight)
+	return tmpl.Execute(w, report)
+}
+
+// ReviewPDF writes the review as a single page PDF document.
+func ReviewPDF(w io.Writer, r Request) error {
+	review := YearReview(r)
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width := pageWidth - left - right
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(width, 10, tr(review.Title()), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "", 11)
+	if review.Total == 0 {
+		pdf.CellFormat(width, 7, "Nothing tracked.", "", 1, "L", false, 0, "")
+		return pdf.Output(w)
+	}
+	for _, line := range []string{
+		fmt.Sprintf("%s hours tracked over %d days", hours(review.Total), review.Days),
+		fmt.Sprintf("Busiest day: %s, %s", review.BusiestDay.Format("Monday 02 January"), duration(review.BusiestTotal)),
+		fmt.Sprintf("Longest streak: %d days in a row from %s", review.Streak, review.StreakStart.Format("02 January")),
+	} {
+		pdf.CellFormat(width, 7, tr(line), "", 1, "L", false, 0, "")
+	}
+
+	// monthly trend
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(width, 8, "Months", "", 1, "L", false, 0, "")
+	const chart = 50.0
+	longest := time.Duration(0)
+	for _, m := range review.Months {
+		longest = max(longest, m.Duration)
+	}
+	top := pdf.GetY() + 2
+	step := width / float64(max(len(review.Months), 12))
+	pdf.SetFillColor(63, 127, 191)
+	pdf.SetFont("Helvetica", "", 8)
+	for i, m := range review.Months {
+		x := left + float64(i)*step
+		height := chart * float64(m.Duration) / float64(longest)
+		if height > 0 {
+			pdf.Rect(x+step*0.15, top+chart-height, step*0.7, height, "F")
+		}
+		pdf.SetXY(x, top+chart+1)
+		pdf.CellFormat(step, 5, m.Month.Format("Jan"), "", 0, "C", false, 0, "")
+	}
+	pdf.SetY(top + chart + 10)
+
+	// per tracker breakdown
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(width, 8, "Trackers", "", 1, "L", false, 0, "")
+	cols := []float64{width * 0.46, width * 0.22, width * 0.16, width * 0.16}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range []string{"Tracker", "Issue", "Hours", "Share"} {
+		align := "R"
+		if i < 2 {
+			align = "L"
+		}
+		pdf.CellFormat(cols[i], 8, h, "B", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 10)
+	for _, item := range review.Trackers {
+		pdf.CellFormat(cols[0], 7, tr(item.Tracker), "", 0, "L", false, 0, "")
+		pdf.CellFormat(cols[1], 7, tr(item.Issue), "", 0, "L", false, 0, "")
+		pdf.CellFormat(cols[2], 7, hours(item.Duration), "", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[3], 7, fmt.Sprintf("%.0f%%", item.Share), "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(cols[0]+cols[1], 8, "Total", "T", 0, "L", false, 0, "")
+	pdf.CellFormat(cols[2], 8, hours(review.Total), "T", 0, "R", false, 0, "")
+	pdf.CellFormat(cols[3], 8, "100%", "T", 1, "R", false, 0, "")
+	return pdf.Output(w)
+}
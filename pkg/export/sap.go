@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(Format{
+		Name:       "sap",
+		Title:      "SAP CATS (CSV)",
+		Extensions: []string{".cats.csv", ".sap.csv"},
+		Exporter:   ExporterFunc(SAPCATS),
+	})
+}
+
+// Fields available to SAP CATS columns.
+const (
+	SAPFieldPersonnel = "pernr"
+	SAPFieldDate      = "date"
+	SAPFieldReceiver  = "receiver"
+	SAPFieldHours     = "hours"
+	SAPFieldText      = "text"
+	SAPFieldActivity  = "activity"
+)
+
+var SAPFields = []string{
+	SAPFieldPersonnel,
+	SAPFieldDate,
+	SAPFieldReceiver,
+	SAPFieldHours,
+	SAPFieldText,
+	SAPFieldActivity,
+}
+
+// SAPColumn maps a column of the CATS upload file to a clocker field.
+type SAPColumn struct {
+	Header string `yaml:"header"`
+	Field  string `yaml:"field"`
+}
+
+type SAPMapping struct {
+	PersonnelNumber string      `yaml:"personnel_number,omitempty"`
+	ActivityType    string      `yaml:"activity_type,omitempty"`
+	DateFormat      string      `yaml:"date_format,omitempty"`
+	Columns         []SAPColumn `yaml:"columns,omitempty"`
+}
+
+// DefaultSAPColumns is the layout typically accepted by CATS uploads: the
+// receiver object defaults to a WBS element.
+var DefaultSAPColumns = []SAPColumn{
+	{Header: "PERNR", Field: SAPFieldPersonnel},
+	{Header: "WORKDATE", Field: SAPFieldDate},
+	{Header: "RPROJ", Field: SAPFieldReceiver},
+	{Header: "CATSHOURS", Field: SAPFieldHours},
+	{Header: "LTXA1", Field: SAPFieldText},
+}
+
+func (m SAPMapping) columns() []SAPColumn {
+	if len(m.Columns) == 0 {
+		return DefaultSAPColumns
+	}
+	return m.Columns
+}
+
+func (m SAPMapping) dateFormat() string {
+	if m.DateFormat == "" {
+		return "20060102"
+	}
+	return m.DateFormat
+}
+
+func FormatSAPColumns(columns []SAPColumn) string {
+	lines := []string{}
+	for _, c := range columns {
+		lines = append(lines, fmt.Sprintf("%s = %s", c.Header, c.Field))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseSAPColumns reads one "HEADER = field" mapping per line.
+func ParseSAPColumns(s string) ([]SAPColumn, error) {
+	columns := []SAPColumn{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		header, field, found := strings.Cut(line, "=")
+		header = strings.TrimSpace(header)
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !found || header == "" {
+			return nil, fmt.Errorf("invalid column mapping %q", line)
+		}
+		known := false
+		for _, f := range SAPFields {
+			known = known || f == field
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q, expected one of %s", field, strings.Join(SAPFields, ", "))
+		}
+		columns = append(columns, SAPColumn{Header: header, Field: field})
+	}
+	return columns, nil
+}
+
+// sapRow is a row of the CATS upload file: the time of a tracker on a
+// receiver for a day.
+type sapRow struct {
+	day      time.Time
+	tracker  string
+	receiver string
+}
+
+// SAPCATS writes one row per day, tracker and receiver, laid out according
+// to the configured column mapping, the text being the session notes.
+func SAPCATS(w io.Writer, r Request) error {
+	columns := r.SAP.columns()
+	entries := map[sapRow][]Entry{}
+	rows := []sapRow{}
+	for _, e := range Split(sortedEntries(r.Entries), r.From, r.To) {
+		row := sapRow{day: Day(e.Start), tracker: e.Tracker, receiver: e.Receiver}
+		if _, ok := entries[row]; !ok {
+			rows = append(rows, row)
+		}
+		entries[row] = append(entries[row], e)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch {
+		case !a.day.Equal(b.day):
+			return a.day.Before(b.day)
+		case a.tracker != b.tracker:
+			return a.tracker < b.tracker
+		}
+		return a.receiver < b.receiver
+	})
+
+	out := csv.NewWriter(w)
+	header := []string{}
+	for _, c := range columns {
+		header = append(header, c.Header)
+	}
+	err := out.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		spent := time.Duration(0)
+		for _, e := range entries[row] {
+			spent += e.End.Sub(e.Start)
+		}
+		values := []string{}
+		for _, c := range columns {
+			value := ""
+			switch c.Field {
+			case SAPFieldPersonnel:
+				value = r.SAP.PersonnelNumber
+			case SAPFieldDate:
+				value = row.day.Format(r.SAP.dateFormat())
+			case SAPFieldReceiver:
+				value = row.receiver
+			case SAPFieldHours:
+				value = hours(spent)
+			case SAPFieldText:
+				value = describe(entries[row])
+			case SAPFieldActivity:
+				value = r.SAP.ActivityType
+			}
+			values = append(values, value)
+		}
+		err = out.Write(values)
+		if err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"text/template"
+	"time"
+)
+
+func init() {
+	Register(Format{
+		Name:       "standup",
+		Title:      "Standup summary (text)",
+		Extensions: []string{".standup.txt", ".txt"},
+		Exporter:   ExporterFunc(Standup),
+	})
+}
+
+// DefaultStandupTemplate lists the trackers worked on each day, with the
+// notes taken, ready to be pasted in a chat.
+const DefaultStandupTemplate = `{{range .Days}}*{{.Day.Format "Monday 02 Jan"}}* ({{duration .Total}})
+{{range .Trackers}}• {{.Tracker}}{{with .Issue}} [{{.}}]{{end}}: {{duration .Duration}}
+{{range .Notes}}    ◦ {{.}}
+{{end}}{{end}}{{end}}`
+
+type StandupOptions struct {
+	// Template is a Go text template over a StandupSummary.
+	Template string `yaml:"template,omitempty"`
+}
+
+// StandupItem is the time spent on a tracker in a day.
+type StandupItem struct {
+	Tracker  string
+	Issue    string
+	Duration time.Duration
+	Notes    []string
+}
+
+type StandupDay struct {
+	Day      time.Time
+	Total    time.Duration
+	Trackers []StandupItem
+}
+
+// StandupSummary holds the days with tracked time of [From, To).
+type StandupSummary struct {
+	From  time.Time
+	To    time.Time
+	Total time.Duration
+	Days  []StandupDay
+}
+
+// Summarize groups the entries per day and tracker, trackers being sorted
+// by time spent.
+func Summarize(r Request) StandupSummary {
+	summary := StandupSummary{From: r.From, To: r.To}
+	days := map[time.Time]*StandupDay{}
+	for _, e := range Split(r.Entries, r.From, r.To) {
+		day := Day(e.Start)
+		d, ok := days[day]
+		if !ok {
+			d = &StandupDay{Day: day}
+			days[day] = d
+		}
+		idx := slices.IndexFunc(d.Trackers, func(i StandupItem) bool {
+			return i.Tracker == e.Tracker
+		})
+		if idx < 0 {
+			d.Trackers = append(d.Trackers, StandupItem{Tracker: e.Tracker, Issue: e.Issue})
+			idx = len(d.Trackers) - 1
+		}
+		item := &d.Trackers[idx]
+		item.Duration += e.End.Sub(e.Start)
+		if e.Note != "" && !slices.Contains(item.Notes, e.Note) {
+			item.Notes = append(item.Notes, e.Note)
+		}
+		d.Total += e.End.Sub(e.Start)
+		summary.Total += e.End.Sub(e.Start)
+	}
+	for _, day := range Days(r.From, r.To) {
+		d, ok := days[day]
+		if !ok {
+			continue
+		}
+		slices.SortStableFunc(d.Trackers, func(a, b StandupItem) int {
+			return cmp.Compare(b.Duration, a.Duration)
+		})
+		summary.Days = append(summary.Days, *d)
+	}
+	return summary
+}
+
+// duration rounds to the minute, e.g. 2h30m or 45m.
+func duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
+
+// StandupTemplate parses a summary template, the default one when empty.
+func StandupTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultStandupTemplate
+	}
+	tmpl, err := template.New("standup").Funcs(template.FuncMap{"duration": duration}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid standup template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Standup writes the summary using the configured template, or the
+// default one.
+func Standup(w io.Writer, r Request) error {
+	tmpl, err := StandupTemplate(r.Standup.Template)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, Summarize(r))
+}
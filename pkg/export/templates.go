@@ -0,0 +1,24 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"html/template"
+	"io/fs"
+	"path"
+
+	"github.com/gxben/clocker/pkg/assets"
+)
+
+// Assets holds the report templates, under templates/. Set it to an
+// assets.New overlay for the user's templates to replace the bundled ones.
+var Assets fs.FS = assets.New("")
+
+// htmlTemplate parses one of the HTML report templates.
+func htmlTemplate(name string) (*template.Template, error) {
+	return template.New(name).Funcs(htmlFuncs).ParseFS(Assets, path.Join("templates", name))
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+func init() {
+	Register(Format{
+		Name:       "tempo-csv",
+		Title:      "Jira Tempo worklogs (CSV)",
+		Extensions: []string{".tempo.csv"},
+		Exporter:   ExporterFunc(TempoCSV),
+	})
+	Register(Format{
+		Name:       "tempo-json",
+		Title:      "Jira Tempo worklogs (JSON)",
+		Extensions: []string{".tempo.json", ".json"},
+		Exporter:   ExporterFunc(TempoJSON),
+	})
+}
+
+type TempoOptions struct {
+	AccountID string `yaml:"account_id,omitempty"`
+}
+
+// Worklog follows the fields Tempo Timesheets accepts for bulk imports.
+type Worklog struct {
+	IssueKey         string `json:"issueKey"`
+	TimeSpentSeconds int64  `json:"timeSpentSeconds"`
+	StartDate        string `json:"startDate"`
+	StartTime        string `json:"startTime"`
+	Description      string `json:"description"`
+	AuthorAccountID  string `json:"authorAccountId,omitempty"`
+}
+
+// Worklogs converts entries linked to an issue into worklogs, merged per
+// issue key and day, starting with the first entry of the day. Entries
+// spanning midnight are split into one worklog per day. Descriptions are
+// the session notes, or the tracker labels without any.
+func Worklogs(r Request) []Worklog {
+	type key struct {
+		issue string
+		day   time.Time
+	}
+	entries := map[key][]Entry{}
+	keys := []key{}
+	for _, e := range Split(sortedEntries(r.Entries), r.From, r.To) {
+		if e.Issue == "" {
+			continue
+		}
+		k := key{e.Issue, Day(e.Start)}
+		if _, ok := entries[k]; !ok {
+			keys = append(keys, k)
+		}
+		entries[k] = append(entries[k], e)
+	}
+
+	worklogs := []Worklog{}
+	for _, k := range keys {
+		spent := time.Duration(0)
+		for _, e := range entries[k] {
+			spent += e.End.Sub(e.Start)
+		}
+		start := entries[k][0].Start
+		worklogs = append(worklogs, Worklog{
+			IssueKey:         k.issue,
+			TimeSpentSeconds: int64(spent.Seconds()),
+			StartDate:        start.Format(DateFormat),
+			StartTime:        start.Format("15:04:05"),
+			Description:      describe(entries[k]),
+			AuthorAccountID:  r.Tempo.AccountID,
+		})
+	}
+	sort.SliceStable(worklogs, func(i, j int) bool {
+		if worklogs[i].IssueKey != worklogs[j].IssueKey {
+			return worklogs[i].IssueKey < worklogs[j].IssueKey
+		}
+		return worklogs[i].StartDate+worklogs[i].StartTime < worklogs[j].StartDate+worklogs[j].StartTime
+	})
+	return worklogs
+}
+
+func TempoCSV(w io.Writer, r Request) error {
+	out := csv.NewWriter(w)
+	err := out.Write([]string{"Issue Key", "Start Date", "Start Time", "Time Spent (seconds)", "Description", "Account ID"})
+	if err != nil {
+		return err
+	}
+
+	for _, wl := range Worklogs(r) {
+		err = out.Write([]string{
+			wl.IssueKey,
+			wl.StartDate,
+			wl.StartTime,
+			strconv.FormatInt(wl.TimeSpentSeconds, 10),
+			wl.Description,
+			wl.AuthorAccountID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func TempoJSON(w io.Writer, r Request) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Worklogs(r))
+}
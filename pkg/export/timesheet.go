@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+func init() {
+	Register(Format{
+		Name:       "grid",
+		Title:      "Weekly grid (CSV)",
+		Extensions: []string{".csv"},
+		Exporter:   ExporterFunc(WeeklyGrid),
+	})
+	Register(Format{
+		Name:       "daily",
+		Title:      "Daily decimal hours (CSV)",
+		Extensions: []string{".daily.csv"},
+		Exporter:   ExporterFunc(DailyHours),
+	})
+}
+
+func hours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
+
+// WeeklyGrid writes one row per tracker and one column per day, the way
+// most corporate timesheets are laid out.
+func WeeklyGrid(w io.Writer, r Request) error {
+	days := Days(r.From, r.To)
+	daily := Daily(r.Entries, r.From, r.To)
+
+	out := csv.NewWriter(w)
+	header := []string{"Tracker"}
+	for _, d := range days {
+		header = append(header, d.Format("Mon "+DateFormat))
+	}
+	header = append(header, "Total")
+	err := out.Write(header)
+	if err != nil {
+		return err
+	}
+
+	sums := make([]time.Duration, len(days))
+	total := time.Duration(0)
+	for _, tracker := range Trackers(Split(r.Entries, r.From, r.To)) {
+		row := []string{tracker}
+		sum := time.Duration(0)
+		for idx, d := range days {
+			spent := daily[d][tracker]
+			row = append(row, hours(spent))
+			sums[idx] += spent
+			sum += spent
+		}
+		row = append(row, hours(sum))
+		total += sum
+		err = out.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	row := []string{"Total"}
+	for _, sum := range sums {
+		row = append(row, hours(sum))
+	}
+	row = append(row, hours(total))
+	err = out.Write(row)
+	if err != nil {
+		return err
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// DailyHours writes one row per day and tracker, with decimal hours.
+func DailyHours(w io.Writer, r Request) error {
+	daily := Daily(r.Entries, r.From, r.To)
+
+	out := csv.NewWriter(w)
+	err := out.Write([]string{"Date", "Tracker", "Hours"})
+	if err != nil {
+		return err
+	}
+
+	for _, d := range Days(r.From, r.To) {
+		for _, tracker := range Trackers(r.Entries) {
+			spent := daily[d][tracker]
+			if spent == 0 {
+				continue
+			}
+			err = out.Write([]string{d.Format(DateFormat), tracker, hours(spent)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
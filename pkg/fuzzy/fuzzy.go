@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package fuzzy ranks texts against a pattern whose letters they hold in
+// order, such as "acmbil" for "Acme billing".
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	matchScore       = 1
+	consecutiveBonus = 6
+	wordStartBonus   = 6
+	// gaps, and runes before the first match, cost a point per skipped
+	// rune, up to maxGapPenalty
+	maxGapPenalty = 3
+)
+
+// wordStart tells whether the rune at i starts a word, e.g. after a space
+// or a dash, or as an upper case letter after a lower case one.
+func wordStart(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, r := text[i-1], text[i]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(r)
+}
+
+// scoreFrom matches the pattern greedily from the start rune of the text.
+func scoreFrom(pattern, text, lower []rune, start int) (int, bool) {
+	score, last := -min(start, maxGapPenalty), -1
+	p := 0
+	for i := start; i < len(text) && p < len(pattern); i++ {
+		if lower[i] != pattern[p] {
+			continue
+		}
+		score += matchScore
+		if wordStart(text, i) {
+			score += wordStartBonus
+		}
+		switch {
+		case last >= 0 && i == last+1:
+			score += consecutiveBonus
+		case last >= 0:
+			score -= min(i-last-1, maxGapPenalty)
+		}
+		last = i
+		p++
+	}
+	return score, p == len(pattern)
+}
+
+// Score tells how well the text matches the pattern, ignoring case, the
+// text having to hold all the runes of the pattern in order. Matches at the
+// start of words and runs of consecutive runes score higher.
+func Score(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, true
+	}
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// a few runes change length with their case
+		runes = lower
+	}
+
+	best, found := 0, false
+	for i := range lower {
+		if lower[i] != p[0] {
+			continue
+		}
+		score, ok := scoreFrom(p, runes, lower, i)
+		if ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// Match scores the fields against all the words of the pattern, each one
+// being matched by its best field.
+func Match(pattern string, fields ...string) (int, bool) {
+	total := 0
+	for _, word := range strings.Fields(pattern) {
+		best, found := 0, false
+		for _, field := range fields {
+			score, ok := Score(word, field)
+			if ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package fuzzy
+
+import (
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	for _, text := range []string{"Acme billing", "ACME-Billing", "acmeBilling", "a c m e b i l"} {
+		if _, ok := Score("acmbil", text); !ok {
+			t.Errorf("acmbil doesn't match %q", text)
+		}
+	}
+	for _, text := range []string{"", "Acme", "billing acme"} {
+		if _, ok := Score("acmbil", text); ok {
+			t.Errorf("acmbil matches %q", text)
+		}
+	}
+
+	// better matches first
+	ranked := []string{"Billing", "The billing module", "abilinxg"}
+	prev, _ := Score("bil", ranked[0])
+	for _, text := range ranked[1:] {
+		score, ok := Score("bil", text)
+		if !ok || score >= prev {
+			t.Errorf("bil scores %d on %q, expected less than %d", score, text, prev)
+		}
+		prev = score
+	}
+}
+
+func TestMatch(t *testing.T) {
+	if _, ok := Match("acme login", "Acme", "LOGIN-12"); !ok {
+		t.Error("words not matched by different fields")
+	}
+	if _, ok := Match("acme login", "Acme", "Globex"); ok {
+		t.Error("matched with a missing word")
+	}
+	exact, _ := Match("glo", "Globex")
+	loose, _ := Match("glo", "Acme", "go long")
+	if exact <= loose {
+		t.Errorf("exact prefix scored %d, loose match %d", exact, loose)
+	}
+	if score, ok := Match("  ", "Acme"); !ok || score != 0 {
+		t.Error("empty pattern doesn't match everything")
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package hooks runs user commands on tracker events, the commands being
+// templates over the event payload.
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds hooks not setting their own timeout.
+	DefaultTimeout = 30 * time.Second
+	// AnyEvent matches all events.
+	AnyEvent = "*"
+)
+
+// keptEnv survive a clean environment, commands hardly running without
+// them.
+var keptEnv = []string{"PATH", "HOME", "USER", "LANG", "TMPDIR", "SystemRoot", "ComSpec", "TEMP"}
+
+// TrackerInfo describes the tracker an event happened to.
+type TrackerInfo struct {
+	ID       string
+	Label    string
+	Issue    string
+	Receiver string
+	State    string
+}
+
+// SessionInfo describes the session an event started or ended.
+type SessionInfo struct {
+	ID       string
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+}
+
+// Payload is what hook templates get, e.g. {{.Tracker.Label}} or
+// {{.Elapsed.Hours}}.
+type Payload struct {
+	Event   string
+	At      time.Time
+	Tracker TrackerInfo
+	Session *SessionInfo
+	// Elapsed is the tracker counter, including the running session.
+	Elapsed time.Duration
+}
+
+type Hook struct {
+	// Name shows up in the log, defaulting to the command.
+	Name string `yaml:"name,omitempty"`
+	// Event is the one to run on, or * for all of them.
+	Event string `yaml:"event"`
+	// Command is run by the shell once executed as a template, values
+	// being passed through quote to be safe, e.g. {{quote .Tracker.Label}}.
+	Command string        `yaml:"command"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Env lists KEY=value templates added to the environment.
+	Env []string `yaml:"env,omitempty"`
+	// CleanEnv only passes the essential variables of clocker's
+	// environment, along with Env.
+	CleanEnv bool   `yaml:"clean_env,omitempty"`
+	Dir      string `yaml:"dir,omitempty"`
+}
+
+func (h *Hook) String() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Command
+}
+
+func (h *Hook) Matches(event string) bool {
+	return h.Event == AnyEvent || strings.EqualFold(h.Event, event)
+}
+
+var funcs = template.FuncMap{
+	"quote": quote,
+}
+
+// quote makes a string a single shell word.
+func quote(v any) string {
+	s := fmt.Sprint(v)
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func render(name, text string, p Payload) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	err = tmpl.Execute(&b, p)
+	return b.String(), err
+}
+
+// Render returns the command and added environment of the hook for the
+// payload.
+func (h *Hook) Render(p Payload) (string, []string, error) {
+	command, err := render("command", h.Command, p)
+	if err != nil {
+		return "", nil, err
+	}
+	env := []string{}
+	for _, e := range h.Env {
+		v, err := render("env", e, p)
+		if err != nil {
+			return "", nil, err
+		}
+		if !strings.Contains(v, "=") {
+			return "", nil, fmt.Errorf("environment entry %q is not KEY=value", e)
+		}
+		env = append(env, v)
+	}
+	return command, env, nil
+}
+
+func (h *Hook) environ(added []string) []string {
+	if !h.CleanEnv {
+		return append(os.Environ(), added...)
+	}
+	env := []string{}
+	for _, key := range keptEnv {
+		if v, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+v)
+		}
+	}
+	return append(env, added...)
+}
+
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// Run executes the hook for the payload, writing its output to out.
+func (h *Hook) Run(ctx context.Context, p Payload, out io.Writer) error {
+	command, env, err := h.Render(p)
+	if err != nil {
+		return err
+	}
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := shell(ctx, command)
+	cmd.Env = h.environ(env)
+	cmd.Dir = h.Dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// don't wait on background children holding the output open
+	cmd.WaitDelay = time.Second
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// Runner runs the hooks matching events, logging their output.
+type Runner struct {
+	Hooks  []Hook
+	Logger *log.Logger
+}
+
+// Fire runs the hooks matching the payload event one after the other,
+// returning once all are done.
+func (r *Runner) Fire(ctx context.Context, p Payload) error {
+	errs := []error{}
+	for idx := range r.Hooks {
+		h := &r.Hooks[idx]
+		if !h.Matches(p.Event) {
+			continue
+		}
+		var out bytes.Buffer
+		err := h.Run(ctx, p, &out)
+		lines := bufio.NewScanner(&out)
+		for lines.Scan() {
+			r.logf("hook %s: %s", h, lines.Text())
+		}
+		if err != nil {
+			err = fmt.Errorf("hook %s: %w", h, err)
+			r.logf("%s", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Runner) logf(format string, args ...any) {
+	if r.Logger != nil {
+		r.Logger.Printf(format, args...)
+	}
+}
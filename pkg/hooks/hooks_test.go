@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func payload() Payload {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	return Payload{
+		Event:   "pause",
+		At:      start.Add(90 * time.Minute),
+		Tracker: TrackerInfo{ID: "tracker-1", Label: "Acme's site", State: "paused"},
+		Session: &SessionInfo{ID: "session-a", Start: start, End: start.Add(90 * time.Minute), Duration: 90 * time.Minute},
+		Elapsed: 3 * time.Hour,
+	}
+}
+
+func skipWithoutShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("commands written for a POSIX shell")
+	}
+}
+
+func TestRender(t *testing.T) {
+	h := Hook{
+		Command: `notify-send {{quote .Tracker.Label}} "{{.Elapsed.Hours}}h, {{.Session.Duration}}"`,
+		Env:     []string{"TRACKER={{.Tracker.ID}}"},
+	}
+	command, env, err := h.Render(payload())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if command != `notify-send 'Acme'\''s site' "3h, 1h30m0s"` {
+		t.Errorf("command = %s", command)
+	}
+	if len(env) != 1 || env[0] != "TRACKER=tracker-1" {
+		t.Errorf("env = %v", env)
+	}
+
+	for _, h := range []Hook{
+		{Command: "echo {{.Tracker.Name}}"},
+		{Command: "echo {{"},
+		{Command: "true", Env: []string{"{{.Event}}"}},
+	} {
+		if _, _, err := h.Render(payload()); err == nil {
+			t.Errorf("rendered %+v", h)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	skipWithoutShell(t)
+	t.Setenv("CLOCKER_SECRET", "hidden")
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	h := Hook{Command: `echo "$EVENT {{.Tracker.Label}}"; echo "[$CLOCKER_SECRET]" >&2`, Env: []string{"EVENT={{.Event}}"}}
+	err := h.Run(ctx, payload(), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "pause Acme's site\n[hidden]\n" {
+		t.Errorf("output = %q", out.String())
+	}
+
+	out.Reset()
+	h.CleanEnv = true
+	err = h.Run(ctx, payload(), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "pause") || !strings.Contains(out.String(), "[]") {
+		t.Errorf("output = %q, expected the added variable only", out.String())
+	}
+
+	started := time.Now()
+	err = (&Hook{Command: "sleep 5", Timeout: 100 * time.Millisecond}).Run(ctx, payload(), &out)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() = %v, expected a timeout", err)
+	}
+	if time.Since(started) > 3*time.Second {
+		t.Errorf("timeout took %s", time.Since(started))
+	}
+}
+
+func TestRunner(t *testing.T) {
+	skipWithoutShell(t)
+	var logged bytes.Buffer
+	r := Runner{
+		Hooks: []Hook{
+			{Name: "started", Event: "start", Command: "echo started"},
+			{Name: "paused", Event: "Pause", Command: "echo paused {{.Tracker.ID}}"},
+			{Name: "any", Event: AnyEvent, Command: "echo any; exit 3"},
+		},
+		Logger: log.New(&logged, "", 0),
+	}
+	err := r.Fire(context.Background(), payload())
+	if err == nil || !strings.Contains(err.Error(), "hook any: exit status 3") {
+		t.Errorf("Fire() = %v, expected the failing hook", err)
+	}
+	expected := "hook paused: paused tracker-1\nhook any: any\nhook any: exit status 3\n"
+	if logged.String() != expected {
+		t.Errorf("logged %q, expected %q", logged.String(), expected)
+	}
+}
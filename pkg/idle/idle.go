@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package idle detects when the user stops interacting with the desktop.
+package idle
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock"
+)
+
+var ErrUnsupported = errors.New("no idle detection backend available")
+
+// Event reports a change of the user activity. Since is when the user
+// stopped or resumed interacting with the desktop.
+type Event struct {
+	Idle  bool
+	Since time.Time
+}
+
+// Monitor delivers idle events once the user has been inactive for the
+// configured timeout, and again when activity resumes.
+type Monitor interface {
+	Name() string
+	Events() <-chan Event
+	Close() error
+}
+
+type backend func(timeout time.Duration, clk clock.Clock) (Monitor, error)
+
+// backends are tried in order, platform-specific files register theirs.
+var backends = []backend{}
+
+// New starts the first idle detection backend working in the current
+// desktop session, dating events with clk.
+func New(timeout time.Duration, clk clock.Clock) (Monitor, error) {
+	errs := []error{ErrUnsupported}
+	for _, b := range backends {
+		m, err := b(timeout, clk)
+		if err == nil {
+			return m, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
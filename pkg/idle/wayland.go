@@ -0,0 +1,349 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package idle
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gxben/clocker/pkg/clock"
+)
+
+// Wayland compositors don't let clients query the idle time, they notify
+// them instead through the ext-idle-notify protocol, or its KDE ancestor
+// org_kde_kwin_idle (also implemented by older wlroots compositors). Only
+// a handful of requests are needed, so the wire protocol is spoken
+// directly rather than through libwayland.
+
+const (
+	wlDisplayID = 1
+
+	// wl_display
+	wlDisplaySync        = 0
+	wlDisplayGetRegistry = 1
+	wlDisplayError       = 0
+
+	// wl_registry
+	wlRegistryBind   = 0
+	wlRegistryGlobal = 0
+
+	// wl_callback
+	wlCallbackDone = 0
+
+	// ext_idle_notifier_v1
+	extIdleGetNotification = 1
+	// org_kde_kwin_idle
+	kdeIdleGetTimeout = 0
+
+	// ext_idle_notification_v1 and org_kde_kwin_idle_timeout share
+	// the same layout
+	idleNotificationDestroy = 0
+	idleNotificationIdled   = 0
+	idleNotificationResumed = 1
+)
+
+const (
+	ifaceSeat        = "wl_seat"
+	ifaceExtNotifier = "ext_idle_notifier_v1"
+	ifaceKDEIdle     = "org_kde_kwin_idle"
+)
+
+type wlGlobal struct {
+	name    uint32
+	version uint32
+}
+
+type waylandMonitor struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+	clock   clock.Clock
+	events  chan Event
+	done    chan struct{}
+	nextID  uint32
+	objects map[string]uint32
+	notify  uint32
+	close   sync.Once
+}
+
+func init() {
+	backends = append(backends, newWayland)
+}
+
+func waylandSocket() (string, error) {
+	display := os.Getenv("WAYLAND_DISPLAY")
+	if display == "" {
+		return "", errors.New("not running a Wayland session")
+	}
+	if filepath.IsAbs(display) {
+		return display, nil
+	}
+	runtime := os.Getenv("XDG_RUNTIME_DIR")
+	if runtime == "" {
+		return "", errors.New("XDG_RUNTIME_DIR is not set")
+	}
+	return filepath.Join(runtime, display), nil
+}
+
+func newWayland(timeout time.Duration, clk clock.Clock) (Monitor, error) {
+	socket, err := waylandSocket()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &waylandMonitor{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: timeout,
+		clock:   clk,
+		events:  make(chan Event, 1),
+		done:    make(chan struct{}),
+		nextID:  wlDisplayID + 1,
+		objects: map[string]uint32{},
+	}
+
+	err = m.setup()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go m.run()
+	return m, nil
+}
+
+func (m *waylandMonitor) Name() string {
+	if _, ok := m.objects[ifaceExtNotifier]; ok {
+		return "wayland (ext-idle-notify)"
+	}
+	return "wayland (org_kde_kwin_idle)"
+}
+
+func (m *waylandMonitor) Events() <-chan Event {
+	return m.events
+}
+
+func (m *waylandMonitor) Close() error {
+	var err error
+	m.close.Do(func() {
+		close(m.done)
+		if m.notify != 0 {
+			_ = m.send(m.notify, idleNotificationDestroy)
+		}
+		err = m.conn.Close()
+	})
+	return err
+}
+
+func (m *waylandMonitor) newID() uint32 {
+	id := m.nextID
+	m.nextID++
+	return id
+}
+
+// setup lists the globals advertised by the compositor, binds the seat
+// and idle notifier, and subscribes to idle notifications.
+func (m *waylandMonitor) setup() error {
+	registry := m.newID()
+	err := m.send(wlDisplayID, wlDisplayGetRegistry, registry)
+	if err != nil {
+		return err
+	}
+	callback := m.newID()
+	err = m.send(wlDisplayID, wlDisplaySync, callback)
+	if err != nil {
+		return err
+	}
+
+	globals := map[string]wlGlobal{}
+	for done := false; !done; {
+		sender, opcode, body, err := m.receive()
+		if err != nil {
+			return err
+		}
+		switch {
+		case sender == registry && opcode == wlRegistryGlobal:
+			name, iface, version, err := parseGlobal(body)
+			if err != nil {
+				return err
+			}
+			if _, seen := globals[iface]; !seen {
+				globals[iface] = wlGlobal{name: name, version: version}
+			}
+		case sender == callback && opcode == wlCallbackDone:
+			done = true
+		}
+	}
+
+	seat, ok := globals[ifaceSeat]
+	if !ok {
+		return errors.New("wayland compositor has no seat")
+	}
+	notifier := ifaceExtNotifier
+	if _, ok := globals[notifier]; !ok {
+		notifier = ifaceKDEIdle
+	}
+	idle, ok := globals[notifier]
+	if !ok {
+		return errors.New("wayland compositor supports neither ext-idle-notify nor org_kde_kwin_idle")
+	}
+
+	binds := []struct {
+		iface  string
+		global wlGlobal
+	}{
+		{iface: ifaceSeat, global: seat},
+		{iface: notifier, global: idle},
+	}
+	for _, b := range binds {
+		id := m.newID()
+		err = m.send(registry, wlRegistryBind, b.global.name, b.iface, uint32(1), id)
+		if err != nil {
+			return err
+		}
+		m.objects[b.iface] = id
+	}
+
+	m.notify = m.newID()
+	ms := uint32(m.timeout.Milliseconds())
+	if notifier == ifaceExtNotifier {
+		err = m.send(m.objects[notifier], extIdleGetNotification, m.notify, ms, m.objects[ifaceSeat])
+	} else {
+		err = m.send(m.objects[notifier], kdeIdleGetTimeout, m.notify, m.objects[ifaceSeat], ms)
+	}
+	return err
+}
+
+func (m *waylandMonitor) run() {
+	defer close(m.events)
+	for {
+		sender, opcode, _, err := m.receive()
+		if err != nil {
+			return
+		}
+		if sender != m.notify {
+			continue
+		}
+
+		now := m.clock.Now()
+		var e Event
+		switch opcode {
+		case idleNotificationIdled:
+			e = Event{Idle: true, Since: now.Add(-m.timeout)}
+		case idleNotificationResumed:
+			e = Event{Idle: false, Since: now}
+		default:
+			continue
+		}
+
+		// nobody may be reading anymore once closed
+		select {
+		case m.events <- e:
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// send writes a request. Arguments are either uint32 (integers, object and
+// new ids) or strings.
+func (m *waylandMonitor) send(object uint32, opcode uint16, args ...any) error {
+	body := []byte{}
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case uint32:
+			body = binary.LittleEndian.AppendUint32(body, v)
+		case string:
+			body = binary.LittleEndian.AppendUint32(body, uint32(len(v)+1))
+			body = append(body, v...)
+			body = append(body, make([]byte, pad(len(v)+1))...)
+		default:
+			return fmt.Errorf("unsupported wayland argument %T", arg)
+		}
+	}
+
+	msg := binary.LittleEndian.AppendUint32(nil, object)
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(8+len(body))<<16|uint32(opcode))
+	_, err := m.conn.Write(append(msg, body...))
+	return err
+}
+
+// receive reads the next event, failing on protocol errors.
+func (m *waylandMonitor) receive() (uint32, uint16, []byte, error) {
+	header := make([]byte, 8)
+	_, err := io.ReadFull(m.reader, header)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	sender := binary.LittleEndian.Uint32(header[0:4])
+	word := binary.LittleEndian.Uint32(header[4:8])
+	size := int(word >> 16)
+	opcode := uint16(word & 0xffff)
+	if size < 8 {
+		return 0, 0, nil, fmt.Errorf("invalid wayland message size %d", size)
+	}
+
+	body := make([]byte, size-8)
+	_, err = io.ReadFull(m.reader, body)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	if sender == wlDisplayID && opcode == wlDisplayError && len(body) >= 12 {
+		msg, _, _ := parseString(body[8:])
+		return 0, 0, nil, fmt.Errorf("wayland error %d: %s", binary.LittleEndian.Uint32(body[4:8]), msg)
+	}
+	return sender, opcode, body, nil
+}
+
+func pad(n int) int {
+	return (4 - n%4) % 4
+}
+
+func parseString(b []byte) (string, []byte, error) {
+	if len(b) < 4 {
+		return "", nil, errors.New("truncated wayland string")
+	}
+	n := int(binary.LittleEndian.Uint32(b))
+	b = b[4:]
+	if n == 0 {
+		return "", b, nil
+	}
+	if len(b) < n+pad(n) {
+		return "", nil, errors.New("truncated wayland string")
+	}
+	return string(b[:n-1]), b[n+pad(n):], nil
+}
+
+func parseGlobal(b []byte) (uint32, string, uint32, error) {
+	if len(b) < 4 {
+		return 0, "", 0, errors.New("truncated wayland global")
+	}
+	name := binary.LittleEndian.Uint32(b)
+	iface, rest, err := parseString(b[4:])
+	if err != nil {
+		return 0, "", 0, err
+	}
+	if len(rest) < 4 {
+		return 0, "", 0, errors.New("truncated wayland global")
+	}
+	return name, iface, binary.LittleEndian.Uint32(rest), nil
+}
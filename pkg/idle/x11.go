@@ -0,0 +1,134 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package idle
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/screensaver"
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/gxben/clocker/pkg/clock"
+)
+
+const (
+	x11MaxPollInterval = 5 * time.Second
+)
+
+// x11Monitor polls the MIT-SCREEN-SAVER extension, which reports for how
+// long the X server hasn't received any input.
+type x11Monitor struct {
+	conn    *xgb.Conn
+	root    xproto.Drawable
+	timeout time.Duration
+	clock   clock.Clock
+	events  chan Event
+	done    chan struct{}
+	close   sync.Once
+}
+
+func init() {
+	backends = append(backends, newX11)
+}
+
+func newX11(timeout time.Duration, clk clock.Clock) (Monitor, error) {
+	if os.Getenv("DISPLAY") == "" {
+		return nil, errors.New("not running an X11 session")
+	}
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	err = screensaver.Init(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	m := &x11Monitor{
+		conn:    conn,
+		root:    xproto.Drawable(xproto.Setup(conn).DefaultScreen(conn).Root),
+		timeout: timeout,
+		clock:   clk,
+		events:  make(chan Event, 1),
+		done:    make(chan struct{}),
+	}
+
+	// make sure the server actually answers before claiming to work
+	_, err = m.idleTime()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go m.run()
+	return m, nil
+}
+
+func (m *x11Monitor) Name() string {
+	return "x11 (screensaver)"
+}
+
+func (m *x11Monitor) Events() <-chan Event {
+	return m.events
+}
+
+func (m *x11Monitor) Close() error {
+	m.close.Do(func() {
+		close(m.done)
+		m.conn.Close()
+	})
+	return nil
+}
+
+func (m *x11Monitor) idleTime() (time.Duration, error) {
+	info, err := screensaver.QueryInfo(m.conn, m.root).Reply()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(info.MsSinceUserInput) * time.Millisecond, nil
+}
+
+func (m *x11Monitor) run() {
+	defer close(m.events)
+
+	interval := min(m.timeout/4, x11MaxPollInterval)
+	ticker := m.clock.NewTicker(max(interval, time.Second))
+	defer ticker.Stop()
+
+	idle := false
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C():
+		}
+
+		elapsed, err := m.idleTime()
+		if err != nil {
+			return
+		}
+
+		if idle == (elapsed >= m.timeout) {
+			continue
+		}
+		idle = !idle
+
+		// nobody may be reading anymore once closed
+		select {
+		case m.events <- Event{Idle: idle, Since: m.clock.Now().Add(-elapsed)}:
+		case <-m.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package importer reads tracked time exported by other tools into a
+// normalized change set, previewed before being applied.
+package importer
+
+import (
+	"io"
+	"time"
+
+	"github.com/gxben/clocker/pkg/registry"
+)
+
+// Session is a span of imported time.
+type Session struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Tracker groups imported sessions the way clocker trackers do. ID is only
+// set for trackers already known, which imported ones get matched with.
+type Tracker struct {
+	ID       string
+	Label    string
+	Issue    string
+	Receiver string
+	Sessions []Session
+}
+
+// ChangeSet is what an import brings in, whatever the source format.
+type ChangeSet struct {
+	Trackers []Tracker
+}
+
+// add appends a session to the tracker with the given label, creating it
+// as needed.
+func (cs *ChangeSet) add(t Tracker, s Session) {
+	for idx := range cs.Trackers {
+		c := &cs.Trackers[idx]
+		if c.Label == t.Label && c.Issue == t.Issue {
+			c.Sessions = append(c.Sessions, s)
+			return
+		}
+	}
+	t.Sessions = []Session{s}
+	cs.Trackers = append(cs.Trackers, t)
+}
+
+// Importer reads a change set in a given format.
+type Importer interface {
+	Import(r io.Reader) (ChangeSet, error)
+}
+
+// ImporterFunc turns a plain function into an Importer.
+type ImporterFunc func(r io.Reader) (ChangeSet, error)
+
+func (f ImporterFunc) Import(r io.Reader) (ChangeSet, error) {
+	return f(r)
+}
+
+// Format is a registered import format.
+type Format struct {
+	// Name selects the format on the command line, e.g. tempo-json.
+	Name string
+	// Title is shown in the import dialog.
+	Title string
+	// Extensions recognize the format from a file name, most specific
+	// first.
+	Extensions []string
+	Importer
+}
+
+func (f Format) info() registry.Info {
+	return registry.Info{Name: f.Name, Title: f.Title, Extensions: f.Extensions}
+}
+
+var formats = registry.New("importer", Format.info)
+
+// Register makes a format available, each format file registering its own
+// from init.
+func Register(f Format) {
+	formats.Register(f)
+}
+
+// Formats lists the registered formats, sorted by title.
+func Formats() []Format {
+	return formats.List()
+}
+
+// Lookup finds a format by name or title.
+func Lookup(name string) (Format, bool) {
+	return formats.Lookup(name)
+}
+
+// Detect finds the format of a file from its name, the longest matching
+// extension winning.
+func Detect(file string) (Format, bool) {
+	return formats.Detect(file)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gxben/clocker/pkg/export"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, 3, day, hour, minute, 0, 0, time.Local)
+}
+
+func TestSessionsCSV(t *testing.T) {
+	in := `Tracker,Start,End,Issue
+Acme,2026-03-02 09:00,2026-03-02 11:30,acme-1
+Globex,2026-03-02T13:00:00Z,2026-03-02T14:00:00Z,
+Acme,2026-03-03 09:00,2026-03-03 10:00,ACME-1
+`
+	cs, err := SessionsCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs.Trackers) != 2 {
+		t.Fatalf("read %d trackers, expected 2", len(cs.Trackers))
+	}
+	acme := cs.Trackers[0]
+	if acme.Label != "Acme" || acme.Issue != "ACME-1" || len(acme.Sessions) != 2 {
+		t.Errorf("read %+v", acme)
+	}
+	if !acme.Sessions[0].Start.Equal(at(2, 9, 0)) || !acme.Sessions[0].End.Equal(at(2, 11, 30)) {
+		t.Errorf("first session %+v", acme.Sessions[0])
+	}
+	if globex := cs.Trackers[1]; globex.Sessions[0].End.Sub(globex.Sessions[0].Start) != time.Hour {
+		t.Errorf("RFC 3339 session %+v", globex.Sessions[0])
+	}
+}
+
+func TestSessionsCSVErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"Tracker,Start\nAcme,2026-03-02 09:00\n",
+		"Tracker,Start,End\nAcme,yesterday,2026-03-02 10:00\n",
+		"Tracker,Start,End\n,2026-03-02 09:00,2026-03-02 10:00\n",
+	}
+	for _, in := range tests {
+		_, err := SessionsCSV(strings.NewReader(in))
+		if err == nil {
+			t.Errorf("SessionsCSV(%q) succeeded", in)
+		}
+	}
+}
+
+func TestTempoJSONRoundTrip(t *testing.T) {
+	r := export.Request{
+		From: at(1, 0, 0),
+		To:   at(31, 0, 0),
+		Entries: []export.Entry{
+			{Tracker: "Acme", Issue: "ACME-1", Start: at(2, 9, 0), End: at(2, 10, 30)},
+			{Tracker: "Acme", Issue: "ACME-1", Start: at(3, 9, 0), End: at(3, 10, 0)},
+		},
+	}
+	var out bytes.Buffer
+	err := export.TempoJSON(&out, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs, err := TempoJSON(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs.Trackers) != 1 || cs.Trackers[0].Label != "Acme" || cs.Trackers[0].Issue != "ACME-1" {
+		t.Fatalf("read %+v", cs.Trackers)
+	}
+	sessions := cs.Trackers[0].Sessions
+	if len(sessions) != 2 || !sessions[0].Start.Equal(at(2, 9, 0)) || !sessions[0].End.Equal(at(2, 10, 30)) {
+		t.Errorf("read sessions %+v", sessions)
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	existing := []Tracker{
+		{ID: "1", Label: "Acme", Issue: "ACME-1", Sessions: []Session{
+			{Start: at(2, 9, 0), End: at(2, 10, 0)},
+			{Start: at(5, 9, 0)}, // running
+		}},
+		{ID: "2", Label: "Globex"},
+	}
+	cs := ChangeSet{Trackers: []Tracker{
+		{Label: "Acme tickets", Issue: "acme-1", Sessions: []Session{
+			{Start: at(2, 9, 0), End: at(2, 10, 0)},  // duplicate
+			{Start: at(2, 9, 30), End: at(2, 11, 0)}, // overlaps a known one
+			{Start: at(3, 9, 0), End: at(3, 10, 0)},  // added
+			{Start: at(3, 9, 30), End: at(3, 9, 45)}, // overlaps the one just added
+			{Start: at(5, 10, 0), End: at(5, 11, 0)}, // overlaps the running one
+			{Start: at(4, 10, 0), End: at(4, 9, 0)},  // invalid
+			{Start: at(4, 12, 0), End: at(4, 13, 0)}, // closed
+		}},
+		{Label: " globex", Sessions: []Session{{Start: at(2, 9, 0), End: at(2, 10, 0)}}},
+		{Label: "Initech", Sessions: []Session{{Start: at(2, 9, 0), End: at(2, 10, 0)}}},
+		{Label: "initech", Sessions: []Session{{Start: at(3, 9, 0), End: at(3, 10, 0)}}},
+		// same label, other issue
+		{Label: "Initech", Issue: "INI-2", Sessions: []Session{{Start: at(2, 9, 0), End: at(2, 10, 0)}}},
+	}}
+	closed := func(tracker string, s Session) string {
+		if s.Start.Day() == 4 && s.Start.Hour() == 12 {
+			return "closed period"
+		}
+		return ""
+	}
+
+	plan := NewPlan(cs, existing, closed)
+	if len(plan.Sessions["1"]) != 1 || !plan.Sessions["1"][0].Start.Equal(at(3, 9, 0)) {
+		t.Errorf("sessions added to Acme %+v", plan.Sessions["1"])
+	}
+	if len(plan.Sessions["2"]) != 1 {
+		t.Errorf("sessions added to Globex %+v", plan.Sessions["2"])
+	}
+	if len(plan.New) != 2 || plan.New[0].Label != "Initech" || len(plan.New[0].Sessions) != 2 ||
+		plan.New[1].Issue != "INI-2" || len(plan.New[1].Sessions) != 1 {
+		t.Errorf("new trackers %+v", plan.New)
+	}
+	if plan.Duplicates != 1 {
+		t.Errorf("%d duplicates, expected 1", plan.Duplicates)
+	}
+	if len(plan.Conflicts) != 5 {
+		t.Errorf("conflicts %v, expected 5", plan.Conflicts)
+	}
+	for _, c := range plan.Conflicts {
+		if c.Tracker != "Acme" {
+			t.Errorf("conflict reported on %q, expected the known label", c.Tracker)
+		}
+	}
+	if s := plan.Summary(); s != "2 new trackers, 5 sessions, 5 conflicts, 1 duplicate" {
+		t.Errorf("summary %q", s)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		"week.csv":            "csv",
+		"week.sessions.csv":   "csv",
+		"worklogs.json":       "tempo-json",
+		"WORKLOGS.TEMPO.JSON": "tempo-json",
+		"week.pdf":            "",
+	}
+	for file, name := range tests {
+		f, ok := Detect(file)
+		if ok != (name != "") || f.Name != name {
+			t.Errorf("Detect(%q) = %q, %v, expected %q", file, f.Name, ok, name)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Conflict is an imported session left out, and why.
+type Conflict struct {
+	Tracker string
+	Session Session
+	Reason  string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s-%s: %s", c.Tracker, c.Session.Start.Format("2006-01-02 15:04"),
+		c.Session.End.Format("15:04"), c.Reason)
+}
+
+// Check rejects an imported session with a reason, e.g. when it falls in a
+// closed accounting period, or accepts it with an empty one.
+type Check func(tracker string, s Session) string
+
+// Plan is what applying a change set would do.
+type Plan struct {
+	// New are the trackers to create, along with their sessions.
+	New []Tracker
+	// Sessions are the ones to add to known trackers, by tracker ID.
+	Sessions map[string][]Session
+	// Duplicates count sessions already known, silently skipped.
+	Duplicates int
+	Conflicts  []Conflict
+}
+
+// Empty tells whether applying the plan would change nothing.
+func (p Plan) Empty() bool {
+	return len(p.New) == 0 && p.SessionCount() == 0
+}
+
+// SessionCount is the number of sessions the plan adds.
+func (p Plan) SessionCount() int {
+	count := 0
+	for _, t := range p.New {
+		count += len(t.Sessions)
+	}
+	for _, sessions := range p.Sessions {
+		count += len(sessions)
+	}
+	return count
+}
+
+// Summary previews the plan, e.g. "1 new tracker, 12 sessions, 2 conflicts".
+func (p Plan) Summary() string {
+	parts := []string{
+		plural(len(p.New), "new tracker"),
+		plural(p.SessionCount(), "session"),
+		plural(len(p.Conflicts), "conflict"),
+	}
+	if p.Duplicates > 0 {
+		parts = append(parts, plural(p.Duplicates, "duplicate"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, what string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", what)
+	}
+	return fmt.Sprintf("%d %ss", n, what)
+}
+
+// match finds the known tracker an imported one belongs to: the one linked
+// to the same issue, or else the one with the same label.
+func match(t Tracker, existing []Tracker) int {
+	if t.Issue != "" {
+		for idx, e := range existing {
+			if strings.EqualFold(e.Issue, t.Issue) {
+				return idx
+			}
+		}
+	}
+	for idx, e := range existing {
+		if strings.EqualFold(strings.TrimSpace(e.Label), strings.TrimSpace(t.Label)) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func overlaps(a, b Session) bool {
+	return a.Start.Before(b.End) && b.Start.Before(a.End)
+}
+
+// NewPlan matches the change set with the known trackers and sorts out
+// which sessions can be added. Sessions overlapping known or previously
+// accepted ones are conflicts, so that nothing gets counted twice. Known
+// sessions still running have a zero End.
+func NewPlan(cs ChangeSet, existing []Tracker, checks ...Check) Plan {
+	plan := Plan{Sessions: map[string][]Session{}}
+
+	// sessions per known tracker ID, or per label and issue of trackers to
+	// create
+	known := map[string][]Session{}
+	for _, e := range existing {
+		for _, s := range e.Sessions {
+			if s.End.IsZero() {
+				s.End = time.Unix(1<<62, 0) // running ones take up the future
+			}
+			known[e.ID] = append(known[e.ID], s)
+		}
+	}
+	created := map[string]int{}
+
+	for _, t := range cs.Trackers {
+		label, key := t.Label, ""
+		if idx := match(t, existing); idx >= 0 {
+			label, key = existing[idx].Label, existing[idx].ID
+		} else {
+			key = "new:" + strings.ToLower(strings.TrimSpace(label)) + "\x00" + strings.ToLower(t.Issue)
+			if _, ok := created[key]; !ok {
+				created[key] = len(plan.New)
+				plan.New = append(plan.New, Tracker{Label: t.Label, Issue: t.Issue, Receiver: t.Receiver})
+			}
+		}
+
+	sessions:
+		for _, s := range t.Sessions {
+			if !s.End.After(s.Start) {
+				plan.Conflicts = append(plan.Conflicts, Conflict{label, s, "ends before it starts"})
+				continue
+			}
+			for _, check := range checks {
+				if reason := check(label, s); reason != "" {
+					plan.Conflicts = append(plan.Conflicts, Conflict{label, s, reason})
+					continue sessions
+				}
+			}
+			for _, k := range known[key] {
+				if k.Start.Equal(s.Start) && k.End.Equal(s.End) {
+					plan.Duplicates++
+					continue sessions
+				}
+				if overlaps(k, s) {
+					reason := fmt.Sprintf("overlaps %s-%s", k.Start.Format("2006-01-02 15:04"), k.End.Format("15:04"))
+					plan.Conflicts = append(plan.Conflicts, Conflict{label, s, reason})
+					continue sessions
+				}
+			}
+
+			known[key] = append(known[key], s)
+			if n, ok := created[key]; ok {
+				plan.New[n].Sessions = append(plan.New[n].Sessions, s)
+			} else {
+				plan.Sessions[key] = append(plan.Sessions[key], s)
+			}
+		}
+	}
+	return plan
+}
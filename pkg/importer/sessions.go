@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	SessionTimeFormat = "2006-01-02 15:04"
+)
+
+func init() {
+	Register(Format{
+		Name:       "csv",
+		Title:      "Sessions (CSV)",
+		Extensions: []string{".sessions.csv", ".csv"},
+		Importer:   ImporterFunc(SessionsCSV),
+	})
+}
+
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(SessionTimeFormat, s, time.Local)
+	if err != nil {
+		return t, fmt.Errorf("invalid time %q, expected RFC 3339 or YYYY-MM-DD HH:MM", s)
+	}
+	return t, nil
+}
+
+// SessionsCSV reads one session per row, with a header naming the tracker,
+// start and end columns, and optionally the issue and receiver ones.
+func SessionsCSV(r io.Reader) (ChangeSet, error) {
+	cs := ChangeSet{}
+	in := csv.NewReader(r)
+	in.TrimLeadingSpace = true
+	header, err := in.Read()
+	if err != nil {
+		return cs, fmt.Errorf("missing header: %w", err)
+	}
+	columns := map[string]int{}
+	for idx, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+	for _, name := range []string{"tracker", "start", "end"} {
+		if _, ok := columns[name]; !ok {
+			return cs, fmt.Errorf("missing %s column", name)
+		}
+	}
+	field := func(row []string, name string) string {
+		idx, ok := columns[name]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[idx])
+	}
+
+	for line := 2; ; line++ {
+		row, err := in.Read()
+		if errors.Is(err, io.EOF) {
+			return cs, nil
+		}
+		if err != nil {
+			return cs, err
+		}
+		start, err := parseTime(field(row, "start"))
+		if err != nil {
+			return cs, fmt.Errorf("line %d: %w", line, err)
+		}
+		end, err := parseTime(field(row, "end"))
+		if err != nil {
+			return cs, fmt.Errorf("line %d: %w", line, err)
+		}
+		label := field(row, "tracker")
+		if label == "" {
+			return cs, fmt.Errorf("line %d: no tracker", line)
+		}
+		cs.add(Tracker{Label: label, Issue: strings.ToUpper(field(row, "issue")), Receiver: field(row, "receiver")},
+			Session{Start: start, End: end})
+	}
+}
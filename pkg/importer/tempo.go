@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gxben/clocker/pkg/export"
+)
+
+func init() {
+	Register(Format{
+		Name:       "tempo-json",
+		Title:      "Jira Tempo worklogs (JSON)",
+		Extensions: []string{".tempo.json", ".json"},
+		Importer:   ImporterFunc(TempoJSON),
+	})
+}
+
+// TempoJSON reads worklogs as exported from Tempo, or by clocker itself.
+// The description names the tracker, the issue key otherwise.
+func TempoJSON(r io.Reader) (ChangeSet, error) {
+	cs := ChangeSet{}
+	worklogs := []export.Worklog{}
+	err := json.NewDecoder(r).Decode(&worklogs)
+	if err != nil {
+		return cs, err
+	}
+	for idx, wl := range worklogs {
+		start, err := time.ParseInLocation(export.DateFormat+" 15:04:05", wl.StartDate+" "+wl.StartTime, time.Local)
+		if err != nil {
+			return cs, fmt.Errorf("worklog %d: invalid start %s %s", idx+1, wl.StartDate, wl.StartTime)
+		}
+		label := wl.Description
+		if label == "" {
+			label = wl.IssueKey
+		}
+		end := start.Add(time.Duration(wl.TimeSpentSeconds) * time.Second)
+		cs.add(Tracker{Label: label, Issue: wl.IssueKey}, Session{Start: start, End: end})
+	}
+	return cs, nil
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package invoice renders client-ready PDF invoices from billable lines.
+package invoice
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	DefaultNumberFormat = "INV-%04d"
+	DateFormat          = "2006-01-02"
+)
+
+// Template holds the invoice customization, persisted across runs.
+type Template struct {
+	Logo         string `yaml:"logo,omitempty"`
+	Address      string `yaml:"address,omitempty"`
+	PaymentTerms string `yaml:"payment_terms,omitempty"`
+	NumberFormat string `yaml:"number_format,omitempty"`
+	NextNumber   int    `yaml:"next_number,omitempty"`
+}
+
+type Line struct {
+	Description string
+	Quantity    float64
+	Unit        string
+	Rate        float64
+	Currency    string
+}
+
+type Invoice struct {
+	Number string
+	Date   time.Time
+	Client string
+	Lines  []Line
+}
+
+func (l Line) Amount() float64 {
+	return l.Quantity * l.Rate
+}
+
+// numberFormat is the format of invoice numbers, formats without a verb
+// getting the number appended.
+func (t *Template) numberFormat() string {
+	format := t.NumberFormat
+	if format == "" {
+		format = DefaultNumberFormat
+	}
+	if !strings.Contains(format, "%") {
+		format += "%d"
+	}
+	return format
+}
+
+// Number returns the formatted invoice number for the given sequence value.
+func (t *Template) Number(seq int) string {
+	return fmt.Sprintf(t.numberFormat(), seq)
+}
+
+// CheckNumberFormat tells whether the number format has a single integer
+// verb, e.g. %04d, the sequence value being its only argument.
+func (t *Template) CheckNumberFormat() error {
+	format := t.numberFormat()
+	verbs := 0
+	for idx := 0; idx < len(format); idx++ {
+		if format[idx] != '%' {
+			continue
+		}
+		// flags, width and precision, e.g. %-4 or %04
+		end := idx + 1
+		for end < len(format) && strings.IndexByte("+-# .0123456789", format[end]) >= 0 {
+			end++
+		}
+		if end == len(format) {
+			return fmt.Errorf("invalid invoice number format %q, %s ends without a verb", t.NumberFormat, format[idx:])
+		}
+		verb := format[end]
+		literal := verb == '%' && end == idx+1
+		idx = end
+		if literal {
+			continue
+		}
+		if strings.IndexByte("dboOxX", verb) < 0 {
+			return fmt.Errorf("invalid invoice number format %q, %%%c isn't an integer verb", t.NumberFormat, verb)
+		}
+		verbs++
+	}
+	if verbs != 1 {
+		return fmt.Errorf("invalid invoice number format %q, expected a single integer verb like %%04d", t.NumberFormat)
+	}
+	return nil
+}
+
+// Next consumes the next value of the numbering sequence.
+func (t *Template) Next() string {
+	if t.NextNumber < 1 {
+		t.NextNumber = 1
+	}
+	number := t.Number(t.NextNumber)
+	t.NextNumber++
+	return number
+}
+
+// Totals returns the invoice total for each currency used by its lines.
+func (inv *Invoice) Totals() map[string]float64 {
+	totals := map[string]float64{}
+	for _, l := range inv.Lines {
+		totals[l.Currency] += l.Amount()
+	}
+	return totals
+}
+
+func money(amount float64, currency string) string {
+	return strings.TrimSpace(fmt.Sprintf("%.2f %s", amount, currency))
+}
+
+// Render writes the invoice as a PDF document.
+func Render(w io.Writer, tpl Template, inv Invoice) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	pageWidth, _ := pdf.GetPageSize()
+	left, top, right, _ := pdf.GetMargins()
+	width := pageWidth - left - right
+
+	// header: logo on the left, issuer address on the right
+	if tpl.Logo != "" {
+		pdf.ImageOptions(tpl.Logo, left, top, 40, 0, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+	}
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetXY(left+width/2, top)
+	pdf.MultiCell(width/2, 5, tr(tpl.Address), "", "R", false)
+
+	pdf.SetY(top + 45)
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(width, 10, tr("Invoice "+inv.Number), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(width, 6, tr("Date: "+inv.Date.Format(DateFormat)), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	if inv.Client != "" {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(width, 6, tr("Bill to"), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(width/2, 5, tr(inv.Client), "", "L", false)
+		pdf.Ln(6)
+	}
+
+	// billable lines
+	cols := []float64{width * 0.46, width * 0.16, width * 0.18, width * 0.20}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range []string{"Description", "Quantity", "Rate", "Amount"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(cols[i], 8, h, "B", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, l := range inv.Lines {
+		quantity := fmt.Sprintf("%.2f %s", l.Quantity, l.Unit)
+		pdf.CellFormat(cols[0], 7, tr(l.Description), "", 0, "L", false, 0, "")
+		pdf.CellFormat(cols[1], 7, tr(strings.TrimSpace(quantity)), "", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[2], 7, tr(money(l.Rate, l.Currency)), "", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[3], 7, tr(money(l.Amount(), l.Currency)), "", 1, "R", false, 0, "")
+	}
+
+	// totals, one per currency
+	totals := inv.Totals()
+	currencies := []string{}
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "B", 11)
+	for _, currency := range currencies {
+		pdf.CellFormat(cols[0]+cols[1]+cols[2], 8, "Total", "T", 0, "R", false, 0, "")
+		pdf.CellFormat(cols[3], 8, tr(money(totals[currency], currency)), "T", 1, "R", false, 0, "")
+	}
+
+	if tpl.PaymentTerms != "" {
+		pdf.Ln(10)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(width, 6, tr("Payment terms"), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(width, 5, tr(tpl.PaymentTerms), "", "L", false)
+	}
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package invoice
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	tpl := Template{}
+	if n := tpl.Next(); n != "INV-0001" || tpl.NextNumber != 2 {
+		t.Errorf("first number %q, next %d", n, tpl.NextNumber)
+	}
+	if n := tpl.Next(); n != "INV-0002" {
+		t.Errorf("second number %q", n)
+	}
+
+	// formats without a verb get the number appended
+	tpl = Template{NumberFormat: "2026-", NextNumber: 41}
+	if n := tpl.Next(); n != "2026-41" || tpl.NextNumber != 42 {
+		t.Errorf("number %q, next %d", n, tpl.NextNumber)
+	}
+	tpl = Template{NumberFormat: "ACME/%03d", NextNumber: 7}
+	if n := tpl.Next(); n != "ACME/007" {
+		t.Errorf("number %q", n)
+	}
+}
+
+func TestCheckNumberFormat(t *testing.T) {
+	for format, valid := range map[string]bool{
+		"":           true,
+		"2026-":      true,
+		"ACME/%03d":  true,
+		"%-6x 100%%": true,
+		"%s":         false,
+		"INV-%d-%d":  false,
+		"100%%":      false,
+		"INV-%":      false,
+		"INV-%*d":    false,
+		"INV-%[1]d":  false,
+	} {
+		tpl := Template{NumberFormat: format}
+		if err := tpl.CheckNumberFormat(); (err == nil) != valid {
+			t.Errorf("format %q checked as %v", format, err)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	inv := Invoice{
+		Number: "INV-0001",
+		Date:   time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		Client: "Big Corp\n1 Main Street",
+		Lines: []Line{
+			{Description: "Acme", Quantity: 2.5, Unit: "h", Rate: 80, Currency: "EUR"},
+			{Description: "Acme: train (2026-03-01)", Quantity: 1, Rate: 45.5, Currency: "EUR"},
+			{Description: "Globex", Quantity: 1, Unit: "h", Rate: 100, Currency: "USD"},
+		},
+	}
+	if totals := inv.Totals(); totals["EUR"] != 245.5 || totals["USD"] != 100 {
+		t.Errorf("totals = %v", totals)
+	}
+
+	var out bytes.Buffer
+	err := Render(&out, Template{Address: "Me\nHere", PaymentTerms: "30 days"}, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) || !bytes.Contains(out.Bytes(), []byte("%%EOF")) {
+		t.Errorf("not a PDF document: %q", out.Bytes()[:min(out.Len(), 16)])
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// PasswordEnv holds the SMTP password, kept out of the configuration
+	// file.
+	PasswordEnv = "CLOCKER_SMTP_PASSWORD"
+)
+
+type EmailOptions struct {
+	// Server is the SMTP submission host:port.
+	Server   string   `yaml:"server,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	To       []string `yaml:"to,omitempty"`
+	Username string   `yaml:"username,omitempty"`
+}
+
+// Email sends alerts as plain text mails.
+type Email struct {
+	EmailOptions
+	Password string
+	send     func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmail(o EmailOptions) *Email {
+	return &Email{
+		EmailOptions: o,
+		Password:     os.Getenv(PasswordEnv),
+		send:         smtp.SendMail,
+	}
+}
+
+func (e *Email) Name() string {
+	return ChannelEmail
+}
+
+func (e *Email) Notify(n Notification) error {
+	if e.Server == "" || e.From == "" || len(e.To) == 0 {
+		return errors.New("email server, sender and recipients must be configured")
+	}
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, err := net.SplitHostPort(e.Server)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+	return e.send(e.Server, auth, e.From, e.To, e.message(n))
+}
+
+func (e *Email) message(n Notification) []byte {
+	at := n.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	headers := []string{
+		"From: " + e.From,
+		"To: " + strings.Join(e.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", fmt.Sprintf("[%s] %s", AppName, n.Title)),
+		"Date: " + at.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	body := strings.ReplaceAll(n.Body, "\n", "\r\n")
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body + "\r\n")
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Channels alerts can be routed to.
+const (
+	ChannelDesktop = "desktop"
+	ChannelSound   = "sound"
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+)
+
+var Channels = []string{
+	ChannelDesktop,
+	ChannelSound,
+	ChannelWebhook,
+	ChannelEmail,
+}
+
+// Notification is an alert raised by clocker.
+type Notification struct {
+	// Kind tells what raised it, e.g. budget or idle, alerts being routed
+	// per kind.
+	Kind  string    `json:"kind"`
+	Title string    `json:"title"`
+	Body  string    `json:"body"`
+	At    time.Time `json:"at"`
+}
+
+// Notifier delivers alerts one way or another.
+type Notifier interface {
+	Name() string
+	Notify(n Notification) error
+}
+
+type funcNotifier struct {
+	name   string
+	notify func(n Notification) error
+}
+
+func (f funcNotifier) Name() string {
+	return f.name
+}
+
+func (f funcNotifier) Notify(n Notification) error {
+	return f.notify(n)
+}
+
+// Func turns a plain function into a Notifier.
+func Func(name string, notify func(n Notification) error) Notifier {
+	return funcNotifier{name: name, notify: notify}
+}
+
+// Desktop shows alerts as desktop notifications through the provider.
+func Desktop(p Provider) Notifier {
+	return Func(ChannelDesktop, func(n Notification) error {
+		return p.Notify(n.Title, n.Body)
+	})
+}
+
+// Router sends each alert to the channels configured for its kind.
+type Router struct {
+	Channels map[string]Notifier
+	// Routes list channel names per alert kind, kinds without a route
+	// going to the Default ones. An empty route silences the kind.
+	Routes  map[string][]string
+	Default []string
+}
+
+// Route returns the channels alerts of the given kind go to.
+func (r *Router) Route(kind string) []string {
+	if route, ok := r.Routes[kind]; ok {
+		return route
+	}
+	return r.Default
+}
+
+// Notify delivers the alert to all its channels, reporting the ones that
+// failed.
+func (r *Router) Notify(n Notification) error {
+	if n.At.IsZero() {
+		n.At = time.Now()
+	}
+	errs := []error{}
+	for _, name := range r.Route(n.Kind) {
+		c, ok := r.Channels[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no %s channel configured", name))
+			continue
+		}
+		err := c.Notify(n)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouter(t *testing.T) {
+	got := map[string][]string{}
+	record := func(name string) Notifier {
+		return Func(name, func(n Notification) error {
+			got[name] = append(got[name], n.Kind)
+			if n.At.IsZero() {
+				t.Errorf("%s notified without a time", name)
+			}
+			return nil
+		})
+	}
+	r := &Router{
+		Channels: map[string]Notifier{
+			ChannelDesktop: record(ChannelDesktop),
+			ChannelSound:   record(ChannelSound),
+			ChannelWebhook: record(ChannelWebhook),
+		},
+		Routes: map[string][]string{
+			"budget": {ChannelDesktop, ChannelWebhook},
+			"idle":   {},
+		},
+		Default: []string{ChannelDesktop, ChannelSound},
+	}
+
+	for _, kind := range []string{"budget", "idle", "power"} {
+		err := r.Notify(Notification{Kind: kind, Title: "Alert"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if strings.Join(got[ChannelDesktop], ",") != "budget,power" {
+		t.Errorf("desktop got %v", got[ChannelDesktop])
+	}
+	if strings.Join(got[ChannelSound], ",") != "power" {
+		t.Errorf("sound got %v", got[ChannelSound])
+	}
+	if strings.Join(got[ChannelWebhook], ",") != "budget" {
+		t.Errorf("webhook got %v", got[ChannelWebhook])
+	}
+}
+
+func TestRouterErrors(t *testing.T) {
+	failure := errors.New("no daemon")
+	delivered := false
+	r := &Router{
+		Channels: map[string]Notifier{
+			ChannelDesktop: Func(ChannelDesktop, func(Notification) error { return failure }),
+			ChannelSound:   Func(ChannelSound, func(Notification) error { delivered = true; return nil }),
+		},
+		Default: []string{ChannelDesktop, ChannelEmail, ChannelSound},
+	}
+	err := r.Notify(Notification{Kind: "budget"})
+	if !errors.Is(err, failure) || !strings.Contains(err.Error(), "no email channel") {
+		t.Errorf("Notify() = %v, expected both failures", err)
+	}
+	if !delivered {
+		t.Error("a failing channel stopped the following ones")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var received Notification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer srv.Close()
+
+	n := Notification{Kind: "budget", Title: "Budget alert", Body: "Acme reached 80%", At: time.Now()}
+	err := NewWebhook(WebhookOptions{URL: srv.URL}).Notify(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Kind != n.Kind || received.Title != n.Title || received.Body != n.Body || !received.At.Equal(n.At) {
+		t.Errorf("received %+v, expected %+v", received, n)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	err = NewWebhook(WebhookOptions{URL: failing.URL}).Notify(n)
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Notify() = %v, expected the status", err)
+	}
+}
+
+func TestEmail(t *testing.T) {
+	var addr, from string
+	var to []string
+	var msg []byte
+	e := NewEmail(EmailOptions{
+		Server:   "smtp.example.com:587",
+		From:     "clocker@example.com",
+		To:       []string{"me@example.com", "boss@example.com"},
+		Username: "me",
+	})
+	e.send = func(a string, auth smtp.Auth, f string, t []string, m []byte) error {
+		addr, from, to, msg = a, f, t, m
+		return nil
+	}
+
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	err := e.Notify(Notification{Kind: "budget", Title: "Budget alert", Body: "Acme reached 80%\nof its budget", At: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != "smtp.example.com:587" || from != "clocker@example.com" || len(to) != 2 {
+		t.Errorf("sent to %s from %s to %v", addr, from, to)
+	}
+	text := string(msg)
+	for _, part := range []string{
+		"To: me@example.com, boss@example.com\r\n",
+		"Subject: [Clocker] Budget alert\r\n",
+		"Date: Mon, 02 Mar 2026 09:00:00 +0000\r\n",
+		"\r\n\r\nAcme reached 80%\r\nof its budget\r\n",
+	} {
+		if !strings.Contains(text, part) {
+			t.Errorf("message misses %q:\n%s", part, text)
+		}
+	}
+
+	err = NewEmail(EmailOptions{Server: "smtp.example.com:587"}).Notify(Notification{})
+	if err == nil {
+		t.Error("sent without sender and recipients")
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package notify delivers desktop notifications through the platform
+// notification center, so that they honor do-not-disturb settings.
+package notify
+
+import (
+	"errors"
+)
+
+const (
+	AppName = "Clocker"
+)
+
+var ErrUnsupported = errors.New("native notifications are not supported on this platform")
+
+// Provider delivers a notification to the user.
+type Provider interface {
+	Name() string
+	Notify(title, body string) error
+}
+
+type chain []Provider
+
+func (c chain) Name() string {
+	if len(c) == 0 {
+		return "none"
+	}
+	return c[0].Name()
+}
+
+// Notify tries each provider in turn until one succeeds.
+func (c chain) Notify(title, body string) error {
+	errs := []error{}
+	for _, p := range c {
+		err := p.Notify(title, body)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// New returns a provider relying on the native notification backend,
+// falling back to the given providers when it fails or is unavailable.
+func New(fallbacks ...Provider) Provider {
+	providers := chain{}
+	if native := Native(); native != nil {
+		providers = append(providers, native)
+	}
+	return append(providers, fallbacks...)
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"os"
+	"os/exec"
+)
+
+// The UserNotifications framework requires a signed application bundle,
+// osascript posts to the Notification Center on behalf of any binary and
+// still honors Focus modes. Text is passed through the environment so it
+// never needs AppleScript escaping.
+const osaScript = `display notification (system attribute "CLOCKER_BODY") with title (system attribute "CLOCKER_TITLE")`
+
+type notificationCenter struct{}
+
+func Native() Provider {
+	return notificationCenter{}
+}
+
+func (notificationCenter) Name() string {
+	return "notification center"
+}
+
+func (notificationCenter) Notify(title, body string) error {
+	cmd := exec.Command("osascript", "-e", osaScript)
+	cmd.Env = append(os.Environ(), "CLOCKER_TITLE="+title, "CLOCKER_BODY="+body)
+	return cmd.Run()
+}
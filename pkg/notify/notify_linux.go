@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"os/exec"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.Notifications"
+	dbusPath      = "/org/freedesktop/Notifications"
+	dbusNotify    = "org.freedesktop.Notifications.Notify"
+	defaultExpiry = int32(-1)
+)
+
+// freedesktop talks to the notification daemon over the D-Bus session bus,
+// and shells out to notify-send when the bus is not reachable.
+type freedesktop struct{}
+
+func Native() Provider {
+	return freedesktop{}
+}
+
+func (freedesktop) Name() string {
+	return "freedesktop"
+}
+
+func (freedesktop) Notify(title, body string) error {
+	conn, err := dbus.SessionBus()
+	if err == nil {
+		obj := conn.Object(dbusDest, dbusPath)
+		call := obj.Call(dbusNotify, 0, AppName, uint32(0), "", title, body,
+			[]string{}, map[string]dbus.Variant{}, defaultExpiry)
+		if call.Err == nil {
+			return nil
+		}
+		err = call.Err
+	}
+
+	if _, lookErr := exec.LookPath("notify-send"); lookErr != nil {
+		return err
+	}
+	return exec.Command("notify-send", "--app-name", AppName, title, body).Run()
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+func Native() Provider {
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// toastAppID borrows the PowerShell application identity, unpackaged
+// binaries can't show toasts under their own name.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+const toastScript = `
+$ErrorActionPreference = 'Stop'
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:CLOCKER_TITLE)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode($env:CLOCKER_BODY)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:CLOCKER_APP).Show($toast)
+`
+
+type toast struct{}
+
+func Native() Provider {
+	return toast{}
+}
+
+func (toast) Name() string {
+	return "toast"
+}
+
+func (toast) Notify(title, body string) error {
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	cmd.Env = append(os.Environ(), "CLOCKER_TITLE="+title, "CLOCKER_BODY="+body, "CLOCKER_APP="+toastAppID)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd.Run()
+}
@@ -0,0 +1,16 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"os/exec"
+)
+
+// PlaySound plays a WAV file and waits for it to end.
+func PlaySound(file string) error {
+	return exec.Command("afplay", file).Run()
+}
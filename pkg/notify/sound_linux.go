@@ -0,0 +1,26 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"os/exec"
+)
+
+// soundPlayers ship with PulseAudio, PipeWire and ALSA respectively.
+var soundPlayers = []string{"paplay", "pw-play", "aplay"}
+
+// PlaySound plays a WAV file and waits for it to end.
+func PlaySound(file string) error {
+	for _, player := range soundPlayers {
+		bin, err := exec.LookPath(player)
+		if err != nil {
+			continue
+		}
+		return exec.Command(bin, file).Run()
+	}
+	return ErrUnsupported
+}
@@ -0,0 +1,23 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const soundScript = `(New-Object Media.SoundPlayer $env:CLOCKER_SOUND).PlaySync()`
+
+// PlaySound plays a WAV file and waits for it to end.
+func PlaySound(file string) error {
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-Command", soundScript)
+	cmd.Env = append(os.Environ(), "CLOCKER_SOUND="+file)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd.Run()
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	WebhookTimeout = 10 * time.Second
+)
+
+type WebhookOptions struct {
+	URL string `yaml:"url,omitempty"`
+}
+
+// Webhook posts alerts as JSON, e.g. to a chat incoming webhook relay.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhook(o WebhookOptions) *Webhook {
+	return &Webhook{
+		URL:    o.URL,
+		Client: &http.Client{Timeout: WebhookTimeout},
+	}
+}
+
+func (w *Webhook) Name() string {
+	return ChannelWebhook
+}
+
+func (w *Webhook) Notify(n Notification) error {
+	if w.URL == "" {
+		return fmt.Errorf("no webhook URL configured")
+	}
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	resp, err := w.Client.Post(w.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build darwin || windows
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package power
+
+import (
+	"sync"
+	"time"
+)
+
+// PollInterval is how often platforms without change notifications are
+// queried.
+const PollInterval = time.Minute
+
+// poller reads the battery level at regular intervals, emitting an event
+// whenever it changes.
+type poller struct {
+	name   string
+	read   func() (Battery, error)
+	events chan Event
+	done   chan struct{}
+	close  sync.Once
+}
+
+func newPoller(name string, read func() (Battery, error)) (Monitor, error) {
+	level, err := read()
+	if err != nil {
+		return nil, err
+	}
+	p := &poller{
+		name:   name,
+		read:   read,
+		events: make(chan Event, 1),
+		done:   make(chan struct{}),
+	}
+	go p.run(level)
+	return p, nil
+}
+
+func (p *poller) Name() string {
+	return p.name
+}
+
+func (p *poller) Events() <-chan Event {
+	return p.events
+}
+
+func (p *poller) Close() error {
+	p.close.Do(func() {
+		close(p.done)
+	})
+	return nil
+}
+
+func (p *poller) run(level Battery) {
+	defer close(p.events)
+
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := p.read()
+		if err != nil || current == level {
+			continue
+		}
+		level = current
+		select {
+		case p.events <- Event{Battery: level}:
+		case <-p.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package power reports battery levels and imminent system shutdowns.
+package power
+
+import (
+	"errors"
+)
+
+var ErrUnsupported = errors.New("power state monitoring not supported on this platform")
+
+// Battery is the charge level, as far as running on battery goes.
+type Battery int
+
+const (
+	BatteryNormal Battery = iota
+	BatteryLow
+	BatteryCritical
+)
+
+func (b Battery) String() string {
+	switch b {
+	case BatteryLow:
+		return "low"
+	case BatteryCritical:
+		return "critical"
+	}
+	return "normal"
+}
+
+// Event reports a change of the battery level, or that the system is
+// about to shut down or sleep.
+type Event struct {
+	Battery  Battery
+	Shutdown bool
+	// Release, when set on a shutdown, lets the system go on once the
+	// state is saved. It is held back until then.
+	Release func()
+}
+
+// Monitor delivers power events until closed.
+type Monitor interface {
+	Name() string
+	Events() <-chan Event
+	Close() error
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package power
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+// thresholds matching the macOS low battery warnings
+const (
+	lowPercent      = 10
+	criticalPercent = 5
+)
+
+var pmsetBattery = regexp.MustCompile(`(\d+)%; discharging`)
+
+func New() (Monitor, error) {
+	return newPoller("pmset", readBattery)
+}
+
+func readBattery() (Battery, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return BatteryNormal, err
+	}
+	// charging, charged or on AC power
+	m := pmsetBattery.FindSubmatch(out)
+	if m == nil {
+		return BatteryNormal, nil
+	}
+	percent, _ := strconv.Atoi(string(m[1]))
+	switch {
+	case percent <= criticalPercent:
+		return BatteryCritical, nil
+	case percent <= lowPercent:
+		return BatteryLow, nil
+	}
+	return BatteryNormal, nil
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package power
+
+import (
+	"log"
+	"os"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	logindDest    = "org.freedesktop.login1"
+	logindPath    = "/org/freedesktop/login1"
+	logindManager = "org.freedesktop.login1.Manager"
+	upowerDest    = "org.freedesktop.UPower"
+	upowerDisplay = "/org/freedesktop/UPower/devices/DisplayDevice"
+	upowerDevice  = "org.freedesktop.UPower.Device"
+	dbusProps     = "org.freedesktop.DBus.Properties"
+)
+
+// UPower warning levels
+const (
+	warningLow      = 3
+	warningCritical = 4
+)
+
+// freedesktop listens to logind for shutdowns and UPower for the battery
+// level, on the system bus. A delay inhibitor holds shutdowns back until
+// the state is saved.
+type freedesktop struct {
+	conn    *dbus.Conn
+	signals chan *dbus.Signal
+	events  chan Event
+	close   sync.Once
+
+	lock      sync.Mutex
+	inhibitor *os.File
+}
+
+func New() (Monitor, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.AddMatchSignal(
+		dbus.WithMatchInterface(logindManager),
+		dbus.WithMatchMember("PrepareForShutdown"),
+	)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// UPower is missing on machines without battery, which is fine
+	_ = conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(upowerDisplay),
+		dbus.WithMatchInterface(dbusProps),
+		dbus.WithMatchMember("PropertiesChanged"),
+	)
+
+	m := &freedesktop{
+		conn:    conn,
+		signals: make(chan *dbus.Signal, 10),
+		events:  make(chan Event, 1),
+	}
+	conn.Signal(m.signals)
+	m.inhibit()
+	go m.run()
+	return m, nil
+}
+
+// inhibit takes a delay lock on shutdowns, released once saved. Without
+// it, logind doesn't wait for us before shutting down.
+func (m *freedesktop) inhibit() {
+	var fd dbus.UnixFD
+	err := m.conn.Object(logindDest, logindPath).Call(logindManager+".Inhibit", 0,
+		"shutdown", "Clocker", "Saving running trackers", "delay").Store(&fd)
+	if err != nil {
+		log.Println("Can't delay shutdowns:", err)
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.inhibitor != nil {
+		m.inhibitor.Close()
+	}
+	m.inhibitor = os.NewFile(uintptr(fd), "inhibitor")
+}
+
+// release lets a pending shutdown go on.
+func (m *freedesktop) release() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.inhibitor != nil {
+		m.inhibitor.Close()
+		m.inhibitor = nil
+	}
+}
+
+func (m *freedesktop) Name() string {
+	return "logind/upower"
+}
+
+func (m *freedesktop) Events() <-chan Event {
+	return m.events
+}
+
+func (m *freedesktop) Close() error {
+	m.close.Do(func() {
+		m.conn.RemoveSignal(m.signals)
+		m.conn.Close()
+		m.release()
+	})
+	return nil
+}
+
+func (m *freedesktop) battery() Battery {
+	v, err := m.conn.Object(upowerDest, upowerDisplay).GetProperty(upowerDevice + ".WarningLevel")
+	if err != nil {
+		return BatteryNormal
+	}
+	level, _ := v.Value().(uint32)
+	switch {
+	case level >= warningCritical:
+		return BatteryCritical
+	case level == warningLow:
+		return BatteryLow
+	}
+	return BatteryNormal
+}
+
+func (m *freedesktop) run() {
+	defer close(m.events)
+
+	level := m.battery()
+	for sig := range m.signals {
+		e := Event{Battery: level}
+		switch sig.Name {
+		case logindManager + ".PrepareForShutdown":
+			if len(sig.Body) == 0 {
+				continue
+			}
+			// emitted with false when the shutdown gets cancelled
+			if start, _ := sig.Body[0].(bool); !start {
+				m.inhibit()
+				continue
+			}
+			e.Shutdown = true
+			e.Release = m.release
+		case dbusProps + ".PropertiesChanged":
+			current := m.battery()
+			if current == level {
+				continue
+			}
+			level = current
+			e.Battery = level
+		default:
+			continue
+		}
+		m.events <- e
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package power
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var getSystemPowerStatus = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+// systemPowerStatus is SYSTEM_POWER_STATUS
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// battery flags
+const (
+	batteryLow      = 2
+	batteryCritical = 4
+	batteryNone     = 128
+	batteryUnknown  = 255
+	acOnline        = 1
+)
+
+func New() (Monitor, error) {
+	return newPoller("GetSystemPowerStatus", readBattery)
+}
+
+func readBattery() (Battery, error) {
+	var status systemPowerStatus
+	r, _, err := getSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status)))
+	if r == 0 {
+		return BatteryNormal, err
+	}
+	switch {
+	case status.ACLineStatus == acOnline, status.BatteryFlag == batteryUnknown, status.BatteryFlag&batteryNone != 0:
+		return BatteryNormal, nil
+	case status.BatteryFlag&batteryCritical != 0:
+		return BatteryCritical, nil
+	case status.BatteryFlag&batteryLow != 0:
+		return BatteryLow, nil
+	}
+	return BatteryNormal, nil
+}
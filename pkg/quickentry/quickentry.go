@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package quickentry parses short phrases describing past work, such as
+// "2h on Project X yesterday" or "Project X 09:00-11:30", into sessions.
+package quickentry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DayStart is the hour sessions given by a duration start at on past
+	// days.
+	DayStart = 9
+)
+
+var (
+	ErrNoLabel = errors.New("no tracker given")
+	ErrNoTime  = errors.New("no duration or time range given, e.g. 2h or 09:00-11:30")
+	ErrFuture  = errors.New("the session would end in the future")
+)
+
+var (
+	dateExpr     = regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2})\b`)
+	rangeExpr    = regexp.MustCompile(`(?i)\b(?:from\s+)?(\d{1,2}(?::\d{2})?)\s*(?:-|–|\bto\b|\buntil\b)\s*(\d{1,2}(?::\d{2})?)\b`)
+	atExpr       = regexp.MustCompile(`(?i)\b(?:(?:at|from)\s+)?(\d{1,2}:\d{2})\b`)
+	durationExpr = regexp.MustCompile(`(?i)\b(\d+(?:[.,]\d+)?)\s*(?:h|hrs?|hours?)(?:\s*(\d+)\s*(?:m|mins?|minutes?)?)?\b|\b(\d+)\s*(?:m|mins?|minutes?)\b`)
+	dayExpr      = regexp.MustCompile(`(?i)\b(?:(last)\s+)?(today|yesterday|monday|tuesday|wednesday|thursday|friday|saturday|sunday)\b`)
+	// connectors left around the label once the rest is taken out
+	connectorExpr = regexp.MustCompile(`(?i)^(?:on|for|in|at|from)\s+|\s+(?:on|for|in|at|from)$`)
+)
+
+// Entry is a session of the tracker with the given label.
+type Entry struct {
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+func (e Entry) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %s-%s", e.Label, e.Start.Format("2006-01-02 15:04"), e.End.Format("15:04"))
+}
+
+// take removes the first match of expr from phrase, returning its groups.
+func take(phrase *string, expr *regexp.Regexp) []string {
+	loc := expr.FindStringSubmatchIndex(*phrase)
+	if loc == nil {
+		return nil
+	}
+	groups := make([]string, len(loc)/2)
+	for i := range groups {
+		if loc[2*i] >= 0 {
+			groups[i] = (*phrase)[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	*phrase = (*phrase)[:loc[0]] + " " + (*phrase)[loc[1]:]
+	return groups
+}
+
+// clockTime returns the hour and minute of "9", "9:30" or "09:30".
+func clockTime(s string) (int, int, error) {
+	hours, minutes, _ := strings.Cut(s, ":")
+	h, err := strconv.Atoi(hours)
+	if err != nil || h > 23 {
+		return 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+	m := 0
+	if minutes != "" {
+		m, err = strconv.Atoi(minutes)
+		if err != nil || m > 59 {
+			return 0, 0, fmt.Errorf("invalid time %q", s)
+		}
+	}
+	return h, m, nil
+}
+
+func duration(groups []string) (time.Duration, error) {
+	if groups[3] != "" {
+		m, err := strconv.Atoi(groups[3])
+		return time.Duration(m) * time.Minute, err
+	}
+	h, err := strconv.ParseFloat(strings.Replace(groups[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(h * float64(time.Hour))
+	if groups[2] != "" {
+		m, err := strconv.Atoi(groups[2])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(m) * time.Minute
+	}
+	return d, nil
+}
+
+// day returns the midnight the words point to, weekdays being the last
+// ones up to today, or before today when said "last".
+func day(groups []string, today time.Time) time.Time {
+	switch strings.ToLower(groups[2]) {
+	case "today":
+		return today
+	case "yesterday":
+		return today.AddDate(0, 0, -1)
+	}
+	weekday := time.Sunday
+	for !strings.EqualFold(weekday.String(), groups[2]) {
+		weekday++
+	}
+	back := (int(today.Weekday()) - int(weekday) + 7) % 7
+	if back == 0 && groups[1] != "" {
+		back = 7
+	}
+	return today.AddDate(0, 0, -back)
+}
+
+// Parse reads the phrase relative to now. Sessions only given a duration
+// end now when done today, and start at DayStart on other days.
+func Parse(phrase string, now time.Time) (Entry, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date, dated := today, false
+	if groups := take(&phrase, dateExpr); groups != nil {
+		d, err := time.ParseInLocation(time.DateOnly, groups[1], now.Location())
+		if err != nil {
+			return Entry{}, fmt.Errorf("invalid date %q", groups[1])
+		}
+		date, dated = d, true
+	}
+	if groups := take(&phrase, dayExpr); groups != nil && !dated {
+		date = day(groups, today)
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location())
+	}
+
+	var e Entry
+	if groups := take(&phrase, rangeExpr); groups != nil {
+		fromHour, fromMinute, err := clockTime(groups[1])
+		if err != nil {
+			return Entry{}, err
+		}
+		toHour, toMinute, err := clockTime(groups[2])
+		if err != nil {
+			return Entry{}, err
+		}
+		e.Start, e.End = at(fromHour, fromMinute), at(toHour, toMinute)
+		if !e.End.After(e.Start) {
+			// past midnight
+			e.End = e.End.AddDate(0, 0, 1)
+		}
+	} else if groups := take(&phrase, durationExpr); groups != nil {
+		d, err := duration(groups)
+		if err != nil || d <= 0 {
+			return Entry{}, fmt.Errorf("invalid duration %q", strings.TrimSpace(groups[0]))
+		}
+		switch start := take(&phrase, atExpr); {
+		case start != nil:
+			hour, minute, err := clockTime(start[1])
+			if err != nil {
+				return Entry{}, err
+			}
+			e.Start = at(hour, minute)
+		case date.Equal(today):
+			e.Start = now.Add(-d)
+		default:
+			e.Start = at(DayStart, 0)
+		}
+		e.End = e.Start.Add(d)
+	} else {
+		return Entry{}, ErrNoTime
+	}
+	if e.End.After(now) {
+		return Entry{}, ErrFuture
+	}
+
+	label := strings.Join(strings.Fields(phrase), " ")
+	for {
+		trimmed := connectorExpr.ReplaceAllString(label, "")
+		if trimmed == label {
+			break
+		}
+		label = trimmed
+	}
+	if label == "" {
+		return Entry{}, ErrNoLabel
+	}
+	e.Label = label
+	return e, nil
+}
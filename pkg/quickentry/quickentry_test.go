@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package quickentry
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// a wednesday
+	now := time.Date(2026, 3, 4, 17, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		phrase     string
+		label      string
+		start, end time.Time
+	}{
+		{"2h on Project X yesterday", "Project X", at(3, 9, 0), at(3, 11, 0)},
+		{"Project X 09:00-11:30", "Project X", at(4, 9, 0), at(4, 11, 30)},
+		{"Project X from 9:00 to 11:30 yesterday", "Project X", at(3, 9, 0), at(3, 11, 30)},
+		{"acme 22-1 monday", "acme", at(2, 22, 0), at(3, 1, 0)},
+		{"1h30 for Acme", "Acme", at(4, 15, 30), at(4, 17, 0)},
+		{"1.5 hours Acme", "Acme", at(4, 15, 30), at(4, 17, 0)},
+		{"45 min on Acme at 14:00", "Acme", at(4, 14, 0), at(4, 14, 45)},
+		{"Acme 2h last wednesday", "Acme", time.Date(2026, 2, 25, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 25, 11, 0, 0, 0, time.UTC)},
+		{"Acme 2h on 2026-03-01", "Acme", at(1, 9, 0), at(1, 11, 0)},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.phrase, now)
+		if err != nil {
+			t.Errorf("Parse(%q) = %v", tt.phrase, err)
+			continue
+		}
+		if e.Label != tt.label || !e.Start.Equal(tt.start) || !e.End.Equal(tt.end) {
+			t.Errorf("Parse(%q) = %s, expected %s %s-%s", tt.phrase, e, tt.label, tt.start.Format(time.DateTime), tt.end.Format(time.DateTime))
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2026, 3, 4, 17, 0, 0, 0, time.UTC)
+	tests := []struct {
+		phrase string
+		err    error
+	}{
+		{"Project X", ErrNoTime},
+		{"2h yesterday", ErrNoLabel},
+		{"Acme 16:00-18:00", ErrFuture},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.phrase, now)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) = %v, expected %v", tt.phrase, err, tt.err)
+		}
+	}
+	if _, err := Parse("Acme 25:00-26:00", now); err == nil {
+		t.Error("parsed an invalid time")
+	}
+}
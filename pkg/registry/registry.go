@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package registry keeps file formats registered by name, found back by
+// name, title or file extension.
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Info describes a registered format.
+type Info struct {
+	Name  string
+	Title string
+	// Extensions recognize the format from a file name.
+	Extensions []string
+}
+
+// Registry holds formats of type T, described by info.
+type Registry[T any] struct {
+	kind    string
+	info    func(T) Info
+	formats map[string]T
+}
+
+// New returns an empty registry, kind naming it in errors.
+func New[T any](kind string, info func(T) Info) *Registry[T] {
+	return &Registry[T]{kind: kind, info: info, formats: map[string]T{}}
+}
+
+// Register makes a format available. Registering a name twice is a
+// programming error.
+func (r *Registry[T]) Register(f T) {
+	name := r.info(f).Name
+	if _, ok := r.formats[name]; ok {
+		panic(fmt.Sprintf("%s: format %s registered twice", r.kind, name))
+	}
+	r.formats[name] = f
+}
+
+// List returns the registered formats, sorted by title.
+func (r *Registry[T]) List() []T {
+	list := []T{}
+	for _, f := range r.formats {
+		list = append(list, f)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return r.info(list[i]).Title < r.info(list[j]).Title
+	})
+	return list
+}
+
+// Lookup finds a format by name or title.
+func (r *Registry[T]) Lookup(name string) (T, bool) {
+	if f, ok := r.formats[name]; ok {
+		return f, true
+	}
+	for _, f := range r.formats {
+		if r.info(f).Title == name {
+			return f, true
+		}
+	}
+	var none T
+	return none, false
+}
+
+// Detect finds the format of a file from its name, the longest matching
+// extension winning, so that week.tempo.json is told apart from week.json.
+func (r *Registry[T]) Detect(file string) (T, bool) {
+	file = strings.ToLower(file)
+	var found T
+	length := 0
+	for _, f := range r.formats {
+		for _, ext := range r.info(f).Extensions {
+			if len(ext) > length && strings.HasSuffix(file, ext) {
+				found, length = f, len(ext)
+			}
+		}
+	}
+	return found, length > 0
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package registry
+
+import (
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := New("test", func(i Info) Info { return i })
+	r.Register(Info{Name: "json", Title: "Plain JSON", Extensions: []string{".json"}})
+	r.Register(Info{Name: "tempo-json", Title: "Jira Tempo", Extensions: []string{".tempo.json"}})
+
+	if list := r.List(); len(list) != 2 || list[0].Name != "tempo-json" {
+		t.Errorf("List() = %+v, expected sorted by title", list)
+	}
+	if f, ok := r.Lookup("Jira Tempo"); !ok || f.Name != "tempo-json" {
+		t.Errorf("Lookup(title) = %+v, %v", f, ok)
+	}
+	if _, ok := r.Lookup("csv"); ok {
+		t.Error("found an unknown format")
+	}
+	if f, ok := r.Detect("WEEK.TEMPO.JSON"); !ok || f.Name != "tempo-json" {
+		t.Errorf("Detect() = %+v, %v, expected the longest extension", f, ok)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registered a name twice")
+		}
+	}()
+	r.Register(Info{Name: "json"})
+}
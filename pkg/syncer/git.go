@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DefaultRemote = "origin"
+	DefaultBranch = "main"
+	DefaultFile   = "clocker.yaml"
+)
+
+type GitOptions struct {
+	// Dir is a clone dedicated to clocker, its working tree gets reset on
+	// each push.
+	Dir    string
+	Remote string
+	Branch string
+	File   string
+}
+
+// gitProvider keeps the remote copy as a file committed on a branch, the
+// commit standing for the revision.
+type gitProvider struct {
+	resolver
+	GitOptions
+}
+
+func NewGit(o GitOptions, merge Merger) Provider {
+	if o.Remote == "" {
+		o.Remote = DefaultRemote
+	}
+	if o.Branch == "" {
+		o.Branch = DefaultBranch
+	}
+	if o.File == "" {
+		o.File = DefaultFile
+	}
+	return &gitProvider{resolver: resolver{merge}, GitOptions: o}
+}
+
+func (p *gitProvider) Name() string {
+	return "git"
+}
+
+func (p *gitProvider) git(ctx context.Context, args ...string) (string, error) {
+	out, err := p.run(ctx, args...)
+	return strings.TrimSpace(string(out)), err
+}
+
+func (p *gitProvider) run(ctx context.Context, args ...string) ([]byte, error) {
+	if p.Dir == "" {
+		return nil, errors.New("no git repository configured")
+	}
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", p.Dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return stdout.Bytes(), fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func (p *gitProvider) Pull(ctx context.Context) (Document, error) {
+	heads, err := p.git(ctx, "ls-remote", "--heads", p.Remote, p.Branch)
+	if err != nil || heads == "" {
+		return Document{}, err
+	}
+	_, err = p.git(ctx, "fetch", "-q", p.Remote, p.Branch)
+	if err != nil {
+		return Document{}, err
+	}
+	rev, err := p.git(ctx, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return Document{}, err
+	}
+	data, err := p.run(ctx, "show", rev+":"+p.File)
+	if err != nil {
+		// the branch exists without the file yet
+		return Document{Revision: rev}, nil
+	}
+	return Document{Data: data, Revision: rev}, nil
+}
+
+func (p *gitProvider) Push(ctx context.Context, doc Document) (Document, error) {
+	if doc.Revision != "" {
+		_, err := p.git(ctx, "reset", "-q", "--hard", doc.Revision)
+		if err != nil {
+			return Document{}, err
+		}
+	}
+	err := os.WriteFile(filepath.Join(p.Dir, p.File), doc.Data, 0o600)
+	if err != nil {
+		return Document{}, err
+	}
+	_, err = p.git(ctx, "add", p.File)
+	if err != nil {
+		return Document{}, err
+	}
+	if _, err = p.git(ctx, "diff", "--cached", "--quiet"); err != nil {
+		host, _ := os.Hostname()
+		_, err = p.git(ctx, "commit", "-q", "-m", "Sync from "+host)
+		if err != nil {
+			return Document{}, err
+		}
+	}
+	_, err = p.git(ctx, "push", "-q", p.Remote, "HEAD:refs/heads/"+p.Branch)
+	if err != nil {
+		if strings.Contains(err.Error(), "rejected") {
+			return Document{}, ErrConflict
+		}
+		return Document{}, err
+	}
+	rev, err := p.git(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		return Document{}, err
+	}
+	return Document{Data: doc.Data, Revision: rev}, nil
+}
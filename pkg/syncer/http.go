@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	HTTPTimeout = 30 * time.Second
+
+	// TokenEnv holds the bearer token of the HTTP API.
+	TokenEnv = "CLOCKER_SYNC_TOKEN"
+	// PasswordEnv holds the WebDAV password.
+	PasswordEnv = "CLOCKER_SYNC_PASSWORD"
+)
+
+// httpProvider stores the remote copy as a single resource, its entity tag
+// standing for the revision.
+type httpProvider struct {
+	resolver
+	name      string
+	url       string
+	client    *http.Client
+	authorize func(r *http.Request)
+}
+
+// NewHTTP syncs with the document served at url by the HTTP API.
+func NewHTTP(url string, merge Merger) Provider {
+	token := os.Getenv(TokenEnv)
+	return &httpProvider{
+		resolver: resolver{merge},
+		name:     "http",
+		url:      url,
+		client:   &http.Client{Timeout: HTTPTimeout},
+		authorize: func(r *http.Request) {
+			if token != "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+		},
+	}
+}
+
+// NewWebDAV syncs with a file on a WebDAV share, e.g. Nextcloud, within an
+// existing folder.
+func NewWebDAV(url, username string, merge Merger) Provider {
+	password := os.Getenv(PasswordEnv)
+	return &httpProvider{
+		resolver: resolver{merge},
+		name:     "webdav",
+		url:      url,
+		client:   &http.Client{Timeout: HTTPTimeout},
+		authorize: func(r *http.Request) {
+			if username != "" {
+				r.SetBasicAuth(username, password)
+			}
+		},
+	}
+}
+
+func (p *httpProvider) Name() string {
+	return p.name
+}
+
+func (p *httpProvider) do(ctx context.Context, method string, body []byte, header http.Header) (*http.Response, error) {
+	if p.url == "" {
+		return nil, fmt.Errorf("no %s URL configured", p.name)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	p.authorize(req)
+	return p.client.Do(req)
+}
+
+func (p *httpProvider) Pull(ctx context.Context) (Document, error) {
+	resp, err := p.do(ctx, http.MethodGet, nil, nil)
+	if err != nil {
+		return Document{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return Document{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Document{}, fmt.Errorf("%s pull answered %s", p.name, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Document{}, err
+	}
+	return Document{Data: data, Revision: resp.Header.Get("ETag")}, nil
+}
+
+func (p *httpProvider) Push(ctx context.Context, doc Document) (Document, error) {
+	header := http.Header{"Content-Type": {"application/yaml"}}
+	if doc.Revision == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", doc.Revision)
+	}
+	resp, err := p.do(ctx, http.MethodPut, doc.Data, header)
+	if err != nil {
+		return Document{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return Document{}, ErrConflict
+	}
+	if resp.StatusCode/100 != 2 {
+		return Document{}, fmt.Errorf("%s push answered %s", p.name, resp.Status)
+	}
+	// servers not returning the new tag get merged with on next sync,
+	// which leaves the data as is
+	return Document{Data: doc.Data, Revision: resp.Header.Get("ETag")}, nil
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// Attempts bounds the pull, merge and push rounds of a sync, when the
+	// remote copy keeps changing under it.
+	Attempts = 3
+	// Delay gathers the triggers coming in a row, e.g. from successive
+	// saves, into a single sync.
+	Delay = 2 * time.Second
+)
+
+type Status struct {
+	Syncing bool
+	Synced  time.Time
+	Err     error
+}
+
+func (s Status) String() string {
+	switch {
+	case s.Syncing:
+		return "Syncing…"
+	case s.Err != nil:
+		return fmt.Sprintf("Sync failed: %s", s.Err)
+	case s.Synced.IsZero():
+		return "Not synced yet"
+	}
+	return "Synced at " + s.Synced.Format("15:04")
+}
+
+// Scheduler syncs the local data on demand, on trigger (e.g. on save) and
+// on a regular interval.
+type Scheduler struct {
+	Provider Provider
+	// Interval between syncs, 0 to only sync when triggered.
+	Interval time.Duration
+	// Local returns the local data to push.
+	Local func() ([]byte, error)
+	// Apply receives the local data merged with remote changes.
+	Apply func(data []byte) error
+	// OnStatus is called whenever a sync starts or ends.
+	OnStatus func(s Status)
+
+	trigger chan struct{}
+	// syncing serializes syncs asked on demand with the scheduled ones
+	syncing sync.Mutex
+
+	mu       sync.Mutex
+	status   Status
+	revision string
+}
+
+func NewScheduler(p Provider, interval time.Duration, local func() ([]byte, error), apply func([]byte) error) *Scheduler {
+	return &Scheduler{
+		Provider: p,
+		Interval: interval,
+		Local:    local,
+		Apply:    apply,
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *Scheduler) setStatus(status Status) {
+	s.mu.Lock()
+	s.status = status
+	s.mu.Unlock()
+	if s.OnStatus != nil {
+		s.OnStatus(status)
+	}
+}
+
+// Trigger asks Run for a sync, without waiting for it.
+func (s *Scheduler) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run syncs on start, then on trigger and interval, until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if s.Interval > 0 {
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	delay := time.NewTimer(0)
+	defer delay.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.trigger:
+			delay.Reset(Delay)
+		case <-tick:
+			_ = s.Sync(ctx)
+		case <-delay.C:
+			_ = s.Sync(ctx)
+		}
+	}
+}
+
+// Sync pulls the remote copy, merges it with the local data if it changed
+// since last sync, and pushes the result.
+func (s *Scheduler) Sync(ctx context.Context) error {
+	s.syncing.Lock()
+	defer s.syncing.Unlock()
+	s.setStatus(Status{Syncing: true, Synced: s.Status().Synced})
+	err := s.sync(ctx)
+	status := Status{Synced: time.Now(), Err: err}
+	if err != nil {
+		status.Synced = s.Status().Synced
+	}
+	s.setStatus(status)
+	return err
+}
+
+func (s *Scheduler) sync(ctx context.Context) error {
+	for range Attempts {
+		remote, err := s.Provider.Pull(ctx)
+		if err != nil {
+			return err
+		}
+		local, err := s.Local()
+		if err != nil {
+			return err
+		}
+
+		doc := Document{Data: local, Revision: remote.Revision}
+		if remote.Revision != "" && remote.Revision != s.revision {
+			// changed elsewhere since last sync
+			doc, err = s.Provider.Resolve(ctx, Document{Data: local, Revision: s.revision}, remote)
+			if err != nil {
+				return err
+			}
+			err = s.Apply(doc.Data)
+			if err != nil {
+				return err
+			}
+		}
+		if remote.Revision != "" && bytes.Equal(doc.Data, remote.Data) {
+			s.revision = remote.Revision
+			return nil
+		}
+
+		pushed, err := s.Provider.Push(ctx, doc)
+		if errors.Is(err, ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.revision = pushed.Revision
+		return nil
+	}
+	return fmt.Errorf("%s: %w after %d attempts", s.Provider.Name(), ErrConflict, Attempts)
+}
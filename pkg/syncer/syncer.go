@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package syncer keeps a document in sync with a remote copy, merging the
+// changes made elsewhere before pushing the local ones.
+package syncer
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrConflict is returned by Push when the remote copy changed since
+	// the revision the document is based on.
+	ErrConflict = errors.New("remote copy changed meanwhile")
+)
+
+// Document is a copy of the synced data, at a given remote revision. An
+// empty revision stands for a document never pushed.
+type Document struct {
+	Data     []byte
+	Revision string
+}
+
+// Merger combines local and remote data, both sides' changes being kept.
+type Merger func(local, remote []byte) ([]byte, error)
+
+// Provider stores the remote copy.
+type Provider interface {
+	Name() string
+	// Pull returns the remote copy, empty if there is none yet.
+	Pull(ctx context.Context) (Document, error)
+	// Push replaces the remote copy, provided it is still at the revision
+	// of the document, and returns the new revision.
+	Push(ctx context.Context, doc Document) (Document, error)
+	// Resolve merges local changes into the remote copy, resulting in a
+	// document to push on top of it.
+	Resolve(ctx context.Context, local, remote Document) (Document, error)
+}
+
+// resolver implements Resolve with a Merger, providers storing opaque
+// copies having no better way to combine them.
+type resolver struct {
+	merge Merger
+}
+
+func (r resolver) Resolve(ctx context.Context, local, remote Document) (Document, error) {
+	if err := ctx.Err(); err != nil {
+		return Document{}, err
+	}
+	if len(remote.Data) == 0 {
+		return Document{Data: local.Data, Revision: remote.Revision}, nil
+	}
+	if r.merge == nil {
+		return Document{}, errors.New("no way to merge remote changes")
+	}
+	data, err := r.merge(local.Data, remote.Data)
+	if err != nil {
+		return Document{}, err
+	}
+	return Document{Data: data, Revision: remote.Revision}, nil
+}
@@ -0,0 +1,306 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syncer
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// union merges documents holding one item per line.
+func union(local, remote []byte) ([]byte, error) {
+	lines := strings.Fields(string(local) + " " + string(remote))
+	slices.Sort(lines)
+	return []byte(strings.Join(slices.Compact(lines), "\n") + "\n"), nil
+}
+
+// memory keeps the remote copy in memory, the number of pushes standing
+// for the revision.
+type memory struct {
+	resolver
+	doc    Document
+	pushes int
+	// race pushes a concurrent change before the next push, once
+	race []byte
+}
+
+func (m *memory) Name() string {
+	return "memory"
+}
+
+func (m *memory) Pull(ctx context.Context) (Document, error) {
+	return m.doc, nil
+}
+
+func (m *memory) put(data []byte) {
+	m.pushes++
+	m.doc = Document{Data: data, Revision: fmt.Sprint(m.pushes)}
+}
+
+func (m *memory) Push(ctx context.Context, doc Document) (Document, error) {
+	if m.race != nil {
+		m.put(m.race)
+		m.race = nil
+	}
+	if doc.Revision != m.doc.Revision {
+		return Document{}, ErrConflict
+	}
+	m.put(doc.Data)
+	return m.doc, nil
+}
+
+type replica struct {
+	data []byte
+}
+
+func (r *replica) local() ([]byte, error) {
+	return r.data, nil
+}
+
+func (r *replica) apply(data []byte) error {
+	r.data = data
+	return nil
+}
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	remote := &memory{resolver: resolver{union}}
+	laptop := &replica{[]byte("a\nb\n")}
+	desktop := &replica{[]byte("c\n")}
+	l := NewScheduler(remote, 0, laptop.local, laptop.apply)
+	d := NewScheduler(remote, 0, desktop.local, desktop.apply)
+
+	// first push
+	if err := l.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if remote.pushes != 1 || string(remote.doc.Data) != "a\nb\n" {
+		t.Fatalf("remote = %q after %d pushes", remote.doc.Data, remote.pushes)
+	}
+
+	// nothing changed, nothing pushed
+	if err := l.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if remote.pushes != 1 {
+		t.Errorf("pushed %d times, expected unchanged data left alone", remote.pushes)
+	}
+
+	// another replica merges before pushing
+	if err := d.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if string(desktop.data) != "a\nb\nc\n" || string(remote.doc.Data) != "a\nb\nc\n" {
+		t.Fatalf("desktop = %q, remote = %q, expected both merged", desktop.data, remote.doc.Data)
+	}
+
+	// the first one gets the merged copy back
+	laptop.data = []byte("a\nb\nd\n")
+	if err := l.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if string(laptop.data) != "a\nb\nc\nd\n" || string(remote.doc.Data) != "a\nb\nc\nd\n" {
+		t.Errorf("laptop = %q, remote = %q", laptop.data, remote.doc.Data)
+	}
+	if status := l.Status(); status.Err != nil || status.Synced.IsZero() || status.Syncing {
+		t.Errorf("status = %+v", status)
+	}
+}
+
+func TestSchedulerConflict(t *testing.T) {
+	ctx := context.Background()
+	remote := &memory{resolver: resolver{union}}
+	laptop := &replica{[]byte("a\n")}
+	s := NewScheduler(remote, 0, laptop.local, laptop.apply)
+	statuses := []Status{}
+	s.OnStatus = func(status Status) {
+		statuses = append(statuses, status)
+	}
+
+	remote.race = []byte("b\n")
+	if err := s.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if string(remote.doc.Data) != "a\nb\n" || string(laptop.data) != "a\nb\n" {
+		t.Errorf("remote = %q, laptop = %q, expected the concurrent change merged", remote.doc.Data, laptop.data)
+	}
+	if len(statuses) != 2 || !statuses[0].Syncing || statuses[1].Syncing {
+		t.Errorf("statuses = %+v, expected syncing then synced", statuses)
+	}
+}
+
+// davServer serves a single resource, honouring conditional requests.
+type davServer struct {
+	mu   sync.Mutex
+	data []byte
+	auth string
+}
+
+func (s *davServer) etag() string {
+	if s.data == nil {
+		return ""
+	}
+	return fmt.Sprintf("%q", fmt.Sprintf("%x", sha256.Sum256(s.data))[:16])
+}
+
+func (s *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Header.Get("Authorization") != s.auth {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if s.data == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", s.etag())
+		_, _ = w.Write(s.data)
+	case http.MethodPut:
+		match := r.Header.Get("If-Match")
+		if (match != "" && match != s.etag()) || (r.Header.Get("If-None-Match") == "*" && s.data != nil) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		s.data, _ = io.ReadAll(r.Body)
+		w.Header().Set("ETag", s.etag())
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	t.Setenv(TokenEnv, "secret")
+	t.Setenv(PasswordEnv, "password")
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("me", "password")
+
+	for name, test := range map[string]struct {
+		auth string
+		open func(url string) Provider
+	}{
+		"http":   {"Bearer secret", func(url string) Provider { return NewHTTP(url, union) }},
+		"webdav": {req.Header.Get("Authorization"), func(url string) Provider { return NewWebDAV(url, "me", union) }},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			srv := httptest.NewServer(&davServer{auth: test.auth})
+			defer srv.Close()
+			p := test.open(srv.URL + "/clocker.yaml")
+
+			doc, err := p.Pull(ctx)
+			if err != nil || doc.Revision != "" || len(doc.Data) != 0 {
+				t.Fatalf("Pull() = %+v, %v, expected no copy yet", doc, err)
+			}
+			first, err := p.Push(ctx, Document{Data: []byte("a\n")})
+			if err != nil || first.Revision == "" {
+				t.Fatalf("Push() = %+v, %v", first, err)
+			}
+			_, err = p.Push(ctx, Document{Data: []byte("b\n")})
+			if err != ErrConflict {
+				t.Errorf("creating again returned %v, expected a conflict", err)
+			}
+			second, err := p.Push(ctx, Document{Data: []byte("a\nb\n"), Revision: first.Revision})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = p.Push(ctx, Document{Data: []byte("c\n"), Revision: first.Revision})
+			if err != ErrConflict {
+				t.Errorf("pushing a stale revision returned %v, expected a conflict", err)
+			}
+			doc, err = p.Pull(ctx)
+			if err != nil || doc.Revision != second.Revision || string(doc.Data) != "a\nb\n" {
+				t.Errorf("Pull() = %+v, %v", doc, err)
+			}
+		})
+	}
+}
+
+func TestHTTPUnauthorized(t *testing.T) {
+	srv := httptest.NewServer(&davServer{auth: "Bearer secret"})
+	defer srv.Close()
+	_, err := NewHTTP(srv.URL, union).Pull(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Pull() = %v, expected the status", err)
+	}
+}
+
+func gitRepo(t *testing.T, bare, dir string) {
+	t.Helper()
+	out, err := exec.Command("git", "init", "-q", "--bare", bare).CombinedOutput()
+	if err == nil {
+		out, err = exec.Command("git", "init", "-q", dir).CombinedOutput()
+	}
+	if err == nil {
+		out, err = exec.Command("git", "-C", dir, "remote", "add", "origin", bare).CombinedOutput()
+	}
+	if err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+}
+
+func TestGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, key := range []string{"AUTHOR", "COMMITTER"} {
+		t.Setenv("GIT_"+key+"_NAME", "Clocker")
+		t.Setenv("GIT_"+key+"_EMAIL", "clocker@example.com")
+	}
+	ctx := context.Background()
+	root := t.TempDir()
+	bare := filepath.Join(root, "remote.git")
+	laptop, desktop := filepath.Join(root, "laptop"), filepath.Join(root, "desktop")
+	gitRepo(t, bare, laptop)
+	gitRepo(t, filepath.Join(root, "unused.git"), desktop)
+	_ = exec.Command("git", "-C", desktop, "remote", "set-url", "origin", bare).Run()
+
+	l := NewGit(GitOptions{Dir: laptop}, union)
+	d := NewGit(GitOptions{Dir: desktop}, union)
+
+	doc, err := l.Pull(ctx)
+	if err != nil || doc.Revision != "" {
+		t.Fatalf("Pull() = %+v, %v, expected no branch yet", doc, err)
+	}
+	first, err := l.Push(ctx, Document{Data: []byte("a")})
+	if err != nil || first.Revision == "" {
+		t.Fatalf("Push() = %+v, %v", first, err)
+	}
+
+	doc, err = d.Pull(ctx)
+	if err != nil || doc.Revision != first.Revision || string(doc.Data) != "a" {
+		t.Fatalf("Pull() = %+v, %v, expected the pushed copy as is", doc, err)
+	}
+	merged, err := d.Resolve(ctx, Document{Data: []byte("b")}, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := d.Push(ctx, merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = l.Push(ctx, Document{Data: []byte("c"), Revision: first.Revision})
+	if err != ErrConflict {
+		t.Errorf("pushing on a stale commit returned %v, expected a conflict", err)
+	}
+	doc, err = l.Pull(ctx)
+	if err != nil || doc.Revision != second.Revision || string(doc.Data) != "a\nb\n" {
+		t.Errorf("Pull() = %+v, %v", doc, err)
+	}
+}
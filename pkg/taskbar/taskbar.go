@@ -0,0 +1,32 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package taskbar reflects the tracking state on the Windows taskbar
+// button of the application window.
+package taskbar
+
+import (
+	"errors"
+)
+
+type State int
+
+// Progress states, matching the TBPFLAG values of ITaskbarList3.
+const (
+	NoProgress    State = 0x0
+	Indeterminate State = 0x1
+	Normal        State = 0x2
+	Error         State = 0x4
+	Paused        State = 0x8
+)
+
+var ErrUnsupported = errors.New("taskbar progress is only supported on Windows")
+
+// Taskbar drives the progress indicator of a window taskbar button.
+type Taskbar interface {
+	SetProgress(state State, completed, total uint64) error
+	Close()
+}
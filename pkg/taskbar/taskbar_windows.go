@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package taskbar
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	coinitApartmentThreaded = 0x2
+	clsctxInprocServer      = 0x1
+
+	// ITaskbarList3 vtable indexes
+	vtblRelease          = 2
+	vtblHrInit           = 3
+	vtblSetProgressValue = 9
+	vtblSetProgressState = 10
+	vtblSize             = 21
+)
+
+var (
+	ole32                = syscall.NewLazyDLL("ole32.dll")
+	procCoInitializeEx   = ole32.NewProc("CoInitializeEx")
+	procCoUninitialize   = ole32.NewProc("CoUninitialize")
+	procCoCreateInstance = ole32.NewProc("CoCreateInstance")
+
+	clsidTaskbarList = syscall.GUID{Data1: 0x56FDF344, Data2: 0xFD6D, Data3: 0x11D0, Data4: [8]byte{0x95, 0x8A, 0x00, 0x60, 0x97, 0xC9, 0xA0, 0x90}}
+	iidITaskbarList3 = syscall.GUID{Data1: 0xEA1AFB91, Data2: 0x9E28, Data3: 0x4B86, Data4: [8]byte{0x90, 0xE9, 0x9E, 0x9F, 0x8A, 0x5E, 0xEF, 0xAF}}
+)
+
+// comTaskbar owns an ITaskbarList3 instance. COM objects are bound to the
+// apartment they were created in, so all calls go through a goroutine
+// locked to its own OS thread.
+type comTaskbar struct {
+	hwnd  uintptr
+	calls chan func(obj *taskbarList)
+	done  chan struct{}
+}
+
+type taskbarList struct {
+	vtbl *[vtblSize]uintptr
+}
+
+func (obj *taskbarList) call(idx int, args ...uintptr) uintptr {
+	r, _, _ := syscall.SyscallN(obj.vtbl[idx], append([]uintptr{uintptr(unsafe.Pointer(obj))}, args...)...)
+	return r
+}
+
+func hresult(r uintptr, call string) error {
+	if int32(r) < 0 {
+		return fmt.Errorf("%s failed: HRESULT 0x%08X", call, uint32(r))
+	}
+	return nil
+}
+
+func New(hwnd uintptr) (Taskbar, error) {
+	t := &comTaskbar{
+		hwnd:  hwnd,
+		calls: make(chan func(obj *taskbarList)),
+		done:  make(chan struct{}),
+	}
+
+	ready := make(chan error)
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		r, _, _ := procCoInitializeEx.Call(0, coinitApartmentThreaded)
+		err := hresult(r, "CoInitializeEx")
+		if err != nil {
+			ready <- err
+			return
+		}
+		defer procCoUninitialize.Call()
+
+		var obj *taskbarList
+		r, _, _ = procCoCreateInstance.Call(
+			uintptr(unsafe.Pointer(&clsidTaskbarList)), 0, clsctxInprocServer,
+			uintptr(unsafe.Pointer(&iidITaskbarList3)), uintptr(unsafe.Pointer(&obj)))
+		err = hresult(r, "CoCreateInstance")
+		if err != nil {
+			ready <- err
+			return
+		}
+		defer obj.call(vtblRelease)
+
+		err = hresult(obj.call(vtblHrInit), "ITaskbarList3.HrInit")
+		ready <- err
+		if err != nil {
+			return
+		}
+
+		for {
+			select {
+			case call := <-t.calls:
+				call(obj)
+			case <-t.done:
+				return
+			}
+		}
+	}()
+
+	err := <-ready
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *comTaskbar) SetProgress(state State, completed, total uint64) error {
+	result := make(chan error)
+	t.calls <- func(obj *taskbarList) {
+		r := obj.call(vtblSetProgressState, t.hwnd, uintptr(state))
+		err := hresult(r, "ITaskbarList3.SetProgressState")
+		if err == nil && state != NoProgress && state != Indeterminate {
+			args := []uintptr{t.hwnd}
+			if unsafe.Sizeof(uintptr(0)) == 4 {
+				// ULONGLONG parameters span two stack slots on 32-bit
+				args = append(args, uintptr(completed), uintptr(completed>>32), uintptr(total), uintptr(total>>32))
+			} else {
+				args = append(args, uintptr(completed), uintptr(total))
+			}
+			r = obj.call(vtblSetProgressValue, args...)
+			err = hresult(r, "ITaskbarList3.SetProgressValue")
+		}
+		result <- err
+	}
+	return <-result
+}
+
+func (t *comTaskbar) Close() {
+	close(t.done)
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package tray
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+const (
+	IconSize = 64
+
+	// ring geometry, relative to the icon size
+	iconRadius    = 0.46
+	iconThickness = 0.16
+	// samples per pixel side, for anti-aliasing
+	iconSamples = 4
+)
+
+var iconTrack = color.NRGBA{R: 0x9e, G: 0x9e, B: 0x9e, A: 0x80}
+
+// Icon renders a ring filling clockwise from 12 o'clock as progress goes
+// from 0 to 1, as a PNG image.
+func Icon(progress float64, fill color.Color) []byte {
+	progress = min(max(progress, 0), 1)
+	fr, fg, fb, fa := fill.RGBA()
+	tr, tg, tb, ta := iconTrack.RGBA()
+
+	img := image.NewNRGBA(image.Rect(0, 0, IconSize, IconSize))
+	center := float64(IconSize) / 2
+	outer := iconRadius * IconSize
+	inner := outer - iconThickness*IconSize
+
+	for y := 0; y < IconSize; y++ {
+		for x := 0; x < IconSize; x++ {
+			var r, g, b, a float64
+			for sy := 0; sy < iconSamples; sy++ {
+				for sx := 0; sx < iconSamples; sx++ {
+					dx := float64(x) + (float64(sx)+0.5)/iconSamples - center
+					dy := float64(y) + (float64(sy)+0.5)/iconSamples - center
+					d := math.Hypot(dx, dy)
+					if d < inner || d > outer {
+						continue
+					}
+					// angle from 12 o'clock, clockwise
+					angle := math.Atan2(dx, -dy)
+					if angle < 0 {
+						angle += 2 * math.Pi
+					}
+					if angle/(2*math.Pi) < progress {
+						r, g, b, a = r+float64(fr), g+float64(fg), b+float64(fb), a+float64(fa)
+					} else {
+						r, g, b, a = r+float64(tr), g+float64(tg), b+float64(tb), a+float64(ta)
+					}
+				}
+			}
+			if a == 0 {
+				continue
+			}
+			// premultiplied sums back to a straight color
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / a * 0xff),
+				G: uint8(g / a * 0xff),
+				B: uint8(b / a * 0xff),
+				A: uint8(a / (iconSamples * iconSamples) / 0x101),
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
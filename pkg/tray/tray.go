@@ -0,0 +1,27 @@
+//go:build !js && !wasip1 && !android && !ios
+
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package tray exposes the system tray features Fyne doesn't wrap, on top
+// of the status item it manages.
+package tray
+
+import (
+	"fyne.io/systray"
+)
+
+// SetTitle sets the text shown next to the tray icon. It is displayed in
+// the macOS menu bar, and as the StatusNotifierItem title on Linux.
+func SetTitle(title string) {
+	systray.SetTitle(title)
+}
+
+// SetTooltip sets the text shown when hovering the tray icon, exposed as
+// the StatusNotifierItem tooltip on Linux.
+func SetTooltip(tooltip string) {
+	systray.SetTooltip(tooltip)
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) Benjamin Zores
+ * Apache License, Version 2.0 (see LICENSE or https://www.apache.org/licenses/LICENSE-2.0.txt)
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package uuid
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		u := New()
+		if !Valid(u) {
+			t.Fatalf("New() = %q, not a valid UUID", u)
+		}
+		if u[14] != '4' {
+			t.Errorf("New() = %q, expected version 4", u)
+		}
+		if v := u[19]; v != '8' && v != '9' && v != 'a' && v != 'b' {
+			t.Errorf("New() = %q, expected RFC 4122 variant", u)
+		}
+		if seen[u] {
+			t.Fatalf("New() returned %q twice", u)
+		}
+		seen[u] = true
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		s     string
+		valid bool
+	}{
+		{"6ba7b810-9dad-41d1-80b4-00c04fd430c8", true},
+		{"6BA7B810-9DAD-41D1-80B4-00C04FD430C8", true},
+		{"", false},
+		{"6ba7b810-9dad-41d1-80b4-00c04fd430c", false},
+		{"6ba7b810-9dad-41d1-80b4-00c04fd430c8a", false},
+		{"6ba7b8109dad-41d1-80b4-00c04fd430c8a", false},
+		{"6ba7b810-9dad-41d1-80b4-00c04fd430cg", false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.s); got != tt.valid {
+			t.Errorf("Valid(%q) = %v, expected %v", tt.s, got, tt.valid)
+		}
+	}
+}